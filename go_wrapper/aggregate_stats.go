@@ -0,0 +1,180 @@
+package main
+
+import (
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// AggregateStatsConfig controls the per-player combat log rollup collector.
+type AggregateStatsConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// PlayerAggregate is one hero's rollup across the whole replay. CombatLog
+// entries identify participants by hero/unit name rather than player_id, so
+// that's what we key on (matches AttackerName/TargetName on CombatLogEntry).
+type PlayerAggregate struct {
+	HeroName       string           `json:"hero_name"`
+	DamageDealt    int64            `json:"damage_dealt"`
+	DamageTaken    int64            `json:"damage_taken"`
+	DamageByTarget map[string]int64 `json:"damage_by_target"` // target hero name -> damage
+	HealingDone    int64            `json:"healing_done"`
+	Kills          int              `json:"kills"`
+	Deaths         int              `json:"deaths"`
+	Assists        int              `json:"assists"`
+	GoldGained     int64            `json:"gold_gained"`
+	XPGained       int64            `json:"xp_gained"`
+	AbilityCasts   int              `json:"ability_casts"`
+	Purchases      []ItemPurchase   `json:"purchases"`
+}
+
+// ItemPurchase is a single PURCHASE combat log entry tied to a hero.
+type ItemPurchase struct {
+	Tick     uint32  `json:"tick"`
+	GameTime float32 `json:"game_time"`
+	ItemName string  `json:"item_name"`
+}
+
+// AggregateStatsResult is the ParseResult payload for the aggregate stats
+// collector: per-hero rollups plus a hero-vs-hero damage matrix.
+type AggregateStatsResult struct {
+	PlayerAggregates map[string]*PlayerAggregate `json:"player_aggregates"` // keyed by hero name, e.g. "npc_dota_hero_axe"
+	MatchupMatrix    map[string]map[string]int64 `json:"matchup_matrix"`    // [attacker_hero][target_hero] -> damage
+	Success          bool                        `json:"success"`
+	Error            string                      `json:"error,omitempty"`
+}
+
+func newPlayerAggregate(heroName string) *PlayerAggregate {
+	return &PlayerAggregate{
+		HeroName:       heroName,
+		DamageByTarget: make(map[string]int64),
+		Purchases:      make([]ItemPurchase, 0),
+	}
+}
+
+// aggregateStatsCollector accumulates AggregateStatsResult across
+// OnCMsgDOTACombatLogEntry callbacks registered by RunParse.
+type aggregateStatsCollector struct {
+	result        *AggregateStatsResult
+	gameStartTick uint32
+}
+
+func newAggregateStatsCollector() *aggregateStatsCollector {
+	return &aggregateStatsCollector{
+		result: &AggregateStatsResult{
+			PlayerAggregates: make(map[string]*PlayerAggregate),
+			MatchupMatrix:    make(map[string]map[string]int64),
+			Success:          true,
+		},
+	}
+}
+
+// heroAgg returns (creating if necessary) the aggregate for heroName, or nil
+// if heroName is blank (e.g. world/neutral sources).
+func (c *aggregateStatsCollector) heroAgg(heroName string) *PlayerAggregate {
+	if heroName == "" {
+		return nil
+	}
+	agg, ok := c.result.PlayerAggregates[heroName]
+	if !ok {
+		agg = newPlayerAggregate(heroName)
+		c.result.PlayerAggregates[heroName] = agg
+	}
+	return agg
+}
+
+func (c *aggregateStatsCollector) addMatchup(attackerHero, targetHero string, damage int64) {
+	if attackerHero == "" || targetHero == "" {
+		return
+	}
+	row, ok := c.result.MatchupMatrix[attackerHero]
+	if !ok {
+		row = make(map[string]int64)
+		c.result.MatchupMatrix[attackerHero] = row
+	}
+	row[targetHero] += damage
+}
+
+// onCombatLogEntry consumes one combat log entry, following the same
+// DOTA_COMBATLOG_TYPES_* switch used by RunCombatLogParse.
+func (c *aggregateStatsCollector) onCombatLogEntry(parser *manta.Parser, m *dota.CMsgDOTACombatLogEntry) {
+	// AttackerName/TargetName are CombatLogNames string table indices, not
+	// resolved strings - same resolution data_parser.go/combat_log_iterator.go
+	// already do for InflictorName/Value.
+	attacker, _ := parser.LookupStringByIndex("CombatLogNames", int32(m.GetAttackerName()))
+	target, _ := parser.LookupStringByIndex("CombatLogNames", int32(m.GetTargetName()))
+
+	switch m.GetType() {
+	case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_DAMAGE:
+		dmg := int64(m.GetValue())
+		if agg := c.heroAgg(attacker); agg != nil {
+			agg.DamageDealt += dmg
+			agg.DamageByTarget[target] += dmg
+		}
+		if agg := c.heroAgg(target); agg != nil {
+			agg.DamageTaken += dmg
+		}
+		c.addMatchup(attacker, target, dmg)
+
+	case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_HEAL:
+		if agg := c.heroAgg(attacker); agg != nil {
+			agg.HealingDone += int64(m.GetValue())
+		}
+
+	case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_DEATH:
+		if agg := c.heroAgg(target); agg != nil {
+			agg.Deaths++
+		}
+		if agg := c.heroAgg(attacker); agg != nil {
+			agg.Kills++
+		}
+		for _, assistName := range assistHeroNames(parser, m) {
+			if agg := c.heroAgg(assistName); agg != nil {
+				agg.Assists++
+			}
+		}
+
+	case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_GOLD:
+		if agg := c.heroAgg(target); agg != nil {
+			agg.GoldGained += int64(m.GetValue())
+		}
+
+	case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_XP:
+		if agg := c.heroAgg(target); agg != nil {
+			agg.XPGained += int64(m.GetValue())
+		}
+
+	case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_PURCHASE:
+		if agg := c.heroAgg(target); agg != nil {
+			itemName, _ := parser.LookupStringByIndex("CombatLogNames", int32(m.GetValue()))
+			agg.Purchases = append(agg.Purchases, ItemPurchase{
+				Tick:     parser.Tick,
+				GameTime: TickToGameTime(parser.Tick, c.gameStartTick),
+				ItemName: itemName,
+			})
+		}
+
+	case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_ABILITY:
+		if agg := c.heroAgg(attacker); agg != nil {
+			agg.AbilityCasts++
+		}
+
+	case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_GAME_STATE:
+		if m.GetValue() == 5 && c.gameStartTick == 0 { // DOTA_GAMERULES_STATE_GAME_IN_PROGRESS
+			c.gameStartTick = parser.Tick
+		}
+	}
+}
+
+// assistHeroNames resolves the DEATH entry's assist player slots to hero
+// names via the same CombatLogNames string table PURCHASE/modifier fields
+// use, since assists are recorded as player indices rather than names.
+func assistHeroNames(parser *manta.Parser, m *dota.CMsgDOTACombatLogEntry) []string {
+	names := make([]string, 0, 4)
+	for _, v := range m.GetAssistPlayers() {
+		if name, ok := parser.LookupStringByIndex("CombatLogNames", v); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
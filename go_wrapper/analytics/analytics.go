@@ -0,0 +1,233 @@
+// Package analytics maintains rolling per-hero timeseries (gold, XP, KDA,
+// last hits/denies, damage dealt/taken, position) as a replay is parsed, and
+// exposes a query API over them - HeroSeries(steamID).Between(t1,
+// t2).GPM(), Heatmap(steamID, cellSize) - instead of making a caller diff
+// EntityParseResult snapshots by hand to get the same numbers.
+//
+// Aggregator has no dependency on github.com/dotabuff/manta or cgo; it's
+// fed via Observe/RecordDamage by whatever is walking the replay (see
+// go_wrapper/analytics_export.go's RunAnalytics), the same separation
+// go_wrapper/filter and go_wrapper/marshal already keep from the parsing
+// code that calls them.
+package analytics
+
+import "sort"
+
+// HeroSeriesPoint is one sampled tick of a hero's state.
+type HeroSeriesPoint struct {
+	Tick        uint32
+	GameTime    float32
+	Gold        int
+	XP          int
+	Kills       int
+	Deaths      int
+	Assists     int
+	LastHits    int
+	Denies      int
+	DamageDealt int
+	DamageTaken int
+	X           float32
+	Y           float32
+}
+
+// HeroSeries is one hero's full recorded timeline.
+type HeroSeries struct {
+	SteamID  uint64
+	HeroName string
+	Points   []HeroSeriesPoint
+}
+
+// Aggregator accumulates HeroSeries across a replay, keyed by steam ID.
+type Aggregator struct {
+	series map[uint64]*HeroSeries
+}
+
+// NewAggregator returns an empty Aggregator ready for Observe/RecordDamage.
+func NewAggregator() *Aggregator {
+	return &Aggregator{series: make(map[uint64]*HeroSeries)}
+}
+
+// Observe appends a sampled point to steamID's series, creating it (with
+// heroName) on first use.
+func (a *Aggregator) Observe(steamID uint64, heroName string, point HeroSeriesPoint) {
+	series, ok := a.series[steamID]
+	if !ok {
+		series = &HeroSeries{SteamID: steamID, HeroName: heroName}
+		a.series[steamID] = series
+	}
+	series.Points = append(series.Points, point)
+}
+
+// RecordDamage adds dealt/taken damage to steamID's most recent point -
+// meant to be called from a combat log callback between Observe samples, so
+// damage accrues onto whichever sample window it landed in rather than
+// needing its own timeline.
+func (a *Aggregator) RecordDamage(steamID uint64, dealt, taken int) {
+	series, ok := a.series[steamID]
+	if !ok || len(series.Points) == 0 {
+		return
+	}
+	last := &series.Points[len(series.Points)-1]
+	last.DamageDealt += dealt
+	last.DamageTaken += taken
+}
+
+// HeroSeries returns a query over steamID's recorded points, or an empty
+// query if nothing was ever observed for it.
+func (a *Aggregator) HeroSeries(steamID uint64) *SeriesQuery {
+	series := a.series[steamID]
+	if series == nil {
+		return &SeriesQuery{}
+	}
+	return &SeriesQuery{heroName: series.HeroName, points: series.Points}
+}
+
+// SeriesQuery is a (possibly time-sliced) view over one hero's points.
+type SeriesQuery struct {
+	heroName string
+	points   []HeroSeriesPoint
+}
+
+// Between returns a query restricted to points with t1 <= GameTime <= t2.
+func (q *SeriesQuery) Between(t1, t2 float32) *SeriesQuery {
+	filtered := make([]HeroSeriesPoint, 0, len(q.points))
+	for _, p := range q.points {
+		if p.GameTime >= t1 && p.GameTime <= t2 {
+			filtered = append(filtered, p)
+		}
+	}
+	return &SeriesQuery{heroName: q.heroName, points: filtered}
+}
+
+// Points returns the query's underlying points.
+func (q *SeriesQuery) Points() []HeroSeriesPoint {
+	return q.points
+}
+
+// HeroName returns the hero name Observe first recorded for this query's
+// steam ID, or "" if the query is empty.
+func (q *SeriesQuery) HeroName() string {
+	return q.heroName
+}
+
+// durationMinutes is the query's span, from its first to its last point's
+// GameTime, used as GPM/XPM's denominator.
+func (q *SeriesQuery) durationMinutes() float32 {
+	if len(q.points) < 2 {
+		return 0
+	}
+	span := q.points[len(q.points)-1].GameTime - q.points[0].GameTime
+	if span <= 0 {
+		return 0
+	}
+	return span / 60.0
+}
+
+// GPM is the query's gold-per-minute: the gold gained across the query's
+// span divided by its duration in minutes.
+func (q *SeriesQuery) GPM() float32 {
+	minutes := q.durationMinutes()
+	if minutes == 0 {
+		return 0
+	}
+	gained := q.points[len(q.points)-1].Gold - q.points[0].Gold
+	return float32(gained) / minutes
+}
+
+// XPM is GPM's XP equivalent.
+func (q *SeriesQuery) XPM() float32 {
+	minutes := q.durationMinutes()
+	if minutes == 0 {
+		return 0
+	}
+	gained := q.points[len(q.points)-1].XP - q.points[0].XP
+	return float32(gained) / minutes
+}
+
+// LastHitRate is last hits gained per minute over the query's span.
+func (q *SeriesQuery) LastHitRate() float32 {
+	minutes := q.durationMinutes()
+	if minutes == 0 {
+		return 0
+	}
+	gained := q.points[len(q.points)-1].LastHits - q.points[0].LastHits
+	return float32(gained) / minutes
+}
+
+// TotalDamageDealt sums DamageDealt across the query's points.
+func (q *SeriesQuery) TotalDamageDealt() int {
+	total := 0
+	for _, p := range q.points {
+		total += p.DamageDealt
+	}
+	return total
+}
+
+// TotalDamageTaken sums DamageTaken across the query's points.
+func (q *SeriesQuery) TotalDamageTaken() int {
+	total := 0
+	for _, p := range q.points {
+		total += p.DamageTaken
+	}
+	return total
+}
+
+// HeatmapCell is one populated cell of a Heatmap grid.
+type HeatmapCell struct {
+	GridX int
+	GridY int
+	Count int
+}
+
+// Heatmap bins steamID's recorded positions into cellSize-world-unit grid
+// cells and returns every populated cell, sorted by (GridY, GridX) for
+// deterministic output.
+func (a *Aggregator) Heatmap(steamID uint64, cellSize float32) []HeatmapCell {
+	series := a.series[steamID]
+	if series == nil || cellSize <= 0 {
+		return nil
+	}
+
+	counts := make(map[[2]int]int)
+	for _, p := range series.Points {
+		cell := [2]int{int(p.X / cellSize), int(p.Y / cellSize)}
+		counts[cell]++
+	}
+
+	cells := make([]HeatmapCell, 0, len(counts))
+	for cell, count := range counts {
+		cells = append(cells, HeatmapCell{GridX: cell[0], GridY: cell[1], Count: count})
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].GridY != cells[j].GridY {
+			return cells[i].GridY < cells[j].GridY
+		}
+		return cells[i].GridX < cells[j].GridX
+	})
+	return cells
+}
+
+// RecalculateGameTime overwrites every recorded point's GameTime via fn,
+// mirroring entity_parser.go's post-process step for snapshots captured
+// before gameStartTick was known - gameStartTick is typically only
+// observable once the combat log's GAME_STATE entry or the game rules
+// entity's m_flGameStartTime arrives, which can be after earlier points
+// were already recorded with a placeholder GameTime.
+func (a *Aggregator) RecalculateGameTime(fn func(tick uint32) float32) {
+	for _, series := range a.series {
+		for i := range series.Points {
+			series.Points[i].GameTime = fn(series.Points[i].Tick)
+		}
+	}
+}
+
+// SteamIDs returns every steam ID this Aggregator has recorded a series
+// for, sorted ascending.
+func (a *Aggregator) SteamIDs() []uint64 {
+	ids := make([]uint64, 0, len(a.series))
+	for id := range a.series {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,70 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// ExportCSV writes one row per (steam ID, point) across every hero this
+// Aggregator recorded, to path - the flattened, offline-analysis-friendly
+// shape the request asks for.
+func (a *Aggregator) ExportCSV(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating CSV export: %w", err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	header := []string{
+		"steam_id", "hero_name", "tick", "game_time", "gold", "xp",
+		"kills", "deaths", "assists", "last_hits", "denies",
+		"damage_dealt", "damage_taken", "x", "y",
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, steamID := range a.SteamIDs() {
+		series := a.series[steamID]
+		for _, p := range series.Points {
+			row := []string{
+				strconv.FormatUint(steamID, 10),
+				series.HeroName,
+				strconv.FormatUint(uint64(p.Tick), 10),
+				strconv.FormatFloat(float64(p.GameTime), 'f', -1, 32),
+				strconv.Itoa(p.Gold),
+				strconv.Itoa(p.XP),
+				strconv.Itoa(p.Kills),
+				strconv.Itoa(p.Deaths),
+				strconv.Itoa(p.Assists),
+				strconv.Itoa(p.LastHits),
+				strconv.Itoa(p.Denies),
+				strconv.Itoa(p.DamageDealt),
+				strconv.Itoa(p.DamageTaken),
+				strconv.FormatFloat(float64(p.X), 'f', -1, 32),
+				strconv.FormatFloat(float64(p.Y), 'f', -1, 32),
+			}
+			if err := w.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	return w.Error()
+}
+
+// ExportParquet is not implemented: this tree vendors no Parquet/Arrow
+// encoder (the same gap go_wrapper/entity_parquet_export.go documents for
+// ParseEntitiesToParquet, and go_wrapper/sink.go's NewWriterSink doc
+// comment documents for its own Parquet output path). Returning a clear
+// error here rather than a hand-rolled binary format keeps this consistent
+// with that established choice - pyarrow.parquet.read_table would simply
+// fail to open a format it doesn't recognize, silently defeating the point
+// of asking for Parquet in the first place. Use ExportCSV instead.
+func (a *Aggregator) ExportParquet(path string) error {
+	return fmt.Errorf("ExportParquet is not implemented: this tree does not vendor a Parquet/Arrow encoder; use ExportCSV and convert on the consumer side instead")
+}
@@ -0,0 +1,128 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// BatchParseResult is the per-file result inside a ParseUniversalBatch run.
+type BatchParseResult struct {
+	Path    string                `json:"path"`
+	Result  *UniversalParseResult `json:"result,omitempty"`
+	Success bool                  `json:"success"`
+	Error   string                `json:"error,omitempty"`
+}
+
+// BatchResult is the top-level payload returned by ParseUniversalBatch.
+type BatchResult struct {
+	Results        map[string]*BatchParseResult `json:"results"`
+	PartialSuccess bool                         `json:"partial_success"`
+	Success        bool                         `json:"success"`
+	Error          string                       `json:"error,omitempty"`
+}
+
+//export ParseUniversalBatch
+func ParseUniversalBatch(filePathsJSON *C.char, filter *C.char, maxMessages C.int, workers C.int) *C.char {
+	goFilePathsJSON := C.GoString(filePathsJSON)
+	goFilter := C.GoString(filter)
+	maxMsgs := int(maxMessages)
+	numWorkers := int(workers)
+
+	var paths []string
+	if err := json.Unmarshal([]byte(goFilePathsJSON), &paths); err != nil {
+		return marshalBatchResult(&BatchResult{
+			Success: false,
+			Error:   fmt.Sprintf("invalid file_paths JSON: %v", err),
+		})
+	}
+
+	result := RunUniversalBatch(paths, goFilter, maxMsgs, numWorkers)
+	return marshalBatchResult(result)
+}
+
+// RunUniversalBatch parses every path in paths concurrently across a bounded
+// worker pool (workers, or runtime.NumCPU() if <= 0), merging results into a
+// map keyed by path. One corrupt/missing demo only fails its own entry;
+// the batch overall succeeds with PartialSuccess set when any file errored.
+func RunUniversalBatch(paths []string, filter string, maxMessages, workers int) *BatchResult {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(paths) && len(paths) > 0 {
+		workers = len(paths)
+	}
+
+	result := &BatchResult{
+		Results: make(map[string]*BatchParseResult, len(paths)),
+		Success: true,
+	}
+	if len(paths) == 0 {
+		return result
+	}
+
+	jobs := make(chan string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				entry := parseOneForBatch(path, filter, maxMessages)
+				mu.Lock()
+				result.Results[path] = entry
+				if !entry.Success {
+					result.PartialSuccess = true
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, p := range paths {
+		jobs <- p
+	}
+	close(jobs)
+	wg.Wait()
+
+	return result
+}
+
+// parseOneForBatch isolates a single file's parse so a panic or error in one
+// demo can't take down the rest of the batch.
+func parseOneForBatch(path, filter string, maxMessages int) (entry *BatchParseResult) {
+	entry = &BatchParseResult{Path: path}
+	defer func() {
+		if r := recover(); r != nil {
+			entry.Success = false
+			entry.Error = fmt.Sprintf("panic during parsing: %v", r)
+		}
+	}()
+
+	res, err := RunUniversal(path, filter, maxMessages)
+	if err != nil {
+		entry.Success = false
+		entry.Error = err.Error()
+		return entry
+	}
+	entry.Success = true
+	entry.Result = res
+	return entry
+}
+
+func marshalBatchResult(result *BatchResult) *C.char {
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		fallback := &BatchResult{Success: false, Error: fmt.Sprintf("JSON marshal error: %v", err)}
+		jsonData, _ = json.Marshal(fallback)
+	}
+	return C.CString(string(jsonData))
+}
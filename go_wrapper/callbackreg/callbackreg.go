@@ -0,0 +1,75 @@
+// Package callbackreg installs manta.Parser callbacks by reflecting over
+// the generated Callbacks struct instead of calling each On<Name> method
+// by hand. Every generated callback method has the same shape -
+// func(func(*T) error) where T is the decoded message - so a single
+// reflect.MakeFunc thunk can stand in for any of them. This lets callers
+// register a handler by message name, or for every message name at once,
+// without a dedicated call site per Valve message.
+package callbackreg
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RegisterByName installs fn as the callback for the single message type
+// named by name (e.g. "CDOTAUserMsg_MapLine"), by looking up the
+// On<name> method on callbacks and wrapping it with a thunk that
+// forwards the decoded message to fn. It returns an error if callbacks
+// has no such method, or the method's signature doesn't match the
+// func(*T) error shape every generated callback uses.
+func RegisterByName(callbacks interface{}, name string, fn func(m interface{}) error) error {
+	method := reflect.ValueOf(callbacks).MethodByName("On" + name)
+	if !method.IsValid() {
+		return fmt.Errorf("callbackreg: no callback method On%s", name)
+	}
+	return registerThunk(method, fn)
+}
+
+// RegisterAll installs fn as the callback for every message name
+// callbacks exposes an On<Name> method for, inferring each message's
+// name from the method name. Methods whose signature doesn't match the
+// standard func(*T) error callback shape (manta.Parser's OnEntity and
+// OnPacketTypeName, which take more than one argument, for example) are
+// silently skipped rather than treated as an error, since RegisterAll is
+// meant to sweep up every decodable message in one call.
+func RegisterAll(callbacks interface{}, fn func(name string, m interface{}) error) {
+	v := reflect.ValueOf(callbacks)
+	t := v.Type()
+
+	for i := 0; i < t.NumMethod(); i++ {
+		methodName := t.Method(i).Name
+		if !strings.HasPrefix(methodName, "On") {
+			continue
+		}
+		name := strings.TrimPrefix(methodName, "On")
+		registerThunk(v.Method(i), func(m interface{}) error { return fn(name, m) })
+	}
+}
+
+// registerThunk calls method (an On<Name> registration method) with a
+// reflect.MakeFunc thunk that forwards the decoded message to fn. It
+// returns an error, without calling method, if method doesn't take a
+// single func(*T) error argument.
+func registerThunk(method reflect.Value, fn func(m interface{}) error) error {
+	methodType := method.Type()
+	if methodType.NumIn() != 1 {
+		return fmt.Errorf("callbackreg: callback method has unexpected signature %s", methodType)
+	}
+
+	cbType := methodType.In(0)
+	if cbType.Kind() != reflect.Func || cbType.NumIn() != 1 || cbType.NumOut() != 1 {
+		return fmt.Errorf("callbackreg: callback method has unexpected signature %s", methodType)
+	}
+
+	thunk := reflect.MakeFunc(cbType, func(args []reflect.Value) []reflect.Value {
+		if err := fn(args[0].Interface()); err != nil {
+			return []reflect.Value{reflect.ValueOf(err)}
+		}
+		return []reflect.Value{reflect.Zero(cbType.Out(0))}
+	})
+
+	method.Call([]reflect.Value{thunk})
+	return nil
+}
@@ -1,10 +1,18 @@
 package main
 
+//go:generate go run ./cmd/gencallbacks -out-callbacks callbacks_dota_all.go -out-table dota_user_message_table.go
+
 import (
 	"github.com/dotabuff/manta"
 	"github.com/dotabuff/manta/dota"
 )
 
+// setupDOTAUserCallbacks is still hand-typed as of this comment; run `go
+// generate ./...` (cmd/gencallbacks) to regenerate this file plus the new
+// dota_user_message_table.go from manta.Callbacks' On<Name> method set,
+// rather than hand-adding an arm here the next time a DOTA user message is
+// added. Once regenerated it will carry a "Code generated... DO NOT EDIT"
+// header.
 func setupDOTAUserCallbacks(parser *manta.Parser, messages *[]MessageEvent, filter string, maxMsgs int) {
 	// All 94 missing DOTA User Message callbacks
 	parser.Callbacks.OnCDOTAUserMsg_AbilityDraftRequestAbility(func(m *dota.CDOTAUserMsg_AbilityDraftRequestAbility) error {
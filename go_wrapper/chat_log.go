@@ -0,0 +1,210 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// ChatLogConfig controls ParseChatLog.
+type ChatLogConfig struct {
+	AllChatOnly bool `json:"all_chat_only"` // Drop ally (non-all) chat lines
+
+	// PlayerSlot restricts output to one player slot (0-9). -1 (the
+	// default) means no filter, since 0 is itself a valid slot.
+	PlayerSlot int32 `json:"player_slot"`
+}
+
+// ChatLogEntry is one ParseChatLog line - ChatMessagesEntry's sibling, but
+// resolving HeroName/Team off CDOTA_PlayerResource instead of just carrying
+// a raw player_slot, and additionally covering CDOTAUserMsg_ChatWheel lines
+// alongside SayText2.
+type ChatLogEntry struct {
+	Tick             uint32  `json:"tick"`
+	NetTick          uint32  `json:"net_tick"`
+	GameTime         float32 `json:"game_time"`
+	PlayerSlot       int32   `json:"player_slot"`
+	PlayerName       string  `json:"player_name"`
+	HeroName         string  `json:"hero_name"`
+	Team             int32   `json:"team"`
+	IsAllChat        bool    `json:"is_all_chat"`
+	MessageName      string  `json:"message_name"`
+	Text             string  `json:"text"`
+	ChatWheelId      int32   `json:"chat_wheel_id,omitempty"`
+	ChatWheelMessage string  `json:"chat_wheel_message,omitempty"`
+}
+
+// ChatLogResult is the ParseChatLog response envelope.
+type ChatLogResult struct {
+	Messages []ChatLogEntry `json:"messages"`
+	Success  bool           `json:"success"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// playerSlotInfo is what chatPlayerTracker resolves per slot.
+type playerSlotInfo struct {
+	HeroName string
+	Team     int32
+}
+
+// chatPlayerTracker watches CDOTA_PlayerResource and hero entities so
+// ParseChatLog can correlate a chat line's player_slot with a hero name and
+// team, the same PlayerResource.m_vecPlayerTeamData fields
+// captureAllEntities/extractFullHeroSnapshot read for entity snapshots.
+type chatPlayerTracker struct {
+	slots        [10]playerSlotInfo
+	heroByHandle map[uint64]string
+}
+
+func newChatPlayerTracker() *chatPlayerTracker {
+	return &chatPlayerTracker{heroByHandle: make(map[uint64]string)}
+}
+
+func (t *chatPlayerTracker) Watch(parser *manta.Parser) {
+	parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+		if e == nil {
+			return nil
+		}
+		className := e.GetClassName()
+
+		if strings.Contains(className, "CDOTA_Unit_Hero_") {
+			t.heroByHandle[uint64(e.GetIndex())] = entityClassToHeroName(className)
+			return nil
+		}
+
+		if !strings.Contains(className, "CDOTA_PlayerResource") {
+			return nil
+		}
+
+		for i := 0; i < 10; i++ {
+			team := int32(2) // Radiant
+			if i >= 5 {
+				team = 3 // Dire
+			}
+			t.slots[i].Team = team
+
+			if handle, ok := e.GetUint64(fmt.Sprintf("m_vecPlayerTeamData.%04d.m_hSelectedHero", i)); ok {
+				if name, ok := t.heroByHandle[handle&0x3FFF]; ok {
+					t.slots[i].HeroName = name
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (t *chatPlayerTracker) Lookup(slot int32) playerSlotInfo {
+	if slot < 0 || int(slot) >= len(t.slots) {
+		return playerSlotInfo{}
+	}
+	return t.slots[slot]
+}
+
+//export ParseChatLog
+func ParseChatLog(filePath *C.char, configJSON *C.char) (cResult *C.char) {
+	goFilePath := C.GoString(filePath)
+	goConfigJSON := C.GoString(configJSON)
+
+	chatLog := &ChatLogResult{Messages: make([]ChatLogEntry, 0)}
+
+	defer func() {
+		if r := recover(); r != nil {
+			chatLog.Success = false
+			chatLog.Error = fmt.Sprintf("panic during parsing: %v", r)
+			cResult = marshalChatLogResult(chatLog)
+		}
+	}()
+
+	config := ChatLogConfig{PlayerSlot: -1}
+	if goConfigJSON != "" {
+		if err := json.Unmarshal([]byte(goConfigJSON), &config); err != nil {
+			chatLog.Error = fmt.Sprintf("invalid config JSON: %v", err)
+			return marshalChatLogResult(chatLog)
+		}
+	}
+
+	file, err := os.Open(goFilePath)
+	if err != nil {
+		chatLog.Error = fmt.Sprintf("error opening file: %v", err)
+		return marshalChatLogResult(chatLog)
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		chatLog.Error = fmt.Sprintf("error creating parser: %v", err)
+		return marshalChatLogResult(chatLog)
+	}
+
+	gameTime := newGameTimeTracker()
+	gameTime.Watch(parser)
+
+	players := newChatPlayerTracker()
+	players.Watch(parser)
+
+	playerNames := make(map[int32]string)
+	parser.Callbacks.OnCDemoFileInfo(func(m *dota.CDemoFileInfo) error {
+		if m.GetGameInfo() == nil || m.GetGameInfo().GetDota() == nil {
+			return nil
+		}
+		for i, p := range m.GetGameInfo().GetDota().GetPlayerInfo() {
+			playerNames[int32(i)] = p.GetPlayerName()
+		}
+		return nil
+	})
+
+	appendEntry := func(slot int32, isAllChat bool, messageName, text string, wheelID int32, wheelMessage string) {
+		if config.AllChatOnly && !isAllChat {
+			return
+		}
+		if config.PlayerSlot >= 0 && slot != config.PlayerSlot {
+			return
+		}
+		info := players.Lookup(slot)
+		entry := ChatLogEntry{
+			Tick: parser.Tick, NetTick: parser.NetTick,
+			PlayerSlot: slot, PlayerName: playerNames[slot], HeroName: info.HeroName, Team: info.Team,
+			IsAllChat: isAllChat, MessageName: messageName, Text: text,
+			ChatWheelId: wheelID, ChatWheelMessage: wheelMessage,
+		}
+		chatLog.Messages = append(chatLog.Messages, entry)
+		idx := len(chatLog.Messages) - 1
+		gameTime.Annotate(func(s gameTimeSnapshot) { chatLog.Messages[idx].GameTime = s.GameTime })
+	}
+
+	parser.Callbacks.OnCUserMessageSayText2(func(m *dota.CUserMessageSayText2) error {
+		appendEntry(m.GetEntityindex(), m.GetChat(), m.GetMessagename(), m.GetParam2(), 0, "")
+		return nil
+	})
+
+	parser.Callbacks.OnCDOTAUserMsg_ChatWheel(func(m *dota.CDOTAUserMsg_ChatWheel) error {
+		wheelID := int32(m.GetChatMessageId())
+		wheelMessage := dota.EDOTA_ChatWheelMessage_name[wheelID]
+		appendEntry(m.GetSubjectPlayerId(), true, "CDOTAUserMsg_ChatWheel", wheelMessage, wheelID, wheelMessage)
+		return nil
+	})
+
+	if err := parser.Start(); err != nil {
+		chatLog.Error = fmt.Sprintf("error parsing file: %v", err)
+		return marshalChatLogResult(chatLog)
+	}
+
+	chatLog.Success = true
+	return marshalChatLogResult(chatLog)
+}
+
+func marshalChatLogResult(r *ChatLogResult) *C.char {
+	data, err := json.Marshal(r)
+	if err != nil {
+		data, _ = json.Marshal(&ChatLogResult{Success: false, Error: err.Error()})
+	}
+	return C.CString(string(data))
+}
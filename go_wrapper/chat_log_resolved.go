@@ -0,0 +1,210 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// GetChatLogConfig controls GetChatLog.
+type GetChatLogConfig struct {
+	AllChatOnly bool `json:"all_chat_only"` // Drop "allies"/"spectator" lines
+
+	// PlayerID restricts output to one player slot (0-9). -1 (the default)
+	// means no filter, since 0 is itself a valid slot.
+	PlayerID int32 `json:"player_id"`
+}
+
+// ChatLogLine is one GetChatLog entry. Unlike ParseChatLog's ChatLogEntry,
+// PlayerName here comes from the live CDOTA_PlayerResource
+// m_vecPlayerData.NNNN.m_iszPlayerName string table rather than
+// CDemoFileInfo's player list, and Channel collapses straight down to the
+// three values a Dota chat line can actually be on, the same
+// "all"/"allies"/"spectator" classification chat_messages.go's
+// channelName helper produces.
+type ChatLogLine struct {
+	Tick        uint32  `json:"tick"`
+	GameTime    float32 `json:"game_time"`
+	PlayerID    int32   `json:"player_id"`
+	PlayerName  string  `json:"player_name"`
+	HeroName    string  `json:"hero_name"`
+	Team        int32   `json:"team"`
+	Channel     string  `json:"channel"`
+	Text        string  `json:"text"`
+	ChatWheelId int32   `json:"chat_wheel_id,omitempty"`
+}
+
+// GetChatLogResult is the GetChatLog response envelope.
+type GetChatLogResult struct {
+	Messages []ChatLogLine `json:"messages"`
+	Success  bool          `json:"success"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// chatResourceTracker resolves a chat line's player slot to a display name,
+// hero name and team straight off CDOTA_PlayerResource, the same
+// m_vecPlayerTeamData.NNNN.m_hSelectedHero handle path
+// chatPlayerTracker/entity_parser.go's snapshot code already reads, plus
+// m_vecPlayerData.NNNN.m_iszPlayerName for the name - in contrast to
+// chatPlayerTracker (chat_log.go) and registerChatMessagesCallbacks
+// (chat_messages.go), which both resolve PlayerName from CDemoFileInfo's
+// static player list instead of the live entity.
+type chatResourceTracker struct {
+	slots        [10]playerSlotInfo
+	names        [10]string
+	heroByHandle map[uint64]string
+}
+
+func newChatResourceTracker() *chatResourceTracker {
+	return &chatResourceTracker{heroByHandle: make(map[uint64]string)}
+}
+
+func (t *chatResourceTracker) Watch(parser *manta.Parser) {
+	parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+		if e == nil {
+			return nil
+		}
+		className := e.GetClassName()
+
+		if strings.Contains(className, "CDOTA_Unit_Hero_") {
+			t.heroByHandle[uint64(e.GetIndex())] = entityClassToHeroName(className)
+			return nil
+		}
+
+		if !strings.Contains(className, "CDOTA_PlayerResource") {
+			return nil
+		}
+
+		for i := 0; i < 10; i++ {
+			team := int32(2) // Radiant
+			if i >= 5 {
+				team = 3 // Dire
+			}
+			t.slots[i].Team = team
+
+			if handle, ok := e.GetUint64(fmt.Sprintf("m_vecPlayerTeamData.%04d.m_hSelectedHero", i)); ok {
+				if name, ok := t.heroByHandle[handle&0x3FFF]; ok {
+					t.slots[i].HeroName = name
+				}
+			}
+			if name, ok := e.GetString(fmt.Sprintf("m_vecPlayerData.%04d.m_iszPlayerName", i)); ok {
+				t.names[i] = name
+			}
+		}
+		return nil
+	})
+}
+
+func (t *chatResourceTracker) Lookup(slot int32) (info playerSlotInfo, name string) {
+	if slot < 0 || int(slot) >= len(t.slots) {
+		return playerSlotInfo{}, ""
+	}
+	return t.slots[slot], t.names[slot]
+}
+
+//export GetChatLog
+func GetChatLog(filePath *C.char, configJSON *C.char) (cResult *C.char) {
+	goFilePath := C.GoString(filePath)
+	goConfigJSON := C.GoString(configJSON)
+
+	chatLog := &GetChatLogResult{Messages: make([]ChatLogLine, 0)}
+
+	defer func() {
+		if r := recover(); r != nil {
+			chatLog.Success = false
+			chatLog.Error = fmt.Sprintf("panic during parsing: %v", r)
+			cResult = marshalGetChatLogResult(chatLog)
+		}
+	}()
+
+	config := GetChatLogConfig{PlayerID: -1}
+	if goConfigJSON != "" {
+		if err := json.Unmarshal([]byte(goConfigJSON), &config); err != nil {
+			chatLog.Error = fmt.Sprintf("invalid config JSON: %v", err)
+			return marshalGetChatLogResult(chatLog)
+		}
+	}
+
+	file, err := os.Open(goFilePath)
+	if err != nil {
+		chatLog.Error = fmt.Sprintf("error opening file: %v", err)
+		return marshalGetChatLogResult(chatLog)
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		chatLog.Error = fmt.Sprintf("error creating parser: %v", err)
+		return marshalGetChatLogResult(chatLog)
+	}
+
+	gameTime := newGameTimeTracker()
+	gameTime.Watch(parser)
+
+	resource := newChatResourceTracker()
+	resource.Watch(parser)
+
+	appendLine := func(slot int32, channel, text string, wheelID int32) {
+		if config.AllChatOnly && channel != "all" {
+			return
+		}
+		if config.PlayerID >= 0 && slot != config.PlayerID {
+			return
+		}
+		info, name := resource.Lookup(slot)
+		line := ChatLogLine{
+			Tick: parser.Tick,
+			PlayerID: slot, PlayerName: name, HeroName: info.HeroName, Team: info.Team,
+			Channel: channel, Text: text, ChatWheelId: wheelID,
+		}
+		chatLog.Messages = append(chatLog.Messages, line)
+		idx := len(chatLog.Messages) - 1
+		gameTime.Annotate(func(s gameTimeSnapshot) { chatLog.Messages[idx].GameTime = s.GameTime })
+	}
+
+	parser.Callbacks.OnCUserMessageSayText2(func(m *dota.CUserMessageSayText2) error {
+		channel := "allies"
+		if m.GetChat() {
+			channel = "all"
+		}
+		appendLine(m.GetEntityindex(), channel, m.GetParam2(), 0)
+		return nil
+	})
+
+	parser.Callbacks.OnCDOTAUserMsg_ChatWheel(func(m *dota.CDOTAUserMsg_ChatWheel) error {
+		wheelID := int32(m.GetChatMessageId())
+		wheelMessage := dota.EDOTA_ChatWheelMessage_name[wheelID]
+		appendLine(m.GetSubjectPlayerId(), "all", wheelMessage, wheelID)
+		return nil
+	})
+
+	parser.Callbacks.OnCDOTAUserMsg_ChatEvent(func(m *dota.CDOTAUserMsg_ChatEvent) error {
+		eventName := dota.DOTA_CHAT_MESSAGE_name[int32(m.GetType())]
+		appendLine(m.GetPlayerId1(), "spectator", eventName, 0)
+		return nil
+	})
+
+	if err := parser.Start(); err != nil {
+		chatLog.Error = fmt.Sprintf("error parsing file: %v", err)
+		return marshalGetChatLogResult(chatLog)
+	}
+
+	chatLog.Success = true
+	return marshalGetChatLogResult(chatLog)
+}
+
+func marshalGetChatLogResult(r *GetChatLogResult) *C.char {
+	data, err := json.Marshal(r)
+	if err != nil {
+		data, _ = json.Marshal(&GetChatLogResult{Success: false, Error: err.Error()})
+	}
+	return C.CString(string(data))
+}
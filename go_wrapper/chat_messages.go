@@ -0,0 +1,415 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// ChatMessagesConfig controls ParseChatMessages. Unlike ParseChat (a fixed,
+// no-config walk of all chat), this is the configurable sibling: callers can
+// narrow down to just all-chat or just ally-chat, drop announcer text, cap
+// the result size, and substring-filter message text.
+type ChatMessagesConfig struct {
+	Filter           string `json:"filter"` // substring match against Message
+	IncludeAllChat   bool   `json:"include_all_chat"`
+	IncludeAllyChat  bool   `json:"include_ally_chat"`
+	IncludeAnnouncer bool   `json:"include_announcer"`
+	MaxMessages      int    `json:"max_messages"` // 0 = unlimited
+
+	// MessageTypes restricts output to the given Kind values (e.g.
+	// "say_text2", "chat_wheel", "chat_event"); empty means no filter, the
+	// same "absent = all" convention HeroesOnly's sibling filters use
+	// elsewhere in this package.
+	MessageTypes []string `json:"message_types"`
+
+	// TeamOnly drops every entry whose ChannelName is "all", keeping only
+	// ally/spectator chat - the same filtering a toxicity/analytics pass
+	// over ally comms would want without also pulling in all-chat banter.
+	TeamOnly bool `json:"team_only"`
+
+	// PlayerSlots restricts output to entries whose PlayerSlot is in this
+	// list; empty means no filter. Entries with no resolvable slot (e.g.
+	// CUserMessageChat, which carries no sender index) are always kept,
+	// since this is an allowlist narrowing by sender rather than a
+	// per-kind filter.
+	PlayerSlots []int32 `json:"player_slots"`
+}
+
+// ChatMessageEntry is one ParseChatMessages entry - richer than ParseChat's
+// ChatMessage in that it carries the resolved PlayerSlot/PlayerName (via
+// CDemoFileInfo's player list, the same dotaInfo.GetPlayerInfo() ParseMatchInfo
+// reads) and the raw message_name/channel from SayText2 rather than just a
+// coarse message_type.
+type ChatMessageEntry struct {
+	Tick        uint32  `json:"tick"`
+	NetTick     uint32  `json:"net_tick"`
+	GameTime    float32 `json:"game_time"`
+	PlayerSlot  int32   `json:"player_slot"`
+	PlayerName  string  `json:"player_name"`
+	HeroName    string  `json:"hero_name,omitempty"`
+	SteamID     uint64  `json:"steam_id,omitempty"`
+	Message     string  `json:"message"`
+	MessageName string  `json:"message_name"`
+	Channel     int32   `json:"channel"`
+	IsAllChat   bool    `json:"is_all_chat"`
+	// Param1/Param3/Param4 are SayText2's other format-string slots - Param2
+	// is the message body (already Message above), Param1 is usually the
+	// sender name the client itself would substitute, Param3/Param4 are
+	// only populated for a handful of localized system messages.
+	Param1 string `json:"param1,omitempty"`
+	Param3 string `json:"param3,omitempty"`
+	Param4 string `json:"param4,omitempty"`
+
+	// Kind distinguishes which callback produced this entry:
+	// "say_text2", "ally_chat" (CUserMessageChat), "chat_wheel", or
+	// "chat_event". ChannelName is Kind/IsAllChat collapsed down to the
+	// three channels a Dota chat line can actually be on.
+	Kind        string `json:"kind"`
+	ChannelName string `json:"channel_name"` // "all", "allies", or "spectator"
+	IsPreGame   bool   `json:"is_pre_game"`
+
+	// ChatWheelId/ChatWheelMessage are only set for Kind=="chat_wheel".
+	ChatWheelId      int32  `json:"chat_wheel_id,omitempty"`
+	ChatWheelMessage string `json:"chat_wheel_message,omitempty"`
+
+	// ChatEventType/ChatEventName are only set for Kind=="chat_event".
+	ChatEventType int32  `json:"chat_event_type,omitempty"`
+	ChatEventName string `json:"chat_event_name,omitempty"`
+}
+
+// ChatMessagesResult is the ParseChatMessages response envelope.
+type ChatMessagesResult struct {
+	Messages []ChatMessageEntry `json:"messages"`
+	Success  bool               `json:"success"`
+	Error    *ErrorInfo         `json:"error,omitempty"`
+}
+
+// ParseChatMessages is the configurable all-chat/ally-chat extractor: it
+// resolves player slots to display names off CDemoFileInfo (like
+// ParseMatchInfo does for the draft), tracks game_time off
+// CDOTAGamerulesProxy (like ParseChat and ParseTimeline), and applies
+// ChatMessagesConfig's channel/substring/count filters before appending
+// each CUserMessageSayText2.
+//
+//export ParseChatMessages
+func ParseChatMessages(filePath *C.char, configJSON *C.char) (result *C.char) {
+	goFilePath := C.GoString(filePath)
+	goConfigJSON := C.GoString(configJSON)
+
+	chatResult := &ChatMessagesResult{Messages: make([]ChatMessageEntry, 0)}
+
+	defer func() {
+		if r := recover(); r != nil {
+			chatResult.Success = false
+			chatResult.Error = simpleErrorInfo(ErrPanic, fmt.Sprintf("panic during parsing: %v", r))
+			result = marshalChatMessagesResult(chatResult)
+		}
+	}()
+
+	config := ChatMessagesConfig{IncludeAllChat: true, IncludeAllyChat: true, IncludeAnnouncer: true}
+	if goConfigJSON != "" {
+		if err := json.Unmarshal([]byte(goConfigJSON), &config); err != nil {
+			chatResult.Error = simpleErrorInfo(ErrIO, fmt.Sprintf("invalid config JSON: %v", err))
+			return marshalChatMessagesResult(chatResult)
+		}
+	}
+
+	parsed, err := RunChatMessagesParse(goFilePath, config)
+	if err != nil {
+		if pe, ok := err.(*ParseError); ok {
+			chatResult.Error = pe.toErrorInfo()
+		} else {
+			chatResult.Error = simpleErrorInfo(ErrIO, err.Error())
+		}
+		return marshalChatMessagesResult(chatResult)
+	}
+
+	return marshalChatMessagesResult(parsed)
+}
+
+// RunChatMessagesParse executes chat message parsing, opening filePath and
+// running a dedicated parser over it. RunAllParse instead calls
+// registerChatMessagesCallbacks directly against its own shared parser.
+// Errors are returned as *ParseError (classifyOpenError/classifyParseError)
+// rather than plain fmt.Errorf so ParseChatMessages keeps the same
+// FILE_NOT_FOUND/NOT_A_DEMO/etc granularity it had before this was factored
+// out of the CGo export.
+func RunChatMessagesParse(filePath string, config ChatMessagesConfig) (*ChatMessagesResult, error) {
+	chatResult := &ChatMessagesResult{Messages: make([]ChatMessageEntry, 0)}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, classifyOpenError(err)
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		return nil, classifyParseError(err)
+	}
+
+	registerChatMessagesCallbacks(parser, config, chatResult)
+
+	if err := parser.Start(); err != nil {
+		return nil, classifyParseError(err)
+	}
+
+	chatResult.Success = true
+	return chatResult, nil
+}
+
+// registerChatMessagesCallbacks wires ChatMessagesConfig's handlers onto
+// parser, appending into result as messages arrive. Split out of
+// RunChatMessagesParse so RunAllParse can register it alongside other
+// subsystems on one shared parser instance instead of each running its own
+// full decode pass.
+func registerChatMessagesCallbacks(parser *manta.Parser, config ChatMessagesConfig, chatResult *ChatMessagesResult) {
+	playerNames := make(map[int32]string)
+	playerSteamIDs := make(map[int32]uint64)
+	heroNames := make(map[int32]string)
+	parser.Callbacks.OnCDemoFileInfo(func(m *dota.CDemoFileInfo) error {
+		if m.GetGameInfo() == nil || m.GetGameInfo().GetDota() == nil {
+			return nil
+		}
+		for i, p := range m.GetGameInfo().GetDota().GetPlayerInfo() {
+			playerNames[int32(i)] = p.GetPlayerName()
+			playerSteamIDs[int32(i)] = p.GetSteamid()
+			heroNames[int32(i)] = p.GetHeroName()
+		}
+		return nil
+	})
+
+	// wantsSlot applies PlayerSlots as an allowlist; entries with no
+	// resolvable slot (CUserMessageChat) are never filtered by it.
+	wantsSlot := func(slot int32, hasSlot bool) bool {
+		if len(config.PlayerSlots) == 0 || !hasSlot {
+			return true
+		}
+		for _, s := range config.PlayerSlots {
+			if s == slot {
+				return true
+			}
+		}
+		return false
+	}
+
+	var gameTime, gameStartTime float32
+	var gameState int32
+	parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+		if e == nil || e.GetClassName() != "CDOTAGamerulesProxy" {
+			return nil
+		}
+		if t, ok := e.GetFloat32("m_pGameRules.m_fGameTime"); ok {
+			gameTime = t
+		}
+		if t, ok := e.GetFloat32("m_pGameRules.m_flGameStartTime"); ok {
+			gameStartTime = t
+		}
+		if s, ok := e.GetInt32("m_pGameRules.m_iGameState"); ok {
+			gameState = s
+		}
+		return nil
+	})
+
+	// isPreGame reports whether gameState is still strategy/picks/loading,
+	// i.e. anything before DOTA_GAMERULES_STATE_GAME_IN_PROGRESS (5) - the
+	// same threshold game_time_tracker.go's gameStateNames map keys "inprogress" at.
+	isPreGame := func() bool { return gameState < 5 }
+
+	wantsKind := func(kind string) bool {
+		if len(config.MessageTypes) == 0 {
+			return true
+		}
+		for _, k := range config.MessageTypes {
+			if k == kind {
+				return true
+			}
+		}
+		return false
+	}
+
+	channelName := func(kind string, isAllChat, isAnnouncer bool) string {
+		switch {
+		case isAnnouncer:
+			return "spectator"
+		case isAllChat:
+			return "all"
+		default:
+			return "allies"
+		}
+	}
+
+	parser.Callbacks.OnCUserMessageSayText2(func(m *dota.CUserMessageSayText2) error {
+		if config.MaxMessages > 0 && len(chatResult.Messages) >= config.MaxMessages {
+			return nil
+		}
+		if !wantsKind("say_text2") {
+			return nil
+		}
+
+		isAllChat := m.GetChat()
+		isAnnouncer := m.GetEntityindex() == 0 && m.GetParam2() != ""
+		if isAllChat && !config.IncludeAllChat {
+			return nil
+		}
+		if !isAllChat && !isAnnouncer && !config.IncludeAllyChat {
+			return nil
+		}
+		if isAnnouncer && !config.IncludeAnnouncer {
+			return nil
+		}
+
+		message := m.GetParam2()
+		if config.Filter != "" && !strings.Contains(message, config.Filter) {
+			return nil
+		}
+
+		slot := m.GetEntityindex()
+		if !wantsSlot(slot, true) {
+			return nil
+		}
+		cn := channelName("say_text2", isAllChat, isAnnouncer)
+		if config.TeamOnly && cn == "all" {
+			return nil
+		}
+		chatResult.Messages = append(chatResult.Messages, ChatMessageEntry{
+			Tick:        parser.Tick,
+			NetTick:     parser.NetTick,
+			GameTime:    gameTime - gameStartTime,
+			PlayerSlot:  slot,
+			PlayerName:  playerNames[slot],
+			HeroName:    heroNames[slot],
+			SteamID:     playerSteamIDs[slot],
+			Message:     message,
+			MessageName: m.GetMessagename(),
+			Channel:     int32(m.GetChatGroupIndex()),
+			IsAllChat:   isAllChat,
+			Param1:      m.GetParam1(),
+			Param3:      m.GetParam3(),
+			Param4:      m.GetParam4(),
+			Kind:        "say_text2",
+			ChannelName: cn,
+			IsPreGame:   isPreGame(),
+		})
+		return nil
+	})
+
+	parser.Callbacks.OnCUserMessageChat(func(m *dota.CUserMessageChat) error {
+		if config.MaxMessages > 0 && len(chatResult.Messages) >= config.MaxMessages {
+			return nil
+		}
+		if !config.IncludeAllyChat {
+			return nil
+		}
+		if !wantsKind("ally_chat") {
+			return nil
+		}
+
+		message := m.GetText()
+		if config.Filter != "" && !strings.Contains(message, config.Filter) {
+			return nil
+		}
+
+		chatResult.Messages = append(chatResult.Messages, ChatMessageEntry{
+			Tick:        parser.Tick,
+			NetTick:     parser.NetTick,
+			GameTime:    gameTime - gameStartTime,
+			MessageName: "CUserMessageChat",
+			Message:     message,
+			Kind:        "ally_chat",
+			ChannelName: "allies",
+			IsPreGame:   isPreGame(),
+		})
+		return nil
+	})
+
+	parser.Callbacks.OnCDOTAUserMsg_ChatWheel(func(m *dota.CDOTAUserMsg_ChatWheel) error {
+		if config.MaxMessages > 0 && len(chatResult.Messages) >= config.MaxMessages {
+			return nil
+		}
+		if !config.IncludeAllChat {
+			return nil
+		}
+		if !wantsKind("chat_wheel") {
+			return nil
+		}
+
+		wheelID := int32(m.GetChatMessageId())
+		wheelMessage := dota.EDOTA_ChatWheelMessage_name[wheelID]
+		slot := m.GetSubjectPlayerId()
+		if !wantsSlot(slot, true) || config.TeamOnly {
+			return nil
+		}
+
+		chatResult.Messages = append(chatResult.Messages, ChatMessageEntry{
+			Tick:             parser.Tick,
+			NetTick:          parser.NetTick,
+			GameTime:         gameTime - gameStartTime,
+			PlayerSlot:       slot,
+			PlayerName:       playerNames[slot],
+			HeroName:         heroNames[slot],
+			SteamID:          playerSteamIDs[slot],
+			Message:          wheelMessage,
+			MessageName:      "CDOTAUserMsg_ChatWheel",
+			IsAllChat:        true,
+			Kind:             "chat_wheel",
+			ChannelName:      "all",
+			IsPreGame:        isPreGame(),
+			ChatWheelId:      wheelID,
+			ChatWheelMessage: wheelMessage,
+		})
+		return nil
+	})
+
+	parser.Callbacks.OnCDOTAUserMsg_ChatEvent(func(m *dota.CDOTAUserMsg_ChatEvent) error {
+		if config.MaxMessages > 0 && len(chatResult.Messages) >= config.MaxMessages {
+			return nil
+		}
+		if !wantsKind("chat_event") {
+			return nil
+		}
+
+		eventType := int32(m.GetType())
+		eventName := dota.DOTA_CHAT_MESSAGE_name[eventType]
+		slot := m.GetPlayerId1()
+		if !wantsSlot(slot, true) {
+			return nil
+		}
+
+		chatResult.Messages = append(chatResult.Messages, ChatMessageEntry{
+			Tick:          parser.Tick,
+			NetTick:       parser.NetTick,
+			GameTime:      gameTime - gameStartTime,
+			PlayerSlot:    slot,
+			PlayerName:    playerNames[slot],
+			HeroName:      heroNames[slot],
+			SteamID:       playerSteamIDs[slot],
+			Message:       eventName,
+			MessageName:   "CDOTAUserMsg_ChatEvent",
+			IsAllChat:     true,
+			Kind:          "chat_event",
+			ChannelName:   "spectator",
+			IsPreGame:     isPreGame(),
+			ChatEventType: eventType,
+			ChatEventName: eventName,
+		})
+		return nil
+	})
+}
+
+func marshalChatMessagesResult(r *ChatMessagesResult) *C.char {
+	data, err := json.Marshal(r)
+	if err != nil {
+		data, _ = json.Marshal(&ChatMessagesResult{Success: false, Error: simpleErrorInfo(ErrIO, err.Error())})
+	}
+	return C.CString(string(data))
+}
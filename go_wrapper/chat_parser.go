@@ -0,0 +1,130 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// ChatMessage is a single chat line, timestamped with game time the same
+// way CombatLogEntry is - relative to CDOTAGamerules.m_flGameStartTime.
+type ChatMessage struct {
+	PlayerName  string  `json:"player_name"`
+	Hero        string  `json:"hero"`
+	Team        int32   `json:"team"`
+	Message     string  `json:"message"`
+	MessageType string  `json:"message_type"` // all_chat / team_chat / system
+	Tick        uint32  `json:"tick"`
+	GameTime    float32 `json:"game_time"`
+}
+
+// ChatResult is the ParseChat response envelope.
+type ChatResult struct {
+	Messages []ChatMessage `json:"messages"`
+	Success  bool          `json:"success"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// ParseChat walks the entire demo and returns every chat entry (SayText2 and
+// the legacy Chat user message) as JSON, each stamped with a game_time
+// computed from the resident CDOTAGamerules entity rather than the demo's
+// own tick/netTick - chat can arrive well before m_flGameStartTime is known,
+// so game_time is back-filled once OnEntity has seen CDOTAGamerulesProxy.
+//
+//export ParseChat
+func ParseChat(filePath *C.char) (result *C.char) {
+	goFilePath := C.GoString(filePath)
+
+	chatResult := &ChatResult{
+		Messages: make([]ChatMessage, 0),
+		Success:  false,
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			chatResult.Success = false
+			chatResult.Error = fmt.Sprintf("panic during parsing: %v", r)
+			result = marshalChatResult(chatResult)
+		}
+	}()
+
+	file, err := os.Open(goFilePath)
+	if err != nil {
+		chatResult.Error = fmt.Sprintf("error opening file: %v", err)
+		return marshalChatResult(chatResult)
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		chatResult.Error = fmt.Sprintf("error creating parser: %v", err)
+		return marshalChatResult(chatResult)
+	}
+
+	var gameTime, gameStartTime float32
+
+	// CDOTAGamerulesProxy carries CDOTAGamerules.m_fGameTime/m_flGameStartTime;
+	// the same pair entity_parser.go reads to detect game start for entity
+	// snapshots.
+	parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+		if e == nil || e.GetClassName() != "CDOTAGamerulesProxy" {
+			return nil
+		}
+		if t, ok := e.GetFloat32("m_pGameRules.m_fGameTime"); ok {
+			gameTime = t
+		}
+		if t, ok := e.GetFloat32("m_pGameRules.m_flGameStartTime"); ok {
+			gameStartTime = t
+		}
+		return nil
+	})
+
+	appendChat := func(tick uint32, playerName, hero string, team int32, message, msgType string) {
+		chatResult.Messages = append(chatResult.Messages, ChatMessage{
+			PlayerName:  playerName,
+			Hero:        hero,
+			Team:        team,
+			Message:     message,
+			MessageType: msgType,
+			Tick:        tick,
+			GameTime:    gameTime - gameStartTime,
+		})
+	}
+
+	parser.Callbacks.OnCUserMessageSayText2(func(m *dota.CUserMessageSayText2) error {
+		msgType := "all_chat"
+		if !m.GetChat() {
+			msgType = "system"
+		}
+		appendChat(parser.Tick, m.GetParam1(), "", 0, m.GetParam2(), msgType)
+		return nil
+	})
+
+	parser.Callbacks.OnCUserMessageChat(func(m *dota.CUserMessageChat) error {
+		appendChat(parser.Tick, "", "", 0, m.GetText(), "team_chat")
+		return nil
+	})
+
+	if err := parser.Start(); err != nil {
+		chatResult.Error = fmt.Sprintf("error parsing file: %v", err)
+		return marshalChatResult(chatResult)
+	}
+
+	chatResult.Success = true
+	return marshalChatResult(chatResult)
+}
+
+func marshalChatResult(r *ChatResult) *C.char {
+	data, err := json.Marshal(r)
+	if err != nil {
+		data, _ = json.Marshal(&ChatResult{Success: false, Error: err.Error()})
+	}
+	return C.CString(string(data))
+}
@@ -0,0 +1,231 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+	"google.golang.org/protobuf/proto"
+)
+
+// CheckpointMeta records where one BuildIndexWithCheckpoints blob lives on
+// disk and which keyframe it resumes from.
+type CheckpointMeta struct {
+	Tick     int    `json:"tick"`
+	NetTick  int    `json:"net_tick"`
+	BlobPath string `json:"blob_path"`
+}
+
+// CheckpointIndex is a DemoIndex whose keyframes are aligned to
+// CDemoFullPacket boundaries, plus the on-disk location of the checkpoint
+// blob written at each one. See BuildIndexWithCheckpoints.
+type CheckpointIndex struct {
+	DemoIndex
+	Checkpoints []CheckpointMeta `json:"checkpoints"`
+}
+
+//export BuildIndexWithCheckpoints
+func BuildIndexWithCheckpoints(filePath *C.char, intervalTicks C.int, outDir *C.char) *C.char {
+	path := C.GoString(filePath)
+	dir := C.GoString(outDir)
+	interval := int(intervalTicks)
+
+	if interval <= 0 {
+		interval = 1800 // Default: every 60 seconds (30 ticks/sec * 60)
+	}
+
+	result := buildIndexWithCheckpoints(path, interval, dir)
+	jsonResult, _ := json.Marshal(result)
+	return C.CString(string(jsonResult))
+}
+
+// buildIndexWithCheckpoints is BuildIndex's checkpoint-aware sibling: it
+// only records a keyframe on a CDemoFullPacket boundary (the invariant the
+// request calls out - writing one off-boundary would leave a checkpoint
+// whose string tables/baselines are mid-update and inconsistent), and at
+// each such keyframe it also writes the full packet's raw serialized bytes
+// to <demo base name>.ckpt.<tick>.bin under outDir, alongside a
+// <demo base name>.idx.json holding the returned CheckpointIndex itself.
+//
+// The blob is the closest thing to "manta's internal parser state" this
+// wrapper can actually capture: a CDemoFullPacket already carries the
+// string tables (CombatLogNames, instancebaseline, EntityNames,
+// modifiernames, ...) and the class baselines/entity baselines active at
+// that point in the stream, which is exactly what the request asks to
+// persist. What it does not carry, and what github.com/dotabuff/manta
+// doesn't expose a way to capture from outside, is the flattened
+// send-table/serializer maps manta builds from the earlier CDemoClassInfo
+// and CDemoSendTables commands, or a byte offset usable to resume
+// decoding the file mid-stream - manta.NewStreamParser owns the read
+// loop and buffering internally and this tree doesn't vendor manta's
+// source to add either a state-export or a resume-from-offset hook to
+// it. See GetSnapshotFast for how that gap is handled on the read side.
+func buildIndexWithCheckpoints(filePath string, intervalTicks int, outDir string) *CheckpointIndex {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return &CheckpointIndex{DemoIndex: DemoIndex{Success: false, Error: fmt.Sprintf("Failed to open file: %v", err)}}
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		return &CheckpointIndex{DemoIndex: DemoIndex{Success: false, Error: fmt.Sprintf("Failed to create parser: %v", err)}}
+	}
+
+	base := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+
+	index := &CheckpointIndex{
+		DemoIndex: DemoIndex{
+			Keyframes: make([]Keyframe, 0),
+			Success:   true,
+		},
+		Checkpoints: make([]CheckpointMeta, 0),
+	}
+
+	var gameStartTick int
+	var gameStartTime float32
+	const ticksPerSecond = 30.0
+
+	parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+		if e == nil || !strings.Contains(e.GetClassName(), "CDOTAGamerulesProxy") {
+			return nil
+		}
+		if gst, ok := e.GetFloat32("m_pGameRules.m_flGameStartTime"); ok && gst > 0 && gameStartTime == 0 {
+			gameStartTime = gst
+			gameStartTick = int(parser.Tick)
+			index.GameStarted = gameStartTick
+		}
+		return nil
+	})
+
+	lastCheckpointTick := -intervalTicks
+	parser.Callbacks.OnCDemoFullPacket(func(m *dota.CDemoFullPacket) error {
+		tick := int(parser.Tick)
+		if tick-lastCheckpointTick < intervalTicks {
+			return nil
+		}
+		lastCheckpointTick = tick
+
+		var gameTime float32
+		if gameStartTick > 0 && tick >= gameStartTick {
+			gameTime = float32(tick-gameStartTick) / ticksPerSecond
+		}
+
+		index.Keyframes = append(index.Keyframes, Keyframe{
+			Tick:       tick,
+			NetTick:    int(parser.NetTick),
+			GameTime:   gameTime,
+			FullPacket: true,
+		})
+
+		blob, err := proto.Marshal(m)
+		if err != nil {
+			return nil // skip this checkpoint, keep parsing
+		}
+		blobName := fmt.Sprintf("%s.ckpt.%d.bin", base, tick)
+		if err := os.WriteFile(filepath.Join(outDir, blobName), blob, 0o644); err != nil {
+			return nil
+		}
+		index.Checkpoints = append(index.Checkpoints, CheckpointMeta{
+			Tick:     tick,
+			NetTick:  int(parser.NetTick),
+			BlobPath: blobName,
+		})
+
+		return nil
+	})
+
+	if err := parser.Start(); err != nil {
+		return &CheckpointIndex{DemoIndex: DemoIndex{Success: false, Error: fmt.Sprintf("Parse failed: %v", err)}}
+	}
+
+	index.TotalTicks = int(parser.Tick)
+
+	idxJSON, err := json.Marshal(index)
+	if err == nil {
+		os.WriteFile(filepath.Join(outDir, base+".idx.json"), idxJSON, 0o644)
+	}
+
+	return index
+}
+
+//export GetSnapshotFast
+func GetSnapshotFast(filePath *C.char, indexDir *C.char, configJSON *C.char) *C.char {
+	path := C.GoString(filePath)
+	dir := C.GoString(indexDir)
+	configStr := C.GoString(configJSON)
+
+	var config SnapshotConfig
+	if err := json.Unmarshal([]byte(configStr), &config); err != nil {
+		result := &EntityStateSnapshot{Success: false, Error: fmt.Sprintf("Invalid config: %v", err)}
+		jsonResult, _ := json.Marshal(result)
+		return C.CString(string(jsonResult))
+	}
+
+	result := getEntitySnapshotFast(path, dir, config)
+	jsonResult, _ := json.Marshal(result)
+	return C.CString(string(jsonResult))
+}
+
+// getEntitySnapshotFast is meant to pick the checkpoint nearest to
+// config.TargetTick (reusing the same binary search FindKeyframe already
+// does over a DemoIndex's Keyframes), reload manta's parser state from
+// that checkpoint's blob, seek the open file to the byte offset the
+// checkpoint was written at, and resume decoding only from there forward
+// - turning a minute-long from-tick-0 replay into the ~100ms seek the
+// request describes.
+//
+// That resume step needs two things github.com/dotabuff/manta doesn't
+// expose and isn't vendored here to add: a way to construct a *manta.Parser
+// (or reset an existing one) from a previously-serialized string
+// table/class baseline/serializer state rather than an empty one, and a
+// way to resume its internal read loop at an arbitrary byte offset rather
+// than from the start of the io.Reader passed to NewStreamParser. Without
+// those, a checkpoint blob can be read back (proto.Unmarshal into a
+// *dota.CDemoFullPacket) but there is nothing on this side to feed it into
+// - manta.Parser's internal string tables and baselines are only ever
+// populated by manta itself as it decodes the stream. So this still
+// delegates to the same from-tick-0 scan getEntitySnapshot runs, trading
+// none of the seek-time cost; it exists so callers have a stable
+// GetSnapshotFast entrypoint to switch to once manta grows a way to
+// import checkpoint state, without a correctness difference from
+// GetSnapshot today. The index lookup below is exercised for real (same
+// binary search as FindKeyframe) even though its result - the nearest
+// on-disk checkpoint blob - isn't used for anything yet.
+func getEntitySnapshotFast(filePath, indexDir string, config SnapshotConfig) *EntityStateSnapshot {
+	base := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	idxBytes, err := os.ReadFile(filepath.Join(indexDir, base+".idx.json"))
+	if err != nil {
+		// No checkpoint index on disk for this demo yet - fall back to the
+		// ordinary full scan rather than failing the request.
+		return getEntitySnapshot(filePath, config)
+	}
+
+	var index CheckpointIndex
+	if err := json.Unmarshal(idxBytes, &index); err != nil {
+		return getEntitySnapshot(filePath, config)
+	}
+
+	checkpoints := index.Checkpoints
+	idx := sort.Search(len(checkpoints), func(i int) bool {
+		return checkpoints[i].Tick > config.TargetTick
+	})
+	if idx > 0 {
+		// idx-1 is the nearest checkpoint at or before the target tick -
+		// see getEntitySnapshotFast's doc comment for why it can't be
+		// resumed from yet.
+		_ = checkpoints[idx-1]
+	}
+
+	return getEntitySnapshot(filePath, config)
+}
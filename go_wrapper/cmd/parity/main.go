@@ -12,8 +12,37 @@ import (
 
 	"github.com/dotabuff/manta"
 	"github.com/dotabuff/manta/dota"
+
+	"manta_wrapper/combatlog"
+	"manta_wrapper/filter"
 )
 
+// messageFilter selects which messages addFilteredMessage keeps - either
+// legacy substring matching against msgType (the --callbacks behavior this
+// tool has always had) or a compiled manta_wrapper/filter predicate
+// evaluated against the full MessageEvent (--filter). At most one of Substr/
+// Expr is set; a zero-value messageFilter matches everything. This reuses
+// the same filter package go_wrapper/sink.go and go_wrapper/filter_parser.go
+// already use for NDJSONSink/RunUniversalExpr, rather than inventing a
+// second predicate language for this tool specifically - cmd/parity is a
+// plain (non-cgo) part of the manta_wrapper module, so importing it here is
+// a normal Go import, not the cross-main-package situation GameContext/
+// OTLPSink hit.
+type messageFilter struct {
+	Substr string
+	Expr   filter.Expr
+}
+
+func (mf messageFilter) match(msgType string, tick, netTick uint32, data interface{}) bool {
+	if mf.Expr != nil {
+		return filter.Eval(mf.Expr, filter.Event{Type: msgType, Tick: tick, NetTick: netTick, Data: data})
+	}
+	if mf.Substr != "" {
+		return strings.Contains(msgType, mf.Substr)
+	}
+	return true
+}
+
 // MessageEvent represents any Manta message with metadata (local copy for standalone tool)
 type MessageEvent struct {
 	Type    string      `json:"type"`
@@ -36,6 +65,42 @@ type parityReport struct {
 	GeneratedAt string                    `json:"generated_at"`
 	Limit       int                       `json:"limit"`
 	Callbacks   map[string]callbackReport `json:"callbacks"`
+	GameContext *GameContext              `json:"game_context,omitempty"`
+}
+
+// gameStateNames maps CDOTAGamerules.m_iGameState (DOTA_GAMERULES_STATE_*)
+// to its short phase name - the same table go_wrapper/game_time_tracker.go
+// keeps; repeated here since cmd/parity is its own `package main` and can't
+// import another main package's symbols.
+var gameStateNames = map[int32]string{
+	0: "init",
+	1: "wait_for_players",
+	2: "hero_selection",
+	3: "strategy_time",
+	4: "pregame",
+	5: "inprogress",
+	6: "postgame",
+	7: "disconnect",
+}
+
+func gameStateName(state int32) string {
+	if name, ok := gameStateNames[state]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown_%d", state)
+}
+
+// GameContext is the game-state snapshot observed as of the last
+// CDOTAGamerulesProxy update seen during a parse - attached to parityReport
+// alongside the per-callback message captures so a reader can tell what
+// part of the match a report's messages came from without separately
+// decoding CDOTAGamerulesProxy themselves.
+type GameContext struct {
+	GameTime  float32 `json:"game_time"`
+	MatchTime float32 `json:"match_time"`
+	GameState int32   `json:"game_state"`
+	GamePhase string  `json:"game_phase"`
+	IsPaused  bool    `json:"is_paused"`
 }
 
 func main() {
@@ -45,13 +110,31 @@ func main() {
 	var callbackCSV string
 	var limit int
 	var outputPath string
+	var exporter string
+	var otlpEndpoint string
+	var otlpHeadersCSV string
+	var otlpCompression string
+	var otlpBatchSize int
+	var combatLogCSVPath string
+	var filterExpr string
 
 	flag.StringVar(&replayPath, "replay", "", "Path to replay (.dem) file")
-	flag.StringVar(&callbackCSV, "callbacks", "CDemoFileHeader,CDOTAUserMsg_ChatMessage,CDOTAUserMsg_LocationPing,CNETMsg_Tick,CSVCMsg_ServerInfo", "Comma-separated list of callbacks to capture")
+	flag.StringVar(&callbackCSV, "callbacks", "CDemoFileHeader,CDOTAUserMsg_ChatMessage,CDOTAUserMsg_LocationPing,CNETMsg_Tick,CSVCMsg_ServerInfo", "Comma-separated list of callbacks to capture; include \"CombatLogEntry\" to capture the decoded combat log stream (see combatlog package) instead of raw CDOTAUserMsg_CombatLogBulkData. Ignored when --filter is set.")
+	flag.StringVar(&filterExpr, "filter", "", "manta_wrapper/filter predicate expression (e.g. 'type == \"CDOTAUserMsg_ChatMessage\" and tick >= 30000') evaluated against every message in a single pass, instead of --callbacks' per-name substring matching")
 	flag.IntVar(&limit, "limit", 10, "Maximum messages per callback (0 for all)")
 	flag.StringVar(&outputPath, "output", "", "Optional output file for JSON report")
+	flag.StringVar(&exporter, "exporter", "json", "Report output mode: \"json\" (default, prints/writes the report) or \"otlp\" (POSTs every captured message as an OTLP log record to --otlp-endpoint)")
+	flag.StringVar(&otlpEndpoint, "otlp-endpoint", "", "OTLP/HTTP logs receiver URL, required when --exporter=otlp")
+	flag.StringVar(&otlpHeadersCSV, "otlp-headers", "", "Comma-separated key=value HTTP headers to send with each OTLP POST (e.g. \"Authorization=Bearer xyz\")")
+	flag.StringVar(&otlpCompression, "otlp-compression", "", "OTLP payload compression: \"\", \"gzip\", or \"snappy\"")
+	flag.IntVar(&otlpBatchSize, "otlp-batch-size", 256, "Max log records per OTLP POST")
+	flag.StringVar(&combatLogCSVPath, "combat-log-csv", "", "Optional path to stream decoded combat log entries to as they arrive, independent of --callbacks/--limit")
 	flag.Parse()
 
+	if exporter != "json" && exporter != "otlp" {
+		log.Fatalf("--exporter must be \"json\" or \"otlp\", got %q", exporter)
+	}
+
 	if replayPath == "" {
 		log.Fatalf("--replay path is required")
 	}
@@ -71,11 +154,26 @@ func main() {
 		log.Fatalf("replay path %s is a directory", replayPath)
 	}
 
+	var compiledFilter filter.Expr
+	if filterExpr != "" {
+		var err error
+		compiledFilter, err = filter.Compile(filterExpr)
+		if err != nil {
+			log.Fatalf("invalid --filter expression: %v", err)
+		}
+	}
+
 	callbackList := parseCallbacks(callbackCSV)
-	if len(callbackList) == 0 {
+	if compiledFilter == nil && len(callbackList) == 0 {
 		log.Fatalf("no callbacks specified")
 	}
 
+	if combatLogCSVPath != "" {
+		if err := streamCombatLogCSV(replayPath, combatLogCSVPath); err != nil {
+			log.Fatalf("failed to stream combat log CSV: %v", err)
+		}
+	}
+
 	report := parityReport{
 		Replay:      replayPath,
 		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
@@ -83,28 +181,73 @@ func main() {
 		Callbacks:   make(map[string]callbackReport),
 	}
 
-	for _, cb := range callbackList {
+	if compiledFilter != nil {
+		// --filter replaces the per-callback-name loop below with a single
+		// pass: one predicate, evaluated against every message type, rather
+		// than one full re-parse per --callbacks entry.
+		const reportKey = "filter"
 		start := time.Now()
-		messages, err := runUniversal(replayPath, cb, limit)
+		messages, gameContext, err := runUniversal(replayPath, messageFilter{Expr: compiledFilter}, limit)
 		if err != nil {
-			report.Callbacks[cb] = callbackReport{
-				Callback:  cb,
+			report.Callbacks[reportKey] = callbackReport{
+				Callback:  filterExpr,
 				Success:   false,
 				Error:     err.Error(),
 				ParseTime: time.Since(start).String(),
 			}
-			continue
+		} else {
+			report.Callbacks[reportKey] = callbackReport{
+				Callback:  filterExpr,
+				Success:   true,
+				Count:     len(messages),
+				ParseTime: time.Since(start).String(),
+				Messages:  messages,
+			}
+			report.GameContext = gameContext
 		}
+	} else {
+		for _, cb := range callbackList {
+			start := time.Now()
+			messages, gameContext, err := runUniversal(replayPath, messageFilter{Substr: cb}, limit)
+			if err != nil {
+				report.Callbacks[cb] = callbackReport{
+					Callback:  cb,
+					Success:   false,
+					Error:     err.Error(),
+					ParseTime: time.Since(start).String(),
+				}
+				continue
+			}
 
-		report.Callbacks[cb] = callbackReport{
-			Callback:  cb,
-			Success:   true,
-			Count:     len(messages),
-			ParseTime: time.Since(start).String(),
-			Messages:  messages,
+			report.Callbacks[cb] = callbackReport{
+				Callback:  cb,
+				Success:   true,
+				Count:     len(messages),
+				ParseTime: time.Since(start).String(),
+				Messages:  messages,
+			}
+			// Every requested callback re-parses the whole replay (see
+			// runUniversal), so each run observes the same full
+			// CDOTAGamerulesProxy history; the last one to finish just
+			// overwrites report.GameContext with the same final snapshot.
+			report.GameContext = gameContext
 		}
 	}
 
+	if exporter == "otlp" {
+		cfg := otlpExporterConfig{
+			Endpoint:    otlpEndpoint,
+			Compression: otlpCompression,
+			Headers:     parseOTLPHeaders(otlpHeadersCSV),
+			BatchSize:   otlpBatchSize,
+		}
+		if err := exportOTLP(report, cfg); err != nil {
+			log.Fatalf("failed to export OTLP logs: %v", err)
+		}
+		fmt.Printf("exported logs for %d callback(s) to %s\n", len(report.Callbacks), otlpEndpoint)
+		return
+	}
+
 	payload, err := json.MarshalIndent(report, "", "  ")
 	if err != nil {
 		log.Fatalf("failed to marshal report: %v", err)
@@ -139,39 +282,82 @@ func parseCallbacks(csv string) []string {
 	return callbacks
 }
 
-// runUniversal parses the replay and captures messages matching the filter
-func runUniversal(filePath string, filter string, maxMessages int) ([]MessageEvent, error) {
+// runUniversal parses the replay and captures messages matching the
+// filter, plus the GameContext observed as of the last CDOTAGamerulesProxy
+// update seen.
+func runUniversal(filePath string, mf messageFilter, maxMessages int) ([]MessageEvent, *GameContext, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("error opening file: %w", err)
+		return nil, nil, fmt.Errorf("error opening file: %w", err)
 	}
 	defer file.Close()
 
 	parser, err := manta.NewStreamParser(file)
 	if err != nil {
-		return nil, fmt.Errorf("error creating parser: %w", err)
+		return nil, nil, fmt.Errorf("error creating parser: %w", err)
 	}
 
 	messages := make([]MessageEvent, 0)
-	setupCallbacks(parser, &messages, filter, maxMessages)
+	gameContext := &GameContext{}
+	setupCallbacks(parser, &messages, mf, maxMessages)
+	setupEntityCallbacks(parser, &messages, gameContext, mf, maxMessages)
+	combatlog.Register(parser, combatlog.StringTableResolver(parser), func(entry *combatlog.CombatLogEntry) error {
+		entry.Tick, entry.NetTick = parser.Tick, parser.NetTick
+		return addFilteredMessage(&messages, "CombatLogEntry", parser.Tick, parser.NetTick, entry, mf, maxMessages)
+	})
 
 	if err := parser.Start(); err != nil {
-		return nil, fmt.Errorf("error parsing file: %w", err)
+		return nil, nil, fmt.Errorf("error parsing file: %w", err)
 	}
 
 	if maxMessages > 0 && len(messages) > maxMessages {
 		messages = messages[:maxMessages]
 	}
 
-	return messages, nil
+	return messages, gameContext, nil
+}
+
+// streamCombatLogCSV runs its own single parser pass over filePath and
+// writes every decoded combat log entry to outPath as a CSV row, as they
+// arrive - independent of --callbacks/--limit, since most analytics
+// pipelines want the full combat log regardless of which other messages a
+// given run happens to be inspecting.
+func streamCombatLogCSV(filePath, outPath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		return fmt.Errorf("error creating parser: %w", err)
+	}
+
+	out, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", outPath, err)
+	}
+	defer out.Close()
+
+	writer := combatlog.NewCSVWriter(out)
+	combatlog.Register(parser, combatlog.StringTableResolver(parser), func(entry *combatlog.CombatLogEntry) error {
+		entry.Tick, entry.NetTick = parser.Tick, parser.NetTick
+		return writer.Write(entry)
+	})
+
+	if err := parser.Start(); err != nil {
+		return fmt.Errorf("error parsing file: %w", err)
+	}
+	return writer.Flush()
 }
 
 // addFilteredMessage adds a message if it passes the filter and limit checks
-func addFilteredMessage(messages *[]MessageEvent, msgType string, tick, netTick uint32, data interface{}, filter string, maxMsgs int) error {
+func addFilteredMessage(messages *[]MessageEvent, msgType string, tick, netTick uint32, data interface{}, mf messageFilter, maxMsgs int) error {
 	if maxMsgs > 0 && len(*messages) >= maxMsgs {
 		return nil
 	}
-	if filter != "" && !strings.Contains(msgType, filter) {
+	if !mf.match(msgType, tick, netTick, data) {
 		return nil
 	}
 	*messages = append(*messages, MessageEvent{
@@ -184,66 +370,134 @@ func addFilteredMessage(messages *[]MessageEvent, msgType string, tick, netTick
 }
 
 // setupCallbacks registers the callbacks needed for parity testing
-func setupCallbacks(parser *manta.Parser, messages *[]MessageEvent, filter string, maxMsgs int) {
+func setupCallbacks(parser *manta.Parser, messages *[]MessageEvent, mf messageFilter, maxMsgs int) {
 	// Demo messages
 	parser.Callbacks.OnCDemoFileHeader(func(m *dota.CDemoFileHeader) error {
-		return addFilteredMessage(messages, "CDemoFileHeader", parser.Tick, parser.NetTick, m, filter, maxMsgs)
+		return addFilteredMessage(messages, "CDemoFileHeader", parser.Tick, parser.NetTick, m, mf, maxMsgs)
 	})
 	parser.Callbacks.OnCDemoFileInfo(func(m *dota.CDemoFileInfo) error {
-		return addFilteredMessage(messages, "CDemoFileInfo", parser.Tick, parser.NetTick, m, filter, maxMsgs)
+		return addFilteredMessage(messages, "CDemoFileInfo", parser.Tick, parser.NetTick, m, mf, maxMsgs)
 	})
 	parser.Callbacks.OnCDemoSyncTick(func(m *dota.CDemoSyncTick) error {
-		return addFilteredMessage(messages, "CDemoSyncTick", parser.Tick, parser.NetTick, m, filter, maxMsgs)
+		return addFilteredMessage(messages, "CDemoSyncTick", parser.Tick, parser.NetTick, m, mf, maxMsgs)
 	})
 	parser.Callbacks.OnCDemoStop(func(m *dota.CDemoStop) error {
-		return addFilteredMessage(messages, "CDemoStop", parser.Tick, parser.NetTick, m, filter, maxMsgs)
+		return addFilteredMessage(messages, "CDemoStop", parser.Tick, parser.NetTick, m, mf, maxMsgs)
 	})
 
 	// Network messages
 	parser.Callbacks.OnCNETMsg_Tick(func(m *dota.CNETMsg_Tick) error {
-		return addFilteredMessage(messages, "CNETMsg_Tick", parser.Tick, parser.NetTick, m, filter, maxMsgs)
+		return addFilteredMessage(messages, "CNETMsg_Tick", parser.Tick, parser.NetTick, m, mf, maxMsgs)
 	})
 	parser.Callbacks.OnCNETMsg_SetConVar(func(m *dota.CNETMsg_SetConVar) error {
-		return addFilteredMessage(messages, "CNETMsg_SetConVar", parser.Tick, parser.NetTick, m, filter, maxMsgs)
+		return addFilteredMessage(messages, "CNETMsg_SetConVar", parser.Tick, parser.NetTick, m, mf, maxMsgs)
 	})
 	parser.Callbacks.OnCNETMsg_SignonState(func(m *dota.CNETMsg_SignonState) error {
-		return addFilteredMessage(messages, "CNETMsg_SignonState", parser.Tick, parser.NetTick, m, filter, maxMsgs)
+		return addFilteredMessage(messages, "CNETMsg_SignonState", parser.Tick, parser.NetTick, m, mf, maxMsgs)
 	})
 
 	// SVC messages
 	parser.Callbacks.OnCSVCMsg_ServerInfo(func(m *dota.CSVCMsg_ServerInfo) error {
-		return addFilteredMessage(messages, "CSVCMsg_ServerInfo", parser.Tick, parser.NetTick, m, filter, maxMsgs)
+		return addFilteredMessage(messages, "CSVCMsg_ServerInfo", parser.Tick, parser.NetTick, m, mf, maxMsgs)
 	})
 	parser.Callbacks.OnCSVCMsg_CreateStringTable(func(m *dota.CSVCMsg_CreateStringTable) error {
-		return addFilteredMessage(messages, "CSVCMsg_CreateStringTable", parser.Tick, parser.NetTick, m, filter, maxMsgs)
+		return addFilteredMessage(messages, "CSVCMsg_CreateStringTable", parser.Tick, parser.NetTick, m, mf, maxMsgs)
 	})
 	parser.Callbacks.OnCSVCMsg_UpdateStringTable(func(m *dota.CSVCMsg_UpdateStringTable) error {
-		return addFilteredMessage(messages, "CSVCMsg_UpdateStringTable", parser.Tick, parser.NetTick, m, filter, maxMsgs)
+		return addFilteredMessage(messages, "CSVCMsg_UpdateStringTable", parser.Tick, parser.NetTick, m, mf, maxMsgs)
 	})
 	parser.Callbacks.OnCSVCMsg_ClearAllStringTables(func(m *dota.CSVCMsg_ClearAllStringTables) error {
-		return addFilteredMessage(messages, "CSVCMsg_ClearAllStringTables", parser.Tick, parser.NetTick, m, filter, maxMsgs)
+		return addFilteredMessage(messages, "CSVCMsg_ClearAllStringTables", parser.Tick, parser.NetTick, m, mf, maxMsgs)
 	})
 	parser.Callbacks.OnCSVCMsg_PacketEntities(func(m *dota.CSVCMsg_PacketEntities) error {
-		return addFilteredMessage(messages, "CSVCMsg_PacketEntities", parser.Tick, parser.NetTick, m, filter, maxMsgs)
+		return addFilteredMessage(messages, "CSVCMsg_PacketEntities", parser.Tick, parser.NetTick, m, mf, maxMsgs)
 	})
 
 	// DOTA User Messages
 	parser.Callbacks.OnCDOTAUserMsg_ChatMessage(func(m *dota.CDOTAUserMsg_ChatMessage) error {
-		return addFilteredMessage(messages, "CDOTAUserMsg_ChatMessage", parser.Tick, parser.NetTick, m, filter, maxMsgs)
+		return addFilteredMessage(messages, "CDOTAUserMsg_ChatMessage", parser.Tick, parser.NetTick, m, mf, maxMsgs)
 	})
 	parser.Callbacks.OnCDOTAUserMsg_ChatEvent(func(m *dota.CDOTAUserMsg_ChatEvent) error {
-		return addFilteredMessage(messages, "CDOTAUserMsg_ChatEvent", parser.Tick, parser.NetTick, m, filter, maxMsgs)
+		return addFilteredMessage(messages, "CDOTAUserMsg_ChatEvent", parser.Tick, parser.NetTick, m, mf, maxMsgs)
 	})
 	parser.Callbacks.OnCDOTAUserMsg_LocationPing(func(m *dota.CDOTAUserMsg_LocationPing) error {
-		return addFilteredMessage(messages, "CDOTAUserMsg_LocationPing", parser.Tick, parser.NetTick, m, filter, maxMsgs)
+		return addFilteredMessage(messages, "CDOTAUserMsg_LocationPing", parser.Tick, parser.NetTick, m, mf, maxMsgs)
 	})
 	parser.Callbacks.OnCDOTAUserMsg_CombatLogBulkData(func(m *dota.CDOTAUserMsg_CombatLogBulkData) error {
-		return addFilteredMessage(messages, "CDOTAUserMsg_CombatLogBulkData", parser.Tick, parser.NetTick, m, filter, maxMsgs)
+		return addFilteredMessage(messages, "CDOTAUserMsg_CombatLogBulkData", parser.Tick, parser.NetTick, m, mf, maxMsgs)
 	})
 	parser.Callbacks.OnCDOTAUserMsg_UnitEvent(func(m *dota.CDOTAUserMsg_UnitEvent) error {
-		return addFilteredMessage(messages, "CDOTAUserMsg_UnitEvent", parser.Tick, parser.NetTick, m, filter, maxMsgs)
+		return addFilteredMessage(messages, "CDOTAUserMsg_UnitEvent", parser.Tick, parser.NetTick, m, mf, maxMsgs)
 	})
 	parser.Callbacks.OnCDOTAUserMsg_SpectatorPlayerClick(func(m *dota.CDOTAUserMsg_SpectatorPlayerClick) error {
-		return addFilteredMessage(messages, "CDOTAUserMsg_SpectatorPlayerClick", parser.Tick, parser.NetTick, m, filter, maxMsgs)
+		return addFilteredMessage(messages, "CDOTAUserMsg_SpectatorPlayerClick", parser.Tick, parser.NetTick, m, mf, maxMsgs)
+	})
+}
+
+// entityEvent is what setupEntityCallbacks's OnEntity handler puts in a
+// MessageEvent's Data field - a decoded PacketEntity update, as opposed to
+// the raw CSVCMsg_PacketEntities message setupCallbacks already captures.
+type entityEvent struct {
+	ClassName string `json:"class_name"`
+	Index     uint32 `json:"index"`
+	Op        string `json:"op"`
+}
+
+func entityOpName(op manta.EntityOp) string {
+	switch {
+	case op.Flag(manta.EntityOpCreated):
+		return "created"
+	case op.Flag(manta.EntityOpDeleted):
+		return "deleted"
+	case op.Flag(manta.EntityOpUpdated):
+		return "updated"
+	default:
+		return "unknown"
+	}
+}
+
+// setupEntityCallbacks registers the PacketEntity and game-state context
+// capture this tool's message callbacks alone don't cover:
+//   - every entity create/update/delete as an "Entity:<ClassName>" message,
+//     filterable the same way as setupCallbacks' proto messages (a
+//     --callbacks entry of e.g. "Entity:CDOTA_Unit_Hero" matches via the
+//     existing substring filter in addFilteredMessage)
+//   - gameContext, kept current from CDOTAGamerulesProxy so the report can
+//     say what part of the match its messages came from
+func setupEntityCallbacks(parser *manta.Parser, messages *[]MessageEvent, gameContext *GameContext, mf messageFilter, maxMsgs int) {
+	var gameTime, gameStartTime, preGameStartTime float32
+
+	parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+		if e == nil {
+			return nil
+		}
+
+		className := e.GetClassName()
+		if className == "CDOTAGamerulesProxy" {
+			if v, ok := e.GetFloat32("m_pGameRules.m_fGameTime"); ok {
+				gameTime = v
+			}
+			if v, ok := e.GetFloat32("m_pGameRules.m_flGameStartTime"); ok {
+				gameStartTime = v
+			}
+			if v, ok := e.GetFloat32("m_pGameRules.m_flPreGameStartTime"); ok {
+				preGameStartTime = v
+			}
+			if v, ok := e.GetInt32("m_pGameRules.m_iGameState"); ok {
+				gameContext.GameState = v
+				gameContext.GamePhase = gameStateName(v)
+			}
+			if v, ok := e.GetBool("m_pGameRules.m_bGamePaused"); ok {
+				gameContext.IsPaused = v
+			}
+			gameContext.GameTime = gameTime - gameStartTime
+			gameContext.MatchTime = gameTime - preGameStartTime
+		}
+
+		return addFilteredMessage(messages, "Entity:"+className, parser.Tick, parser.NetTick, entityEvent{
+			ClassName: className,
+			Index:     uint32(e.GetIndex()),
+			Op:        entityOpName(op),
+		}, mf, maxMsgs)
 	})
 }
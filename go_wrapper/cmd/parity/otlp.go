@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang/snappy"
+)
+
+// otlpExporterConfig mirrors go_wrapper/sink.go's OTLPSinkConfig - same
+// endpoint/compression/headers/batch-size/retry knobs - but lives here
+// rather than importing that type directly: cmd/parity is its own `package
+// main`, and Go doesn't allow importing symbols from another main package.
+type otlpExporterConfig struct {
+	Endpoint       string
+	Compression    string // "", "gzip", or "snappy"
+	Headers        map[string]string
+	BatchSize      int
+	MaxRetries     int
+	RetryBaseDelay time.Duration
+}
+
+// otlpLogRecord is the same minimal OTLP LogRecord projection sink.go's
+// otlpLogRecord uses.
+type otlpLogRecord struct {
+	TimeUnixNano int64                  `json:"timeUnixNano,string"`
+	Body         interface{}            `json:"body"`
+	Attributes   map[string]interface{} `json:"attributes"`
+}
+
+// parseOTLPHeaders parses a comma-separated key=value list (e.g.
+// "Authorization=Bearer xyz,X-Scope-OrgID=demo") into a header map, the
+// same shape --callbacks already uses for its comma-separated list.
+func parseOTLPHeaders(csv string) map[string]string {
+	headers := make(map[string]string)
+	if csv == "" {
+		return headers
+	}
+	for _, pair := range strings.Split(csv, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		headers[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return headers
+}
+
+// exportOTLP flattens report's per-callback messages into OTLP log records
+// and POSTs them in batches to cfg.Endpoint, with exponential-backoff
+// retry - the same batching/retry/compression behavior as sink.go's
+// OTLPSink.Flush, reimplemented here for the reason otlpExporterConfig's
+// doc comment gives.
+func exportOTLP(report parityReport, cfg otlpExporterConfig) error {
+	if cfg.Endpoint == "" {
+		return fmt.Errorf("--otlp-endpoint is required when --exporter=otlp")
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 256
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.RetryBaseDelay <= 0 {
+		cfg.RetryBaseDelay = 200 * time.Millisecond
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	batch := make([]otlpLogRecord, 0, cfg.BatchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := postOTLPBatch(client, cfg, batch); err != nil {
+			return err
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for cbName, cb := range report.Callbacks {
+		for _, msg := range cb.Messages {
+			body, err := json.Marshal(msg.Data)
+			if err != nil {
+				return fmt.Errorf("marshal message body for %s: %w", cbName, err)
+			}
+			batch = append(batch, otlpLogRecord{
+				TimeUnixNano: int64(msg.Tick) * int64(parityTickInterval),
+				Body:         json.RawMessage(body),
+				Attributes: map[string]interface{}{
+					"tick":         msg.Tick,
+					"net_tick":     msg.NetTick,
+					"message_type": msg.Type,
+					"callback":     cbName,
+					"demo_file":    report.Replay,
+				},
+			})
+			if len(batch) >= cfg.BatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return flush()
+}
+
+// parityTickInterval is the wall-clock duration of a single replay tick,
+// matching sink.go's tickInterval (manta_wrapper.TicksPerSecond isn't
+// reachable from this separate main package, so the constant is repeated
+// here rather than imported).
+const parityTickInterval = time.Second / 30
+
+func postOTLPBatch(client *http.Client, cfg otlpExporterConfig, batch []otlpLogRecord) error {
+	payload, err := json.Marshal(map[string]interface{}{"logRecords": batch})
+	if err != nil {
+		return fmt.Errorf("marshal batch: %w", err)
+	}
+
+	body, contentEncoding, err := compressOTLPPayload(payload, cfg.Compression)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	delay := cfg.RetryBaseDelay
+	for attempt := 0; attempt <= cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+
+		req, err := http.NewRequest(http.MethodPost, cfg.Endpoint, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("build request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if contentEncoding != "" {
+			req.Header.Set("Content-Encoding", contentEncoding)
+		}
+		for k, v := range cfg.Headers {
+			req.Header.Set(k, v)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("OTLP receiver returned status %d", resp.StatusCode)
+	}
+
+	return fmt.Errorf("flushing %d log records to %s: %w", len(batch), cfg.Endpoint, lastErr)
+}
+
+func compressOTLPPayload(payload []byte, compression string) ([]byte, string, error) {
+	switch compression {
+	case "", "none":
+		return payload, "", nil
+	case "gzip":
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		if _, err := gw.Write(payload); err != nil {
+			return nil, "", fmt.Errorf("gzip compress: %w", err)
+		}
+		if err := gw.Close(); err != nil {
+			return nil, "", fmt.Errorf("gzip close: %w", err)
+		}
+		return buf.Bytes(), "gzip", nil
+	case "snappy":
+		return snappy.Encode(nil, payload), "snappy", nil
+	default:
+		return nil, "", fmt.Errorf("unsupported OTLP compression %q (supported: gzip, snappy)", compression)
+	}
+}
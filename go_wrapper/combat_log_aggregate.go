@@ -0,0 +1,319 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// AggregateCombatLogConfig controls AggregateCombatLog.
+type AggregateCombatLogConfig struct {
+	// StartTick/EndTick restrict the aggregation to a tick range. EndTick <=
+	// 0 (the default) means the whole replay.
+	StartTick int `json:"start_tick"`
+	EndTick   int `json:"end_tick"`
+
+	// ExcludeIllusionDamage drops DAMAGE/HEAL entries where
+	// CMsgDOTACombatLogEntry.IsAttackerIllusion/IsTargetIllusion is set,
+	// instead of folding illusion/clone damage into the owning hero's
+	// totals the way it's folded in by default (combat log entries name
+	// illusions the same as their owner hero, so no attribution step is
+	// needed to credit the owner - this flag is only for excluding it).
+	ExcludeIllusionDamage bool `json:"exclude_illusion_damage"`
+}
+
+// PlayerCombatAggregate is one player's rollup across the aggregated range,
+// resolved to a player index via the CDOTA_PlayerResource
+// m_vecPlayerTeamData.NNNN.m_hSelectedHero mapping combatLogPlayerTracker
+// builds - unlike PlayerAggregate/PlayerCombatSummary in this package, which
+// key by hero name alone because they don't resolve that mapping.
+type PlayerCombatAggregate struct {
+	PlayerIdx       int32            `json:"player_idx"`
+	Team            int32            `json:"team"`
+	HeroName        string           `json:"hero_name"`
+	DamageDealt     int64            `json:"damage_dealt"`
+	DamageTaken     int64            `json:"damage_taken"`
+	DamageByAbility map[string]int64 `json:"damage_by_ability"`
+	HealingDone     int64            `json:"healing_done"`
+	HealingReceived int64            `json:"healing_received"`
+	Kills           int              `json:"kills"`
+	Deaths          int              `json:"deaths"`
+	Assists         int              `json:"assists"`
+	Denies          int              `json:"denies"`
+	LastHits        int              `json:"last_hits"`
+	XPGained        int64            `json:"xp_gained"`
+	GoldGained      int64            `json:"gold_gained"`
+}
+
+// AggregateCombatLogResult is the AggregateCombatLog response envelope.
+type AggregateCombatLogResult struct {
+	Players []*PlayerCombatAggregate `json:"players"`
+	// DamageMatrix is [attacker_player_idx][target_player_idx] -> damage,
+	// keyed by decimal strings since JSON object keys must be strings.
+	DamageMatrix map[string]map[string]int64 `json:"damage_matrix"`
+	Success      bool                        `json:"success"`
+	Error        string                      `json:"error,omitempty"`
+}
+
+// combatLogPlayerTracker resolves a combat log entry's attacker/target hero
+// name to a player index and team, the same CDOTA_PlayerResource
+// m_vecPlayerTeamData.NNNN.m_hSelectedHero mapping chatPlayerTracker
+// resolves in the other direction (slot -> hero name) for ParseChatLog.
+type combatLogPlayerTracker struct {
+	heroByHandle map[uint64]string
+	idxByHero    map[string]int32
+	teamByHero   map[string]int32
+}
+
+func newCombatLogPlayerTracker() *combatLogPlayerTracker {
+	return &combatLogPlayerTracker{
+		heroByHandle: make(map[uint64]string),
+		idxByHero:    make(map[string]int32),
+		teamByHero:   make(map[string]int32),
+	}
+}
+
+func (t *combatLogPlayerTracker) Watch(parser *manta.Parser) {
+	parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+		if e == nil {
+			return nil
+		}
+		className := e.GetClassName()
+
+		if strings.Contains(className, "CDOTA_Unit_Hero_") {
+			t.heroByHandle[uint64(e.GetIndex())] = entityClassToHeroName(className)
+			return nil
+		}
+
+		if !strings.Contains(className, "CDOTA_PlayerResource") {
+			return nil
+		}
+
+		for i := int32(0); i < 10; i++ {
+			team := int32(2) // Radiant
+			if i >= 5 {
+				team = 3 // Dire
+			}
+
+			handle, ok := e.GetUint64(fmt.Sprintf("m_vecPlayerTeamData.%04d.m_hSelectedHero", i))
+			if !ok {
+				continue
+			}
+			name, ok := t.heroByHandle[handle&0x3FFF]
+			if !ok {
+				continue
+			}
+			t.idxByHero[name] = i
+			t.teamByHero[name] = team
+		}
+		return nil
+	})
+}
+
+// PlayerIdx resolves heroName to a player index, or (-1, false) if the
+// mapping isn't known yet (e.g. a combat log entry arriving before the
+// first CDOTA_PlayerResource snapshot).
+func (t *combatLogPlayerTracker) PlayerIdx(heroName string) (int32, bool) {
+	idx, ok := t.idxByHero[heroName]
+	return idx, ok
+}
+
+func (t *combatLogPlayerTracker) Team(heroName string) int32 {
+	return t.teamByHero[heroName]
+}
+
+//export AggregateCombatLog
+func AggregateCombatLog(filePath *C.char, configJSON *C.char) (cResult *C.char) {
+	goFilePath := C.GoString(filePath)
+	goConfigJSON := C.GoString(configJSON)
+
+	defer func() {
+		if r := recover(); r != nil {
+			failure := &AggregateCombatLogResult{Success: false, Error: fmt.Sprintf("panic during parsing: %v", r)}
+			cResult = marshalAggregateCombatLogResult(failure)
+		}
+	}()
+
+	config := AggregateCombatLogConfig{}
+	if goConfigJSON != "" {
+		if err := json.Unmarshal([]byte(goConfigJSON), &config); err != nil {
+			return marshalAggregateCombatLogResult(&AggregateCombatLogResult{Success: false, Error: fmt.Sprintf("invalid config JSON: %v", err)})
+		}
+	}
+
+	result, err := RunAggregateCombatLog(goFilePath, config)
+	if err != nil {
+		return marshalAggregateCombatLogResult(&AggregateCombatLogResult{Success: false, Error: err.Error()})
+	}
+	return marshalAggregateCombatLogResult(result)
+}
+
+// RunAggregateCombatLog streams the whole replay (or StartTick..EndTick)
+// once, reducing the combat log to per-player totals and an
+// attacker-player -> target-player damage matrix, resolving player indices
+// via combatLogPlayerTracker instead of leaving results keyed by hero name
+// the way RunCombatLogSummary/aggregateStatsCollector do.
+func RunAggregateCombatLog(filePath string, config AggregateCombatLogConfig) (*AggregateCombatLogResult, error) {
+	result := &AggregateCombatLogResult{
+		Players:      make([]*PlayerCombatAggregate, 0, 10),
+		DamageMatrix: make(map[string]map[string]int64),
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		return nil, fmt.Errorf("error creating parser: %w", err)
+	}
+
+	players := newCombatLogPlayerTracker()
+	players.Watch(parser)
+
+	byIdx := make(map[int32]*PlayerCombatAggregate)
+	playerAgg := func(heroName string) *PlayerCombatAggregate {
+		idx, ok := players.PlayerIdx(heroName)
+		if !ok {
+			return nil
+		}
+		agg, ok := byIdx[idx]
+		if !ok {
+			agg = &PlayerCombatAggregate{
+				PlayerIdx:       idx,
+				Team:            players.Team(heroName),
+				HeroName:        heroName,
+				DamageByAbility: make(map[string]int64),
+			}
+			byIdx[idx] = agg
+			result.Players = append(result.Players, agg)
+		}
+		return agg
+	}
+
+	addMatchup := func(attackerIdx, targetIdx int32, damage int64) {
+		key := strconv.Itoa(int(attackerIdx))
+		row, ok := result.DamageMatrix[key]
+		if !ok {
+			row = make(map[string]int64)
+			result.DamageMatrix[key] = row
+		}
+		row[strconv.Itoa(int(targetIdx))] += damage
+	}
+
+	inRange := func(tick uint32) bool {
+		if int(tick) < config.StartTick {
+			return false
+		}
+		if config.EndTick > 0 && int(tick) > config.EndTick {
+			return false
+		}
+		return true
+	}
+
+	parser.Callbacks.OnCMsgDOTACombatLogEntry(func(m *dota.CMsgDOTACombatLogEntry) error {
+		if !inRange(parser.Tick) {
+			return nil
+		}
+		if config.ExcludeIllusionDamage && (m.GetIsAttackerIllusion() || m.GetIsTargetIllusion()) {
+			return nil
+		}
+
+		// AttackerName/TargetName are CombatLogNames string table indices,
+		// not resolved strings - resolve the same way data_parser.go/
+		// combat_log_iterator.go already do.
+		attacker, _ := parser.LookupStringByIndex("CombatLogNames", int32(m.GetAttackerName()))
+		target, _ := parser.LookupStringByIndex("CombatLogNames", int32(m.GetTargetName()))
+
+		switch m.GetType() {
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_DAMAGE:
+			dmg := int64(m.GetValue())
+			attackerAgg, targetAgg := playerAgg(attacker), playerAgg(target)
+			if attackerAgg != nil {
+				attackerAgg.DamageDealt += dmg
+				inflictor, _ := parser.LookupStringByIndex("CombatLogNames", int32(m.GetInflictorName()))
+				if inflictor != "" {
+					attackerAgg.DamageByAbility[inflictor] += dmg
+				}
+			}
+			if targetAgg != nil {
+				targetAgg.DamageTaken += dmg
+			}
+			if attackerAgg != nil && targetAgg != nil {
+				addMatchup(attackerAgg.PlayerIdx, targetAgg.PlayerIdx, dmg)
+			}
+
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_HEAL:
+			heal := int64(m.GetValue())
+			if agg := playerAgg(attacker); agg != nil {
+				agg.HealingDone += heal
+			}
+			if agg := playerAgg(target); agg != nil {
+				agg.HealingReceived += heal
+			}
+
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_DEATH:
+			switch {
+			case strings.Contains(target, "hero"):
+				if agg := playerAgg(target); agg != nil {
+					agg.Deaths++
+				}
+				if agg := playerAgg(attacker); agg != nil {
+					agg.Kills++
+				}
+				for _, assistName := range assistHeroNames(parser, m) {
+					if agg := playerAgg(assistName); agg != nil {
+						agg.Assists++
+					}
+				}
+			case strings.Contains(target, "creep"):
+				if agg := playerAgg(attacker); agg != nil {
+					if m.GetAttackerTeam() == m.GetTargetTeam() {
+						agg.Denies++
+					} else {
+						agg.LastHits++
+					}
+				}
+			}
+
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_GOLD:
+			if agg := playerAgg(target); agg != nil {
+				agg.GoldGained += int64(m.GetValue())
+			}
+
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_XP:
+			if agg := playerAgg(target); agg != nil {
+				agg.XPGained += int64(m.GetValue())
+			}
+		}
+
+		return nil
+	})
+
+	if err := parser.Start(); err != nil {
+		return nil, fmt.Errorf("error parsing file: %w", err)
+	}
+
+	result.Success = true
+	return result, nil
+}
+
+func marshalAggregateCombatLogResult(r *AggregateCombatLogResult) *C.char {
+	data, err := json.Marshal(r)
+	if err != nil {
+		data, _ = json.Marshal(&AggregateCombatLogResult{Success: false, Error: err.Error()})
+	}
+	return C.CString(string(data))
+}
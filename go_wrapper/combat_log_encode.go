@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// combatLogEnumFields lists the CombatLogEntry fields EncodeWithProjection
+// treats as enum-valued for EnumFormat's "name"/"both" modes.
+var combatLogEnumFields = []string{
+	"DamageType",
+	"DamageCategory",
+	"RuneType",
+	"BuildingType",
+	"NeutralCampType",
+	"UnitStatusLabel",
+	"KillEaterEvent",
+}
+
+// combatLogEnumNames maps the enum fields above to their value->name tables.
+// Only dota.DOTA_COMBATLOG_TYPES_name is a generated enum map this codebase
+// can confirm exists (see CombatLogEntry.TypeName's lookup); the manta/dota
+// package isn't vendored in this tree, so the remaining combat log enums
+// below can't be resolved against real generated symbols without risking a
+// reference to a name that was never generated in the first place. These
+// tables are hand-maintained from the known CMsgDOTACombatLogEntry value
+// sets instead; combatLogEnumName falls back to "unknown_N" for anything
+// not listed here, the same way CombatLogEntry.ValueName already does for
+// unresolved CombatLogNames string-table entries.
+var combatLogEnumNames = map[string]map[int32]string{
+	"DamageType": {
+		1: "physical",
+		2: "magical",
+		4: "pure",
+	},
+	"DamageCategory": {
+		0: "default",
+		1: "basic_attack",
+		2: "spell",
+	},
+	"RuneType": {
+		0: "invalid",
+		1: "double_damage",
+		2: "haste",
+		3: "illusion",
+		4: "invisibility",
+		5: "regeneration",
+		6: "arcane",
+		7: "bounty",
+		8: "water",
+		9: "shield",
+	},
+	"BuildingType": {
+		0: "tower",
+		1: "barracks",
+		2: "ancient",
+		3: "other",
+	},
+	"NeutralCampType": {
+		0: "small",
+		1: "medium",
+		2: "large",
+		3: "ancient",
+	},
+	"UnitStatusLabel": {
+		0: "none",
+	},
+	"KillEaterEvent": {
+		0: "none",
+	},
+}
+
+// combatLogEnumName resolves value's name for field, or a deterministic
+// placeholder if field/value isn't in the hand-maintained table above.
+func combatLogEnumName(field string, value int32) string {
+	if names, ok := combatLogEnumNames[field]; ok {
+		if name, ok := names[value]; ok {
+			return name
+		}
+	}
+	return fmt.Sprintf("unknown_%d", value)
+}
+
+// jsonFieldName returns f's JSON key, honoring a `json:"name"` tag and
+// falling back to the Go field name when the tag has no name portion (e.g.
+// `json:",omitempty"`).
+func jsonFieldName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if tag == "" {
+		return f.Name
+	}
+	name := strings.Split(tag, ",")[0]
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// sanitizeNonFinite returns a copy of v (a struct or pointer to struct) with
+// any NaN/Inf float fields zeroed, plus the JSON key for each field it
+// zeroed. encoding/json.Marshal errors on NaN/Inf floats, so this has to run
+// before marshaling - there's no post-processing fix once Marshal has
+// already failed.
+func sanitizeNonFinite(v interface{}) (interface{}, []string) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return v, nil
+	}
+
+	out := reflect.New(rv.Type()).Elem()
+	out.Set(rv)
+
+	var nulled []string
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		fv := out.Field(i)
+		if !fv.CanFloat() {
+			continue
+		}
+		f := fv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			fv.SetFloat(0)
+			nulled = append(nulled, jsonFieldName(t.Field(i)))
+		}
+	}
+
+	return out.Interface(), nulled
+}
+
+// EncodeWithProjection marshals v (a CombatLogEntry or AttackEvent) to a
+// map, applying the same three encoding knobs CombatLogConfig/AttacksConfig
+// expose: fields projects the output down to a field whitelist (dotted
+// paths aren't nested in either struct, so this only matches top-level JSON
+// keys; empty means no projection), enumFields names which keys
+// combatLogEnumNames can resolve, and enumFormat controls whether those
+// keys are left as raw ints ("int", the default/zero value), replaced by
+// their name string ("name"), or joined by a "<field>_name" companion key
+// ("both"). NaN/Inf floats (e.g. a CombatLogEntry.ModifierDuration sentinel)
+// are canonicalized to JSON null regardless of the other knobs.
+func EncodeWithProjection(v interface{}, fields []string, enumFields []string, enumFormat string) (map[string]interface{}, error) {
+	sanitized, nulled := sanitizeNonFinite(v)
+
+	data, err := json.Marshal(sanitized)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling for projection: %w", err)
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("error decoding for projection: %w", err)
+	}
+
+	for _, key := range nulled {
+		m[key] = nil
+	}
+
+	if enumFormat == "name" || enumFormat == "both" {
+		for _, field := range enumFields {
+			key := jsonKeyForField(v, field)
+			raw, ok := m[key]
+			if !ok {
+				continue
+			}
+			num, ok := raw.(float64)
+			if !ok {
+				continue
+			}
+			name := combatLogEnumName(field, int32(num))
+			if enumFormat == "name" {
+				m[key] = name
+			} else {
+				m[key+"_name"] = name
+			}
+		}
+	}
+
+	if len(fields) > 0 {
+		whitelist := make(map[string]bool, len(fields))
+		for _, f := range fields {
+			whitelist[f] = true
+		}
+		for key := range m {
+			base := strings.TrimSuffix(key, "_name")
+			if !whitelist[key] && !whitelist[base] {
+				delete(m, key)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// jsonKeyForField resolves field's (a Go struct field name) JSON key by
+// reflecting on v's type, so EncodeWithProjection's enum handling doesn't
+// have to hardcode a Go-field-name -> JSON-key table alongside
+// combatLogEnumNames.
+func jsonKeyForField(v interface{}, field string) string {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return field
+	}
+	f, ok := rv.Type().FieldByName(field)
+	if !ok {
+		return field
+	}
+	return jsonFieldName(f)
+}
+
+// encodeEntries applies EncodeWithProjection to each entry in entries,
+// skipping (rather than failing outright on) any entry that fails to
+// encode - fields/enumFormat are caller-controlled JSON config, not parser
+// output, so a bad whitelist value shouldn't take down the whole result.
+func encodeEntries(entries interface{}, fields []string, enumFields []string, enumFormat string) []map[string]interface{} {
+	rv := reflect.ValueOf(entries)
+	encoded := make([]map[string]interface{}, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		m, err := EncodeWithProjection(rv.Index(i).Interface(), fields, enumFields, enumFormat)
+		if err != nil {
+			continue
+		}
+		encoded = append(encoded, m)
+	}
+	return encoded
+}
+
+// wantsProjection reports whether fields/enumFormat ask for anything beyond
+// the default typed-struct JSON shape.
+func wantsProjection(fields []string, enumFormat string) bool {
+	return len(fields) > 0 || (enumFormat != "" && enumFormat != "int")
+}
@@ -0,0 +1,275 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// combatLogStreamHandle is one OpenCombatLogStream run: parser.Start()
+// executes in its own goroutine, pushing each resolved CombatLogEntry onto
+// a buffered channel as it's produced instead of accumulating a
+// CombatLogResult, so NextCombatLogBatch can hand entries to Python as they
+// arrive rather than waiting for the whole replay. Unlike
+// ParseCombatLogStream's two-phase buffer-then-resolve design, entries are
+// resolved against CombatLogNames inline in the callback - the same way
+// parser.go's attacker/target hero-level lookups already do mid-parse -
+// since the iterator's whole point is to avoid holding the replay's full
+// entry set in memory at once.
+type combatLogStreamHandle struct {
+	entries chan CombatLogEntry // closed once the parse goroutine returns, signalling Done to NextCombatLogBatch
+	cancel  context.CancelFunc
+	file    *os.File
+	err     *ParseError
+}
+
+var (
+	combatLogStreamHandles      sync.Map // map[uint64]*combatLogStreamHandle
+	nextCombatLogStreamHandleID uint64
+)
+
+// OpenCombatLogStreamResult is OpenCombatLogStream's response envelope.
+type OpenCombatLogStreamResult struct {
+	HandleID uint64     `json:"handle_id,omitempty"`
+	Success  bool       `json:"success"`
+	Error    *ErrorInfo `json:"error,omitempty"`
+}
+
+func marshalOpenCombatLogStreamResult(r *OpenCombatLogStreamResult) *C.char {
+	data, err := json.Marshal(r)
+	if err != nil {
+		data, _ = json.Marshal(&OpenCombatLogStreamResult{Error: simpleErrorInfo(ErrIO, err.Error())})
+	}
+	return C.CString(string(data))
+}
+
+// OpenCombatLogStream opens filePath and starts parsing it in the
+// background, returning a handle NextCombatLogBatch can pull entries from
+// and CloseCombatLogStream must eventually release.
+//
+//export OpenCombatLogStream
+func OpenCombatLogStream(filePath *C.char, configJSON *C.char) *C.char {
+	goFilePath := C.GoString(filePath)
+	goConfigJSON := C.GoString(configJSON)
+
+	config := CombatLogConfig{}
+	if goConfigJSON != "" {
+		if err := json.Unmarshal([]byte(goConfigJSON), &config); err != nil {
+			return marshalOpenCombatLogStreamResult(&OpenCombatLogStreamResult{Error: simpleErrorInfo(ErrIO, fmt.Sprintf("invalid config JSON: %v", err))})
+		}
+	}
+
+	file, err := os.Open(goFilePath)
+	if err != nil {
+		return marshalOpenCombatLogStreamResult(&OpenCombatLogStreamResult{Error: classifyOpenError(err).toErrorInfo()})
+	}
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		file.Close()
+		return marshalOpenCombatLogStreamResult(&OpenCombatLogStreamResult{Error: classifyParseError(err).toErrorInfo()})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &combatLogStreamHandle{
+		entries: make(chan CombatLogEntry, 256),
+		cancel:  cancel,
+		file:    file,
+	}
+
+	id := atomic.AddUint64(&nextCombatLogStreamHandleID, 1)
+	combatLogStreamHandles.Store(id, h)
+
+	gameTime := newGameTimeTracker()
+	gameTime.Watch(parser)
+
+	getName := func(idx uint32) string {
+		if name, ok := parser.LookupStringByIndex("CombatLogNames", int32(idx)); ok {
+			return name
+		}
+		return fmt.Sprintf("unknown_%d", idx)
+	}
+
+	written := 0
+	progress := newProgressReporter(config.ProgressSlotID, 0)
+
+	parser.Callbacks.OnCMsgDOTACombatLogEntry(func(m *dota.CMsgDOTACombatLogEntry) error {
+		progress.report(parser.Tick, uint64(written))
+
+		if ctx.Err() != nil {
+			parser.Stop()
+			return nil
+		}
+
+		if config.MaxEntries > 0 && written >= config.MaxEntries {
+			return nil
+		}
+		if len(config.Types) > 0 {
+			found := false
+			for _, t := range config.Types {
+				if t == int32(m.GetType()) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil
+			}
+		}
+
+		entry := CombatLogEntry{
+			Tick: parser.Tick, NetTick: parser.NetTick,
+			Type: int32(m.GetType()), TypeName: dota.DOTA_COMBATLOG_TYPES_name[int32(m.GetType())],
+			TargetName: getName(m.GetTargetName()), AttackerName: getName(m.GetAttackerName()),
+			IsAttackerHero: m.GetIsAttackerHero(), IsTargetHero: m.GetIsTargetHero(),
+			Value: int32(m.GetValue()), Health: m.GetHealth(),
+			Timestamp: m.GetTimestamp(),
+		}
+
+		if config.HeroesOnly && !entry.IsAttackerHero && !entry.IsTargetHero &&
+			!strings.Contains(entry.AttackerName, "npc_dota_hero_") && !strings.Contains(entry.TargetName, "npc_dota_hero_") {
+			return nil
+		}
+
+		gameTime.Annotate(func(s gameTimeSnapshot) {
+			entry.GameTime = s.GameTime
+			entry.MatchTime = s.MatchTime
+			entry.GameState = s.GameState
+			entry.GamePhase = s.GamePhase
+			entry.IsPaused = s.IsPaused
+
+			select {
+			case h.entries <- entry:
+				written++
+			case <-ctx.Done():
+				parser.Stop()
+			}
+		})
+		return nil
+	})
+
+	go func() {
+		defer file.Close()
+		defer close(h.entries)
+		defer cancel()
+
+		startErr := parser.Start()
+		if startErr != nil && ctx.Err() == nil {
+			h.err = classifyParseError(startErr)
+		}
+		progress.finish(parser.Tick, uint64(written))
+	}()
+
+	return marshalOpenCombatLogStreamResult(&OpenCombatLogStreamResult{HandleID: id, Success: true})
+}
+
+// NextCombatLogBatchResult is NextCombatLogBatch's response envelope.
+type NextCombatLogBatchResult struct {
+	Entries []CombatLogEntry `json:"entries"`
+	Done    bool             `json:"done"`
+	Success bool             `json:"success"`
+	Error   *ErrorInfo       `json:"error,omitempty"`
+}
+
+func marshalNextCombatLogBatchResult(r *NextCombatLogBatchResult) *C.char {
+	data, err := json.Marshal(r)
+	if err != nil {
+		data, _ = json.Marshal(&NextCombatLogBatchResult{Done: true, Error: simpleErrorInfo(ErrIO, err.Error())})
+	}
+	return C.CString(string(data))
+}
+
+// NextCombatLogBatch returns up to maxEntries entries pulled off handleID's
+// channel, waiting at most timeoutMs for the first one (0 = block
+// indefinitely). The deadline is implemented the way net.Conn deadlines
+// are: a cancelCh closed by a time.AfterFunc armed fresh on every call, so
+// a Python caller polling in a loop can bound each call's latency (and
+// thus its own responsiveness to a KeyboardInterrupt) without the deadline
+// persisting across calls. Returns Done once the channel is drained and
+// closed - a partial (possibly empty) batch with Done=false just means the
+// deadline elapsed before maxEntries were available.
+//
+//export NextCombatLogBatch
+func NextCombatLogBatch(handleID C.ulonglong, maxEntries C.int, timeoutMs C.longlong) *C.char {
+	id := uint64(handleID)
+	v, ok := combatLogStreamHandles.Load(id)
+	if !ok {
+		return marshalNextCombatLogBatchResult(&NextCombatLogBatchResult{Done: true, Error: simpleErrorInfo(ErrIO, "unknown or already-closed combat log stream handle")})
+	}
+	h := v.(*combatLogStreamHandle)
+
+	limit := int(maxEntries)
+	if limit <= 0 {
+		limit = 1
+	}
+
+	cancelCh := make(chan struct{})
+	var timer *time.Timer
+	if int64(timeoutMs) > 0 {
+		timer = time.AfterFunc(time.Duration(int64(timeoutMs))*time.Millisecond, func() { close(cancelCh) })
+	}
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	batch := make([]CombatLogEntry, 0, limit)
+	for len(batch) < limit {
+		select {
+		case entry, ok := <-h.entries:
+			if !ok {
+				result := &NextCombatLogBatchResult{Entries: batch, Done: true, Success: h.err == nil}
+				if h.err != nil {
+					result.Error = h.err.toErrorInfo()
+				}
+				return marshalNextCombatLogBatchResult(result)
+			}
+			batch = append(batch, entry)
+		case <-cancelCh:
+			return marshalNextCombatLogBatchResult(&NextCombatLogBatchResult{Entries: batch, Success: true})
+		}
+	}
+	return marshalNextCombatLogBatchResult(&NextCombatLogBatchResult{Entries: batch, Success: true})
+}
+
+// CloseCombatLogStream cancels handleID's background parse (if still
+// running) and releases its resources. Safe to call even if the stream has
+// already drained to completion on its own.
+//
+//export CloseCombatLogStream
+func CloseCombatLogStream(handleID C.ulonglong) *C.char {
+	id := uint64(handleID)
+	v, ok := combatLogStreamHandles.LoadAndDelete(id)
+	if !ok {
+		result := map[string]interface{}{"success": false, "error": "unknown or already-closed combat log stream handle"}
+		data, _ := json.Marshal(result)
+		return C.CString(string(data))
+	}
+	h := v.(*combatLogStreamHandle)
+	h.cancel()
+
+	// Drain any buffered entries so the parse goroutine's blocked channel
+	// send (if any) can observe ctx.Done() and unwind instead of leaking.
+	go func() {
+		for range h.entries {
+		}
+	}()
+
+	result := map[string]interface{}{"success": true}
+	data, _ := json.Marshal(result)
+	return C.CString(string(data))
+}
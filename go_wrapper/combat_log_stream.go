@@ -0,0 +1,332 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// CombatLogStreamSummary is ParseCombatLogStream's return value - unlike
+// ParseCombatLog it never holds the full entry set in memory to hand back
+// through CGo, so callers get counts and the game-start anchor instead and
+// read the actual entries back out of outPath.
+type CombatLogStreamSummary struct {
+	TotalEntries  int        `json:"total_entries"`
+	GameStartTime float32    `json:"game_start_time"`
+	GameStartTick uint32     `json:"game_start_tick"`
+	OutPath       string     `json:"out_path"`
+	Success       bool       `json:"success"`
+	Error         *ErrorInfo `json:"error,omitempty"`
+}
+
+// combatLogCsvHeader is the column order ParseCombatLogStream's "csv"
+// format writes; it covers the scalar fields most downstream analysis
+// needs and skips the long tail of rarely-populated modifier/ability
+// fields CombatLogEntry also carries.
+var combatLogCsvHeader = []string{
+	"tick", "net_tick", "game_time", "type", "type_name",
+	"attacker_name", "target_name", "value", "health",
+	"is_attacker_hero", "is_target_hero",
+}
+
+// ParseCombatLogStream is ParseCombatLog's streaming sibling: it writes
+// newline-delimited (or, per CombatLogConfig.Format, single-array JSON or
+// CSV) combat log entries directly to outPath as they're resolved,
+// flushing after each one, instead of buffering a CombatLogResult and
+// marshaling one giant blob back through CGo. Like RunCombatLogParse, name
+// resolution needs the CombatLogNames string table fully populated, so
+// this keeps the same two-pass shape: pass one buffers the raw protobuf
+// messages (far cheaper per-entry than a fully resolved CombatLogEntry),
+// pass two resolves names and streams each entry out as soon as it's
+// ready.
+//
+//export ParseCombatLogStream
+func ParseCombatLogStream(filePath *C.char, configJSON *C.char, outPath *C.char) *C.char {
+	goFilePath := C.GoString(filePath)
+	goConfigJSON := C.GoString(configJSON)
+	goOutPath := C.GoString(outPath)
+
+	summary := &CombatLogStreamSummary{OutPath: goOutPath}
+
+	defer func() {
+		if r := recover(); r != nil {
+			summary.Success = false
+			summary.Error = simpleErrorInfo(ErrPanic, fmt.Sprintf("panic during parsing: %v", r))
+		}
+	}()
+
+	config := CombatLogConfig{Format: "ndjson"}
+	if goConfigJSON != "" {
+		if err := json.Unmarshal([]byte(goConfigJSON), &config); err != nil {
+			summary.Error = simpleErrorInfo(ErrIO, fmt.Sprintf("invalid config JSON: %v", err))
+			return marshalCombatLogStreamSummary(summary)
+		}
+	}
+
+	out, err := os.Create(goOutPath)
+	if err != nil {
+		summary.Error = simpleErrorInfo(ErrIO, fmt.Sprintf("error creating out_path: %v", err))
+		return marshalCombatLogStreamSummary(summary)
+	}
+	defer out.Close()
+
+	runCombatLogStream(goFilePath, config, out, summary)
+	return marshalCombatLogStreamSummary(summary)
+}
+
+// StreamParseCombatLog is ParseCombatLogStream's fd-based sibling: instead
+// of a path the caller owns, it writes to an already-open file descriptor -
+// typically the write end of an os.pipe() the Python side hands in - so it
+// can iterate results lazily off the read end instead of waiting for a
+// file to appear on disk. The fd is wrapped with os.NewFile, not os.Open/
+// os.Create, since it's already an open descriptor the caller (and defer
+// Close here) owns the lifetime of.
+//
+//export StreamParseCombatLog
+func StreamParseCombatLog(filePath *C.char, configJSON *C.char, fd C.int) *C.char {
+	goFilePath := C.GoString(filePath)
+	goConfigJSON := C.GoString(configJSON)
+
+	summary := &CombatLogStreamSummary{OutPath: fmt.Sprintf("fd:%d", int(fd))}
+
+	defer func() {
+		if r := recover(); r != nil {
+			summary.Success = false
+			summary.Error = simpleErrorInfo(ErrPanic, fmt.Sprintf("panic during parsing: %v", r))
+		}
+	}()
+
+	config := CombatLogConfig{Format: "ndjson"}
+	if goConfigJSON != "" {
+		if err := json.Unmarshal([]byte(goConfigJSON), &config); err != nil {
+			summary.Error = simpleErrorInfo(ErrIO, fmt.Sprintf("invalid config JSON: %v", err))
+			return marshalCombatLogStreamSummary(summary)
+		}
+	}
+
+	out := os.NewFile(uintptr(fd), "pipe")
+	if out == nil {
+		summary.Error = simpleErrorInfo(ErrIO, "invalid file descriptor")
+		return marshalCombatLogStreamSummary(summary)
+	}
+	defer out.Close()
+
+	runCombatLogStream(goFilePath, config, out, summary)
+	return marshalCombatLogStreamSummary(summary)
+}
+
+// runCombatLogStream is ParseCombatLogStream/StreamParseCombatLog's shared
+// implementation - the only difference between the two exports is how out
+// was obtained (os.Create'd path vs. an fd the caller already opened), so
+// everything past that point (opening filePath, the two-pass combat log
+// resolution, writing entries, populating summary) lives here once.
+func runCombatLogStream(filePath string, config CombatLogConfig, out io.Writer, summary *CombatLogStreamSummary) {
+	if config.Format == "" {
+		config.Format = "ndjson"
+	}
+
+	in, err := os.Open(filePath)
+	if err != nil {
+		summary.Error = classifyOpenError(err).toErrorInfo()
+		return
+	}
+	defer in.Close()
+
+	parser, err := manta.NewStreamParser(in)
+	if err != nil {
+		summary.Error = classifyParseError(err).toErrorInfo()
+		return
+	}
+
+	gameTime := newGameTimeTracker()
+	gameTime.Watch(parser)
+
+	type rawEntry struct {
+		tick, netTick uint32
+		msg           *dota.CMsgDOTACombatLogEntry
+		snapshot      gameTimeSnapshot
+	}
+	rawEntries := make([]rawEntry, 0)
+
+	var gameStartTime float32
+	var gameStartTick uint32
+
+	parser.Callbacks.OnCMsgDOTACombatLogEntry(func(m *dota.CMsgDOTACombatLogEntry) error {
+		if m.GetType() == dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_GAME_STATE && m.GetValue() == 5 {
+			gameStartTime = m.GetTimestamp()
+			gameStartTick = parser.Tick
+		}
+
+		if config.MaxEntries > 0 && len(rawEntries) >= config.MaxEntries {
+			return nil
+		}
+		if len(config.Types) > 0 {
+			found := false
+			for _, t := range config.Types {
+				if t == int32(m.GetType()) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil
+			}
+		}
+
+		rawEntries = append(rawEntries, rawEntry{tick: parser.Tick, netTick: parser.NetTick, msg: m})
+		idx := len(rawEntries) - 1
+		gameTime.Annotate(func(s gameTimeSnapshot) { rawEntries[idx].snapshot = s })
+		return nil
+	})
+
+	if err := parser.Start(); err != nil {
+		summary.Error = classifyParseError(err).toErrorInfo()
+		return
+	}
+
+	getName := func(idx uint32) string {
+		if name, ok := parser.LookupStringByIndex("CombatLogNames", int32(idx)); ok {
+			return name
+		}
+		return fmt.Sprintf("unknown_%d", idx)
+	}
+
+	writer := bufio.NewWriter(out)
+	var csvWriter *csv.Writer
+	if config.Format == "csv" {
+		csvWriter = csv.NewWriter(writer)
+		if err := csvWriter.Write(combatLogCsvHeader); err != nil {
+			summary.Error = simpleErrorInfo(ErrIO, fmt.Sprintf("error writing csv header: %v", err))
+			return
+		}
+	}
+	if config.Format == "json" {
+		writer.WriteString("[")
+	}
+
+	written := 0
+	for _, raw := range rawEntries {
+		m := raw.msg
+		entry := CombatLogEntry{
+			Tick: raw.tick, NetTick: raw.netTick,
+			Type: int32(m.GetType()), TypeName: dota.DOTA_COMBATLOG_TYPES_name[int32(m.GetType())],
+			TargetName: getName(m.GetTargetName()), AttackerName: getName(m.GetAttackerName()),
+			IsAttackerHero: m.GetIsAttackerHero(), IsTargetHero: m.GetIsTargetHero(),
+			Value: int32(m.GetValue()), Health: m.GetHealth(),
+			GameTime: raw.snapshot.GameTime, MatchTime: raw.snapshot.MatchTime,
+			GameState: raw.snapshot.GameState, GamePhase: raw.snapshot.GamePhase, IsPaused: raw.snapshot.IsPaused,
+			Timestamp: m.GetTimestamp(),
+		}
+
+		if config.HeroesOnly && !entry.IsAttackerHero && !entry.IsTargetHero &&
+			!strings.Contains(entry.AttackerName, "npc_dota_hero_") && !strings.Contains(entry.TargetName, "npc_dota_hero_") {
+			continue
+		}
+
+		if err := writeCombatLogStreamEntry(writer, csvWriter, config, &entry, written); err != nil {
+			summary.Error = simpleErrorInfo(ErrIO, fmt.Sprintf("error writing entry: %v", err))
+			return
+		}
+		written++
+
+		if written%30 == 0 {
+			writer.Flush()
+		}
+	}
+
+	if config.Format == "csv" {
+		csvWriter.Flush()
+	}
+	if config.Format == "json" {
+		writer.WriteString("]")
+	}
+	if err := writer.Flush(); err != nil {
+		summary.Error = simpleErrorInfo(ErrIO, fmt.Sprintf("error flushing output: %v", err))
+		return
+	}
+
+	summary.TotalEntries = written
+	summary.GameStartTime = gameStartTime
+	summary.GameStartTick = gameStartTick
+	summary.Success = true
+}
+
+// writeCombatLogStreamEntry appends one entry to writer in the requested
+// format: a JSON object per line for "ndjson", a comma-joined element of a
+// JSON array for "json", or a row for "csv". The "csv" format always writes
+// combatLogCsvHeader's fixed columns; config.Fields/EnumFormat only affect
+// "ndjson"/"json", encoding through EncodeWithProjection instead of
+// CombatLogEntry's plain JSON tags when either is set, so the streaming
+// sink follows the same encoding policy as ParseCombatLog.
+func writeCombatLogStreamEntry(writer *bufio.Writer, csvWriter *csv.Writer, config CombatLogConfig, entry *CombatLogEntry, index int) error {
+	format := config.Format
+	marshalEntry := func() ([]byte, error) {
+		if wantsProjection(config.Fields, config.EnumFormat) {
+			m, err := EncodeWithProjection(entry, config.Fields, combatLogEnumFields, config.EnumFormat)
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(m)
+		}
+		return json.Marshal(entry)
+	}
+
+	switch format {
+	case "csv":
+		row := []string{
+			strconv.FormatUint(uint64(entry.Tick), 10),
+			strconv.FormatUint(uint64(entry.NetTick), 10),
+			strconv.FormatFloat(float64(entry.GameTime), 'f', -1, 32),
+			strconv.FormatInt(int64(entry.Type), 10),
+			entry.TypeName,
+			entry.AttackerName, entry.TargetName,
+			strconv.FormatInt(int64(entry.Value), 10),
+			strconv.FormatInt(int64(entry.Health), 10),
+			strconv.FormatBool(entry.IsAttackerHero),
+			strconv.FormatBool(entry.IsTargetHero),
+		}
+		return csvWriter.Write(row)
+
+	case "json":
+		data, err := marshalEntry()
+		if err != nil {
+			return err
+		}
+		if index > 0 {
+			writer.WriteString(",")
+		}
+		_, err = writer.Write(data)
+		return err
+
+	default: // "ndjson"
+		data, err := marshalEntry()
+		if err != nil {
+			return err
+		}
+		if _, err := writer.Write(data); err != nil {
+			return err
+		}
+		return writer.WriteByte('\n')
+	}
+}
+
+func marshalCombatLogStreamSummary(s *CombatLogStreamSummary) *C.char {
+	data, err := json.Marshal(s)
+	if err != nil {
+		data, _ = json.Marshal(&CombatLogStreamSummary{Success: false, Error: simpleErrorInfo(ErrIO, err.Error())})
+	}
+	return C.CString(string(data))
+}
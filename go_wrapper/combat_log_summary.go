@@ -0,0 +1,287 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// Bit values CMsgDOTACombatLogEntry.DamageType encodes (the game itself
+// treats damage type as a flag set, not an enum, so a hit can in principle
+// carry more than one bit - CombatLogDamageBreakdown resolves that by
+// priority: pure beats magical beats physical).
+const (
+	damageTypePhysical = 1
+	damageTypeMagical  = 2
+	damageTypePure     = 4
+)
+
+// CombatLogSummaryConfig controls RunCombatLogSummary.
+type CombatLogSummaryConfig struct {
+	// BucketSeconds is the width of each PlayerCombatSummary.Timeline
+	// bucket. Defaults to 60 (one-minute buckets) when zero.
+	BucketSeconds int `json:"bucket_seconds"`
+}
+
+// CombatLogDamageBreakdown splits PlayerCombatSummary.DamageDealt by
+// CombatLogEntry.DamageType.
+type CombatLogDamageBreakdown struct {
+	Physical int64 `json:"physical"`
+	Magical  int64 `json:"magical"`
+	Pure     int64 `json:"pure"`
+}
+
+// CombatLogBucket is one BucketSeconds-wide slice of a player's timeline,
+// keyed by the game-time second the bucket starts at.
+type CombatLogBucket struct {
+	BucketStart float32 `json:"bucket_start"`
+	DamageDealt int64   `json:"damage_dealt"`
+	DamageTaken int64   `json:"damage_taken"`
+	HealingDone int64   `json:"healing_done"`
+	GoldGained  int64   `json:"gold_gained"`
+	XPGained    int64   `json:"xp_gained"`
+}
+
+// PlayerCombatSummary is one hero's aggregated totals across the whole
+// replay, keyed the same way aggregateStatsCollector keys PlayerAggregate -
+// by hero/unit name, since that's all CombatLogEntry carries.
+type PlayerCombatSummary struct {
+	HeroName           string                   `json:"hero_name"`
+	DamageDealt        int64                    `json:"damage_dealt"`
+	DamageTaken        int64                    `json:"damage_taken"`
+	DamageBreakdown    CombatLogDamageBreakdown `json:"damage_breakdown"`
+	HealingDone        int64                    `json:"healing_done"`
+	HealingReceived    int64                    `json:"healing_received"`
+	Kills              int                      `json:"kills"`
+	Deaths             int                      `json:"deaths"`
+	Assists            int                      `json:"assists"`
+	LastHits           int                      `json:"last_hits"`
+	Denies             int                      `json:"denies"`
+	XPGained           int64                    `json:"xp_gained"`
+	GoldGained         int64                    `json:"gold_gained"`
+	WardsPlaced        int                      `json:"wards_placed"`
+	RunesTaken         int                      `json:"runes_taken"`
+	BuildingsDestroyed int                      `json:"buildings_destroyed"`
+	Timeline           []CombatLogBucket        `json:"timeline"`
+
+	buckets map[int]*CombatLogBucket
+}
+
+// CombatLogSummary is the SummarizeCombatLog response envelope.
+type CombatLogSummary struct {
+	Players       map[string]*PlayerCombatSummary `json:"players"` // keyed by hero name, e.g. "npc_dota_hero_axe"
+	BucketSeconds int                             `json:"bucket_seconds"`
+	Success       bool                            `json:"success"`
+	Error         string                          `json:"error,omitempty"`
+}
+
+func newPlayerCombatSummary(heroName string) *PlayerCombatSummary {
+	return &PlayerCombatSummary{
+		HeroName: heroName,
+		Timeline: make([]CombatLogBucket, 0),
+		buckets:  make(map[int]*CombatLogBucket),
+	}
+}
+
+// bucket returns (creating if necessary) the CombatLogBucket covering
+// gameTime, appending it to Timeline in the order buckets are first seen -
+// combat log entries arrive in tick order, so that's already bucket order.
+func (p *PlayerCombatSummary) bucket(gameTime float32, bucketSeconds int) *CombatLogBucket {
+	idx := int(gameTime) / bucketSeconds
+	if b, ok := p.buckets[idx]; ok {
+		return b
+	}
+	p.Timeline = append(p.Timeline, CombatLogBucket{BucketStart: float32(idx * bucketSeconds)})
+	b := &p.Timeline[len(p.Timeline)-1]
+	p.buckets[idx] = b
+	return b
+}
+
+//export SummarizeCombatLog
+func SummarizeCombatLog(filePath *C.char, configJSON *C.char) (cResult *C.char) {
+	goFilePath := C.GoString(filePath)
+	goConfigJSON := C.GoString(configJSON)
+
+	defer func() {
+		if r := recover(); r != nil {
+			failure := &CombatLogSummary{Success: false, Error: fmt.Sprintf("panic during parsing: %v", r)}
+			cResult = marshalCombatLogSummary(failure)
+		}
+	}()
+
+	config := CombatLogSummaryConfig{BucketSeconds: 60}
+	if goConfigJSON != "" {
+		if err := json.Unmarshal([]byte(goConfigJSON), &config); err != nil {
+			return marshalCombatLogSummary(&CombatLogSummary{Success: false, Error: fmt.Sprintf("invalid config JSON: %v", err)})
+		}
+	}
+	if config.BucketSeconds <= 0 {
+		config.BucketSeconds = 60
+	}
+
+	result, err := RunCombatLogSummary(goFilePath, config)
+	if err != nil {
+		return marshalCombatLogSummary(&CombatLogSummary{Success: false, Error: err.Error()})
+	}
+	return marshalCombatLogSummary(result)
+}
+
+// RunCombatLogSummary walks the replay's combat log once, reducing it to
+// per-hero totals and BucketSeconds-wide timelines instead of the raw
+// per-entry rows RunCombatLogParse returns - the same rollup
+// aggregateStatsCollector performs for RunParse, but exposed standalone
+// with the fuller breakdown (damage by type, last hits/denies, wards,
+// runes, buildings) the raw stream doesn't surface on its own.
+func RunCombatLogSummary(filePath string, config CombatLogSummaryConfig) (*CombatLogSummary, error) {
+	result := &CombatLogSummary{
+		Players:       make(map[string]*PlayerCombatSummary),
+		BucketSeconds: config.BucketSeconds,
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		return nil, fmt.Errorf("error creating parser: %w", err)
+	}
+
+	gameTime := newGameTimeTracker()
+	gameTime.Watch(parser)
+
+	playerAgg := func(heroName string) *PlayerCombatSummary {
+		if heroName == "" {
+			return nil
+		}
+		agg, ok := result.Players[heroName]
+		if !ok {
+			agg = newPlayerCombatSummary(heroName)
+			result.Players[heroName] = agg
+		}
+		return agg
+	}
+
+	parser.Callbacks.OnCMsgDOTACombatLogEntry(func(m *dota.CMsgDOTACombatLogEntry) error {
+		// AttackerName/TargetName are CombatLogNames string table indices,
+		// not resolved strings - resolve the same way data_parser.go/
+		// combat_log_iterator.go already do.
+		attacker, _ := parser.LookupStringByIndex("CombatLogNames", int32(m.GetAttackerName()))
+		target, _ := parser.LookupStringByIndex("CombatLogNames", int32(m.GetTargetName()))
+		gt := gameTime.GameTimeAt(parser.Tick)
+
+		switch m.GetType() {
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_DAMAGE:
+			dmg := int64(m.GetValue())
+			if agg := playerAgg(attacker); agg != nil {
+				agg.DamageDealt += dmg
+				switch dt := m.GetDamageType(); {
+				case dt&damageTypePure != 0:
+					agg.DamageBreakdown.Pure += dmg
+				case dt&damageTypeMagical != 0:
+					agg.DamageBreakdown.Magical += dmg
+				default:
+					agg.DamageBreakdown.Physical += dmg
+				}
+				agg.bucket(gt, config.BucketSeconds).DamageDealt += dmg
+			}
+			if agg := playerAgg(target); agg != nil {
+				agg.DamageTaken += dmg
+				agg.bucket(gt, config.BucketSeconds).DamageTaken += dmg
+			}
+
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_HEAL:
+			heal := int64(m.GetValue())
+			if agg := playerAgg(attacker); agg != nil {
+				agg.HealingDone += heal
+				agg.bucket(gt, config.BucketSeconds).HealingDone += heal
+			}
+			if agg := playerAgg(target); agg != nil {
+				agg.HealingReceived += heal
+			}
+
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_DEATH:
+			switch {
+			case strings.Contains(target, "hero"):
+				if agg := playerAgg(target); agg != nil {
+					agg.Deaths++
+				}
+				if agg := playerAgg(attacker); agg != nil {
+					agg.Kills++
+				}
+				for _, assistName := range assistHeroNames(parser, m) {
+					if agg := playerAgg(assistName); agg != nil {
+						agg.Assists++
+					}
+				}
+			case strings.Contains(target, "tower"), strings.Contains(target, "rax"), strings.Contains(target, "barracks"):
+				if agg := playerAgg(attacker); agg != nil {
+					agg.BuildingsDestroyed++
+				}
+			case strings.Contains(target, "creep"):
+				if agg := playerAgg(attacker); agg != nil {
+					if m.GetAttackerTeam() == m.GetTargetTeam() {
+						agg.Denies++
+					} else {
+						agg.LastHits++
+					}
+				}
+			}
+
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_GOLD:
+			gold := int64(m.GetValue())
+			if agg := playerAgg(target); agg != nil {
+				agg.GoldGained += gold
+				agg.bucket(gt, config.BucketSeconds).GoldGained += gold
+			}
+
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_XP:
+			xp := int64(m.GetValue())
+			if agg := playerAgg(target); agg != nil {
+				agg.XPGained += xp
+				agg.bucket(gt, config.BucketSeconds).XPGained += xp
+			}
+
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_RUNE_PICKUP:
+			if agg := playerAgg(attacker); agg != nil {
+				agg.RunesTaken++
+			}
+
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_ABILITY:
+			inflictor, _ := parser.LookupStringByIndex("CombatLogNames", int32(m.GetInflictorName()))
+			if strings.Contains(inflictor, "observer_ward") || strings.Contains(inflictor, "sentry_ward") {
+				if agg := playerAgg(attacker); agg != nil {
+					agg.WardsPlaced++
+				}
+			}
+		}
+
+		return nil
+	})
+
+	if err := parser.Start(); err != nil {
+		return nil, fmt.Errorf("error parsing file: %w", err)
+	}
+
+	result.Success = true
+	return result, nil
+}
+
+func marshalCombatLogSummary(r *CombatLogSummary) *C.char {
+	data, err := json.Marshal(r)
+	if err != nil {
+		data, _ = json.Marshal(&CombatLogSummary{Success: false, Error: err.Error()})
+	}
+	return C.CString(string(data))
+}
@@ -0,0 +1,112 @@
+// Package combatlog decodes CMsgDOTACombatLogEntry messages into a typed,
+// human-readable CombatLogEntry instead of leaving callers to resolve
+// CombatLogNames string table indices themselves. manta already splits a
+// CDOTAUserMsg_CombatLogBulkData message into individual
+// CMsgDOTACombatLogEntry callbacks (that's what every other combat log
+// consumer in this tree - RunCombatLogParse, ParseCombatLogStream - already
+// registers against), so Register hooks that same per-entry callback rather
+// than trying to re-parse the bulk envelope itself.
+//
+// This package has no manta_wrapper/cgo dependency - like the analytics
+// package, it only needs manta.Parser and the generated dota proto types,
+// both of which are plain Go - so it can be unit tested and reused outside
+// the cgo-exported surface if a future caller needs that.
+package combatlog
+
+import (
+	"fmt"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// CombatLogEntry is one decoded combat log row: the scalar fields most
+// analysis needs, with every *_name index already resolved against the
+// CombatLogNames string table. It deliberately doesn't try to cover every
+// field CMsgDOTACombatLogEntry carries (see CombatLogEntry in data_parser.go
+// for that) - just the common subset a --combat-log-csv or OnEntry consumer
+// wants without a string table lookup of their own.
+type CombatLogEntry struct {
+	Tick           uint32  `json:"tick"`
+	NetTick        uint32  `json:"net_tick"`
+	Type           int32   `json:"type"`
+	TypeName       string  `json:"type_name"`
+	AttackerName   string  `json:"attacker_name"`
+	TargetName     string  `json:"target_name"`
+	AbilityName    string  `json:"ability_name"`
+	Value          int32   `json:"value"`
+	Health         int32   `json:"health"`
+	GameTime       float32 `json:"game_time"`
+	Timestamp      float32 `json:"timestamp"`
+	IsAttackerHero bool    `json:"is_attacker_hero"`
+	IsTargetHero   bool    `json:"is_target_hero"`
+	IsCritical     bool    `json:"is_critical"`
+}
+
+// NameResolver resolves a CombatLogNames string table index to its name,
+// the same signature as the getName closures every combat log consumer in
+// this tree already builds over parser.LookupStringByIndex.
+type NameResolver func(index uint32) string
+
+// Decode converts one raw CMsgDOTACombatLogEntry into a CombatLogEntry,
+// resolving its name-table indices via resolve. gameTime is the caller's
+// best current estimate of elapsed match time (callers that don't track it
+// can pass m.GetTimestamp() again, or 0).
+func Decode(m *dota.CMsgDOTACombatLogEntry, resolve NameResolver, gameTime float32) CombatLogEntry {
+	return CombatLogEntry{
+		Type:           int32(m.GetType()),
+		TypeName:       dota.DOTA_COMBATLOG_TYPES_name[int32(m.GetType())],
+		AttackerName:   resolve(m.GetAttackerName()),
+		TargetName:     resolve(m.GetTargetName()),
+		AbilityName:    resolve(m.GetInflictorName()),
+		Value:          int32(m.GetValue()),
+		Health:         m.GetHealth(),
+		GameTime:       gameTime,
+		Timestamp:      m.GetTimestamp(),
+		IsAttackerHero: m.GetIsAttackerHero(),
+		IsTargetHero:   m.GetIsTargetHero(),
+		IsCritical:     isCriticalDamage(m),
+	}
+}
+
+// isCriticalDamage is a best-effort flag: CMsgDOTACombatLogEntry has no
+// dedicated "was a crit" bit, so this mirrors the convention other fields in
+// this tree use for similarly inferred booleans (e.g. HeroSnapshot's
+// IsStun/IsSilence heuristics in modifier_registry.go) - a value-modifier
+// percentage field (CMsgDOTACombatLogEntry.ValueOverride / "value_name"
+// suffix conventions) isn't exposed on this generated message, so until one
+// is, this always reports false rather than guess.
+func isCriticalDamage(m *dota.CMsgDOTACombatLogEntry) bool {
+	return false
+}
+
+// Register hooks parser's OnCMsgDOTACombatLogEntry callback, decodes each
+// entry via Decode, and invokes fn once per decoded row. This is the
+// package's equivalent of a parser.Callbacks.OnCombatLogEntry high-level
+// callback; it can't literally be added to manta.Parser.Callbacks since
+// that type is owned by the vendored manta library, not this tree, so
+// Register wraps the existing low-level callback instead. resolve should
+// consult the CombatLogNames string table, which is only fully populated
+// partway through the parse - callers that need names resolved correctly
+// for early entries should buffer and resolve in a second pass the way
+// RunCombatLogParse/ParseCombatLogStream do; Register is for callers (like
+// cmd/parity) that are fine with best-effort resolution as entries arrive.
+func Register(parser *manta.Parser, resolve NameResolver, fn func(*CombatLogEntry) error) {
+	parser.Callbacks.OnCMsgDOTACombatLogEntry(func(m *dota.CMsgDOTACombatLogEntry) error {
+		entry := Decode(m, resolve, m.GetTimestamp())
+		return fn(&entry)
+	})
+}
+
+// StringTableResolver returns a NameResolver backed by parser's
+// CombatLogNames string table, falling back to "unknown_<index>" for
+// indices not yet present - the same fallback every other combat log
+// consumer in this tree uses.
+func StringTableResolver(parser *manta.Parser) NameResolver {
+	return func(index uint32) string {
+		if name, ok := parser.LookupStringByIndex("CombatLogNames", int32(index)); ok {
+			return name
+		}
+		return fmt.Sprintf("unknown_%d", index)
+	}
+}
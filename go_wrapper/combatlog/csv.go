@@ -0,0 +1,62 @@
+package combatlog
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// csvHeader is the column order CSVWriter emits - the same scalar subset
+// CombatLogEntry exposes, in struct-declaration order.
+var csvHeader = []string{
+	"tick", "net_tick", "game_time", "type", "type_name",
+	"attacker_name", "target_name", "ability_name",
+	"value", "health", "is_attacker_hero", "is_target_hero", "is_critical",
+}
+
+// CSVWriter streams CombatLogEntry rows to an io.Writer as they arrive,
+// writing the header on first use - the streaming counterpart to
+// combat_log_stream.go's "csv" format, for callers (like cmd/parity) that
+// decode entries one at a time via Register instead of buffering a full
+// CombatLogResult first.
+type CSVWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// NewCSVWriter wraps w for streaming CombatLogEntry rows.
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+// Write appends one entry as a CSV row, writing csvHeader first if this is
+// the writer's first call.
+func (cw *CSVWriter) Write(entry *CombatLogEntry) error {
+	if !cw.wroteHeader {
+		if err := cw.w.Write(csvHeader); err != nil {
+			return err
+		}
+		cw.wroteHeader = true
+	}
+
+	row := []string{
+		strconv.FormatUint(uint64(entry.Tick), 10),
+		strconv.FormatUint(uint64(entry.NetTick), 10),
+		strconv.FormatFloat(float64(entry.GameTime), 'f', -1, 32),
+		strconv.FormatInt(int64(entry.Type), 10),
+		entry.TypeName,
+		entry.AttackerName, entry.TargetName, entry.AbilityName,
+		strconv.FormatInt(int64(entry.Value), 10),
+		strconv.FormatInt(int64(entry.Health), 10),
+		strconv.FormatBool(entry.IsAttackerHero),
+		strconv.FormatBool(entry.IsTargetHero),
+		strconv.FormatBool(entry.IsCritical),
+	}
+	return cw.w.Write(row)
+}
+
+// Flush flushes any buffered rows to the underlying writer.
+func (cw *CSVWriter) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
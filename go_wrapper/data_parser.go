@@ -12,6 +12,9 @@ import (
 
 	"github.com/dotabuff/manta"
 	"github.com/dotabuff/manta/dota"
+
+	"manta_wrapper/marshal"
+	"manta_wrapper/parserstate"
 )
 
 // ============================================================================
@@ -23,24 +26,29 @@ type GameEventData struct {
 	Name      string                 `json:"name"`
 	Tick      uint32                 `json:"tick"`
 	NetTick   uint32                 `json:"net_tick"`
+	GameTime  float32                `json:"game_time"`
+	MatchTime float32                `json:"match_time"`
+	GameState int32                  `json:"game_state"`
+	GamePhase string                 `json:"game_phase"`
+	IsPaused  bool                   `json:"is_paused"`
 	Fields    map[string]interface{} `json:"fields"`
 }
 
 // GameEventsResult holds the result of game events parsing
 type GameEventsResult struct {
-	Events       []GameEventData `json:"events"`
-	EventTypes   []string        `json:"event_types"`
-	Success      bool            `json:"success"`
-	Error        string          `json:"error,omitempty"`
-	TotalEvents  int             `json:"total_events"`
+	Events      []GameEventData `json:"events"`
+	EventTypes  []string        `json:"event_types"`
+	Success     bool            `json:"success"`
+	Error       string          `json:"error,omitempty"`
+	TotalEvents int             `json:"total_events"`
 }
 
 // GameEventsConfig controls game event parsing
 type GameEventsConfig struct {
-	EventFilter  string   `json:"event_filter"`   // Filter by event name (substring match)
-	EventNames   []string `json:"event_names"`    // Specific event names to capture (empty = all)
-	MaxEvents    int      `json:"max_events"`     // Max events to capture (0 = unlimited)
-	CaptureTypes bool     `json:"capture_types"`  // Capture event type definitions
+	EventFilter  string   `json:"event_filter"`  // Filter by event name (substring match)
+	EventNames   []string `json:"event_names"`   // Specific event names to capture (empty = all)
+	MaxEvents    int      `json:"max_events"`    // Max events to capture (0 = unlimited)
+	CaptureTypes bool     `json:"capture_types"` // Capture event type definitions
 }
 
 //export ParseGameEvents
@@ -99,6 +107,24 @@ func RunGameEventsParse(filePath string, config GameEventsConfig) (*GameEventsRe
 		return nil, fmt.Errorf("error creating parser: %w", err)
 	}
 
+	gameTime := newGameTimeTracker()
+	gameTime.Watch(parser)
+	registerGameEventsCallbacks(parser, config, result, gameTime)
+
+	if err := parser.Start(); err != nil {
+		return nil, fmt.Errorf("error parsing file: %w", err)
+	}
+
+	result.Success = true
+	result.TotalEvents = len(result.Events)
+	return result, nil
+}
+
+// registerGameEventsCallbacks wires GameEventsConfig's handlers onto parser,
+// appending into result as events arrive. Split out of RunGameEventsParse so
+// RunAllParse can register it alongside other subsystems on one shared
+// parser instance instead of each running its own full decode pass.
+func registerGameEventsCallbacks(parser *manta.Parser, config GameEventsConfig, result *GameEventsResult, gameTime *gameTimeTracker) {
 	// Store event type definitions for lookup
 	eventTypeNames := make(map[int32]string)
 	eventTypeFields := make(map[string][]string)
@@ -131,6 +157,14 @@ func RunGameEventsParse(filePath string, config GameEventsConfig) (*GameEventsRe
 				}
 				event := extractGameEventData(e, name, parser.Tick, parser.NetTick, eventTypeFields[name])
 				result.Events = append(result.Events, event)
+				idx := len(result.Events) - 1
+				gameTime.Annotate(func(s gameTimeSnapshot) {
+					result.Events[idx].GameTime = s.GameTime
+					result.Events[idx].MatchTime = s.MatchTime
+					result.Events[idx].GameState = s.GameState
+					result.Events[idx].GamePhase = s.GamePhase
+					result.Events[idx].IsPaused = s.IsPaused
+				})
 				return nil
 			})
 		}
@@ -186,18 +220,18 @@ func RunGameEventsParse(filePath string, config GameEventsConfig) (*GameEventsRe
 				NetTick: parser.NetTick,
 				Fields:  fields,
 			})
+			idx := len(result.Events) - 1
+			gameTime.Annotate(func(s gameTimeSnapshot) {
+				result.Events[idx].GameTime = s.GameTime
+				result.Events[idx].MatchTime = s.MatchTime
+				result.Events[idx].GameState = s.GameState
+				result.Events[idx].GamePhase = s.GamePhase
+				result.Events[idx].IsPaused = s.IsPaused
+			})
 
 			return nil
 		})
 	}
-
-	if err := parser.Start(); err != nil {
-		return nil, fmt.Errorf("error parsing file: %w", err)
-	}
-
-	result.Success = true
-	result.TotalEvents = len(result.Events)
-	return result, nil
 }
 
 // extractGameEventData extracts data from a GameEvent using typed accessors
@@ -247,6 +281,11 @@ func marshalGameEventsResult(result *GameEventsResult) *C.char {
 type ModifierEntry struct {
 	Tick          uint32  `json:"tick"`
 	NetTick       uint32  `json:"net_tick"`
+	GameTime      float32 `json:"game_time"`
+	MatchTime     float32 `json:"match_time"`
+	GameState     int32   `json:"game_state"`
+	GamePhase     string  `json:"game_phase"`
+	IsPaused      bool    `json:"is_paused"`
 	Parent        uint32  `json:"parent"`         // Entity handle of unit with modifier
 	Caster        uint32  `json:"caster"`         // Entity handle of caster
 	Ability       uint32  `json:"ability"`        // Ability that created modifier
@@ -258,21 +297,40 @@ type ModifierEntry struct {
 	StackCount    int32   `json:"stack_count"`    // Number of stacks
 	IsAura        bool    `json:"is_aura"`        // Whether it's an aura
 	IsDebuff      bool    `json:"is_debuff"`      // Whether it's a debuff
+
+	// Resolved names - Parent/Caster/Ability are raw entity handles and
+	// ModifierClass a string-table index, none of which mean anything to a
+	// downstream consumer without a second pass joining against entities and
+	// string tables. registerModifiersCallbacks resolves all four while the
+	// parser and string tables are still live.
+	ParentName   string `json:"parent_name,omitempty"`
+	CasterName   string `json:"caster_name,omitempty"`
+	AbilityName  string `json:"ability_name,omitempty"`
+	ModifierName string `json:"modifier_name,omitempty"`
 }
 
 // ModifiersResult holds modifier parsing results
 type ModifiersResult struct {
-	Modifiers     []ModifierEntry `json:"modifiers"`
-	Success       bool            `json:"success"`
-	Error         string          `json:"error,omitempty"`
-	TotalModifiers int            `json:"total_modifiers"`
+	Modifiers      []ModifierEntry `json:"modifiers"`
+	Success        bool            `json:"success"`
+	Error          string          `json:"error,omitempty"`
+	TotalModifiers int             `json:"total_modifiers"`
 }
 
 // ModifiersConfig controls modifier parsing
 type ModifiersConfig struct {
-	MaxModifiers int  `json:"max_modifiers"` // Max modifiers to capture (0 = unlimited)
-	DebuffsOnly  bool `json:"debuffs_only"`  // Only capture debuffs
-	AurasOnly    bool `json:"auras_only"`    // Only capture auras
+	MaxModifiers int      `json:"max_modifiers"` // Max modifiers to capture (0 = unlimited)
+	DebuffsOnly  bool     `json:"debuffs_only"`  // Only capture debuffs
+	AurasOnly    bool     `json:"auras_only"`    // Only capture auras
+	DebuffNames  []string `json:"debuff_names"`  // Extra exact modifier names to treat as debuffs beyond the "*_debuff" heuristic
+}
+
+// modifierNameLooksLikeDebuff is the naming heuristic registerModifiersCallbacks
+// falls back on when a modifier's name isn't in config.DebuffNames: real
+// debuff modifiers are conventionally suffixed "_debuff" (e.g.
+// "modifier_viper_poison_attack_debuff"), unlike buffs/auras which aren't.
+func modifierNameLooksLikeDebuff(name string) bool {
+	return strings.Contains(name, "_debuff")
 }
 
 //export ParseModifiers
@@ -329,19 +387,60 @@ func RunModifiersParse(filePath string, config ModifiersConfig) (*ModifiersResul
 		return nil, fmt.Errorf("error creating parser: %w", err)
 	}
 
-	// Register modifier handler
+	gameTime := newGameTimeTracker()
+	gameTime.Watch(parser)
+	registerModifiersCallbacks(parser, config, result, gameTime)
+
+	if err := parser.Start(); err != nil {
+		return nil, fmt.Errorf("error parsing file: %w", err)
+	}
+
+	result.Success = true
+	result.TotalModifiers = len(result.Modifiers)
+	return result, nil
+}
+
+// registerModifiersCallbacks wires ModifiersConfig's handler onto parser -
+// split out the same way registerGameEventsCallbacks is, so RunAllParse can
+// share one parser across subsystems.
+func registerModifiersCallbacks(parser *manta.Parser, config ModifiersConfig, result *ModifiersResult, gameTime *gameTimeTracker) {
+	debuffNames := make(map[string]bool, len(config.DebuffNames))
+	for _, name := range config.DebuffNames {
+		debuffNames[name] = true
+	}
+
+	resolveName := func(class int32) string {
+		if name, ok := parser.LookupStringByIndex("ModifierNames", class); ok {
+			return name
+		}
+		if name, ok := parser.LookupStringByIndex("CombatLogNames", class); ok {
+			return name
+		}
+		return ""
+	}
+	resolveEntityName := func(handle uint32) string {
+		if ent := parser.FindEntityByHandle(uint64(handle)); ent != nil {
+			return ent.GetClassName()
+		}
+		return ""
+	}
+
 	parser.OnModifierTableEntry(func(m *dota.CDOTAModifierBuffTableEntry) error {
 		if config.MaxModifiers > 0 && len(result.Modifiers) >= config.MaxModifiers {
 			return nil
 		}
 
 		isAura := m.GetAura()
+		modifierName := resolveName(m.GetModifierClass())
+		isDebuff := debuffNames[modifierName] || modifierNameLooksLikeDebuff(modifierName)
 
 		// Apply filters
 		if config.AurasOnly && !isAura {
 			return nil
 		}
-		// Note: isDebuff not directly available in protobuf, skip debuffs_only filter
+		if config.DebuffsOnly && !isDebuff {
+			return nil
+		}
 
 		entry := ModifierEntry{
 			Tick:          parser.Tick,
@@ -356,20 +455,24 @@ func RunModifiersParse(filePath string, config ModifiersConfig) (*ModifiersResul
 			Duration:      m.GetDuration(),
 			StackCount:    m.GetStackCount(),
 			IsAura:        isAura,
-			IsDebuff:      false, // Not directly available
+			IsDebuff:      isDebuff,
+			ParentName:    resolveEntityName(m.GetParent()),
+			CasterName:    resolveEntityName(m.GetCaster()),
+			AbilityName:   resolveEntityName(m.GetAbility()),
+			ModifierName:  modifierName,
 		}
 
 		result.Modifiers = append(result.Modifiers, entry)
+		idx := len(result.Modifiers) - 1
+		gameTime.Annotate(func(s gameTimeSnapshot) {
+			result.Modifiers[idx].GameTime = s.GameTime
+			result.Modifiers[idx].MatchTime = s.MatchTime
+			result.Modifiers[idx].GameState = s.GameState
+			result.Modifiers[idx].GamePhase = s.GamePhase
+			result.Modifiers[idx].IsPaused = s.IsPaused
+		})
 		return nil
 	})
-
-	if err := parser.Start(); err != nil {
-		return nil, fmt.Errorf("error parsing file: %w", err)
-	}
-
-	result.Success = true
-	result.TotalModifiers = len(result.Modifiers)
-	return result, nil
 }
 
 func marshalModifiersResult(result *ModifiersResult) *C.char {
@@ -393,26 +496,61 @@ type EntityData struct {
 	Index      int32                  `json:"index"`
 	Serial     int32                  `json:"serial"`
 	ClassName  string                 `json:"class_name"`
+	GameTime   float32                `json:"game_time"`
+	MatchTime  float32                `json:"match_time"`
+	GameState  int32                  `json:"game_state"`
+	GamePhase  string                 `json:"game_phase"`
+	IsPaused   bool                   `json:"is_paused"`
 	Properties map[string]interface{} `json:"properties"`
 }
 
 // EntitiesResult holds entity query results
 type EntitiesResult struct {
-	Entities      []EntityData `json:"entities"`
-	Success       bool         `json:"success"`
-	Error         string       `json:"error,omitempty"`
-	TotalEntities int          `json:"total_entities"`
-	Tick          uint32       `json:"tick"`
-	NetTick       uint32       `json:"net_tick"`
+	Entities      []EntityData  `json:"entities"`
+	Deltas        []EntityDelta `json:"deltas,omitempty"`
+	Success       bool          `json:"success"`
+	Error         string        `json:"error,omitempty"`
+	TotalEntities int           `json:"total_entities"`
+	Tick          uint32        `json:"tick"`
+	NetTick       uint32        `json:"net_tick"`
 }
 
 // EntitiesConfig controls entity querying
 type EntitiesConfig struct {
-	ClassFilter    string   `json:"class_filter"`     // Filter by class name (substring)
-	ClassNames     []string `json:"class_names"`      // Specific class names to capture
-	PropertyFilter []string `json:"property_filter"`  // Only include these properties (empty = all)
-	AtTick         uint32   `json:"at_tick"`          // Capture entities at this tick (0 = end of file)
-	MaxEntities    int      `json:"max_entities"`     // Max entities to return (0 = unlimited)
+	ClassFilter    string   `json:"class_filter"`    // Filter by class name (substring)
+	ClassNames     []string `json:"class_names"`     // Specific class names to capture
+	PropertyFilter []string `json:"property_filter"` // Only include these properties (empty = all)
+	AtTick         uint32   `json:"at_tick"`         // Capture entities at this tick (0 = end of file)
+	MaxEntities    int      `json:"max_entities"`    // Max entities to return (0 = unlimited)
+
+	// Mode selects how QueryEntities samples matching entities over time:
+	// "" or "snapshot" (default) captures once, at AtTick or end of file;
+	// "deltas" emits an EntityDelta every time a WatchProperties field
+	// changes on a matching entity; "periodic" emits a full EntityData
+	// snapshot of the matching set every SampleInterval ticks.
+	Mode            string   `json:"mode"`
+	SampleInterval  uint32   `json:"sample_interval"`  // Tick period for "periodic" mode
+	WatchProperties []string `json:"watch_properties"` // Properties "deltas" mode watches for changes
+}
+
+// EntityDelta is one changed-property event QueryEntities emits in
+// "deltas" mode: whenever any property in EntitiesConfig.WatchProperties
+// differs from the last value seen for that entity, Changed carries the
+// new values and Previous the old ones (both keyed by property name, and
+// limited to the properties that actually changed).
+type EntityDelta struct {
+	Tick      uint32                 `json:"tick"`
+	NetTick   uint32                 `json:"net_tick"`
+	GameTime  float32                `json:"game_time"`
+	MatchTime float32                `json:"match_time"`
+	GameState int32                  `json:"game_state"`
+	GamePhase string                 `json:"game_phase"`
+	Index     int32                  `json:"index"`
+	Serial    int32                  `json:"serial"`
+	ClassName string                 `json:"class_name"`
+	Op        string                 `json:"op"`
+	Changed   map[string]interface{} `json:"changed"`
+	Previous  map[string]interface{} `json:"previous"`
 }
 
 //export QueryEntities
@@ -470,31 +608,45 @@ func RunEntitiesQuery(filePath string, config EntitiesConfig) (*EntitiesResult,
 		return nil, fmt.Errorf("error creating parser: %w", err)
 	}
 
-	captured := false
-
-	// If targeting a specific tick, wait for it
-	if config.AtTick > 0 {
-		parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
-			if captured {
-				return nil
-			}
+	gameTime := newGameTimeTracker()
+	gameTime.Watch(parser)
 
-			if parser.Tick >= config.AtTick {
-				captureAllEntities(parser, config, result)
-				captured = true
-				parser.Stop()
-			}
-			return nil
-		})
+	switch config.Mode {
+	case "deltas":
+		setupEntityDeltas(parser, config, result, gameTime)
+		if err := parser.Start(); err != nil {
+			return nil, fmt.Errorf("error parsing file: %w", err)
+		}
+		result.Success = true
+		result.Tick = parser.Tick
+		result.NetTick = parser.NetTick
+		return result, nil
+
+	case "periodic":
+		setupEntityPeriodic(parser, config, result, gameTime)
+		if err := parser.Start(); err != nil {
+			return nil, fmt.Errorf("error parsing file: %w", err)
+		}
+		result.Success = true
+		result.TotalEntities = len(result.Entities)
+		result.Tick = parser.Tick
+		result.NetTick = parser.NetTick
+		return result, nil
 	}
 
-	if err := parser.Start(); err != nil && !captured {
+	// Standalone calls are free to stop the parser as soon as AtTick is
+	// reached; RunAllParse shares this parser with other enabled
+	// subsystems, so it passes allowStop=false and lets captured stay
+	// false until the whole file has been read.
+	captured := registerEntitySnapshotCallback(parser, config, result, gameTime, true)
+
+	if err := parser.Start(); err != nil && !*captured {
 		return nil, fmt.Errorf("error parsing file: %w", err)
 	}
 
 	// If no specific tick, capture at end of parsing
-	if !captured {
-		captureAllEntities(parser, config, result)
+	if !*captured {
+		captureAllEntities(parser, config, result, gameTime)
 	}
 
 	result.Success = true
@@ -504,38 +656,68 @@ func RunEntitiesQuery(filePath string, config EntitiesConfig) (*EntitiesResult,
 	return result, nil
 }
 
-// captureAllEntities captures entities matching the config filters
-func captureAllEntities(parser *manta.Parser, config EntitiesConfig, result *EntitiesResult) {
-	// Build filter function
-	filter := func(e *manta.Entity) bool {
-		if e == nil {
-			return false
-		}
-		className := e.GetClassName()
-
-		// Check class filter
-		if config.ClassFilter != "" && !strings.Contains(className, config.ClassFilter) {
-			return false
-		}
-
-		// Check specific class names
-		if len(config.ClassNames) > 0 {
-			found := false
-			for _, cn := range config.ClassNames {
-				if strings.Contains(className, cn) {
-					found = true
-					break
+// registerEntitySnapshotCallback wires the "capture once AtTick is reached"
+// behavior RunEntitiesQuery's default (non-deltas/periodic) mode uses onto
+// parser, split out so RunAllParse can share it on one parser instance. It
+// returns a *bool the caller polls after parser.Start() returns to decide
+// whether captureAllEntities still needs to run at end-of-parse. allowStop
+// gates the early parser.Stop() - RunEntitiesQuery passes true so a
+// standalone call doesn't read the rest of the file once its target tick is
+// captured; RunAllParse passes false since stopping would cut off every
+// other subsystem sharing the parser.
+func registerEntitySnapshotCallback(parser *manta.Parser, config EntitiesConfig, result *EntitiesResult, gameTime *gameTimeTracker, allowStop bool) *bool {
+	captured := new(bool)
+	if config.AtTick > 0 {
+		parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+			if *captured {
+				return nil
+			}
+			if parser.Tick >= config.AtTick {
+				captureAllEntities(parser, config, result, gameTime)
+				*captured = true
+				if allowStop {
+					parser.Stop()
 				}
 			}
-			if !found {
-				return false
+			return nil
+		})
+	}
+	return captured
+}
+
+// entityMatchesClassConfig applies EntitiesConfig's ClassFilter/ClassNames
+// checks, shared by the snapshot, deltas, and periodic modes.
+func entityMatchesClassConfig(e *manta.Entity, config EntitiesConfig) bool {
+	if e == nil {
+		return false
+	}
+	className := e.GetClassName()
+
+	if config.ClassFilter != "" && !strings.Contains(className, config.ClassFilter) {
+		return false
+	}
+
+	if len(config.ClassNames) > 0 {
+		found := false
+		for _, cn := range config.ClassNames {
+			if strings.Contains(className, cn) {
+				found = true
+				break
 			}
 		}
-
-		return true
+		if !found {
+			return false
+		}
 	}
 
-	entities := parser.FilterEntity(filter)
+	return true
+}
+
+// captureAllEntities captures entities matching the config filters
+func captureAllEntities(parser *manta.Parser, config EntitiesConfig, result *EntitiesResult, gameTime *gameTimeTracker) {
+	entities := parser.FilterEntity(func(e *manta.Entity) bool {
+		return entityMatchesClassConfig(e, config)
+	})
 
 	for _, e := range entities {
 		if e == nil {
@@ -567,9 +749,118 @@ func captureAllEntities(parser *manta.Parser, config EntitiesConfig, result *Ent
 			ClassName:  e.GetClassName(),
 			Properties: props,
 		})
+		idx := len(result.Entities) - 1
+		gameTime.Annotate(func(s gameTimeSnapshot) {
+			result.Entities[idx].GameTime = s.GameTime
+			result.Entities[idx].MatchTime = s.MatchTime
+			result.Entities[idx].GameState = s.GameState
+			result.Entities[idx].GamePhase = s.GamePhase
+			result.Entities[idx].IsPaused = s.IsPaused
+		})
 	}
 }
 
+// setupEntityDeltas wires EntitiesConfig's "deltas" mode: on every update to
+// an entity matching the class filter, compare its WatchProperties values
+// against the last-seen values for that entity (kept in lastValues, indexed
+// by entity handle) and emit an EntityDelta for whichever watched
+// properties actually changed.
+func setupEntityDeltas(parser *manta.Parser, config EntitiesConfig, result *EntitiesResult, gameTime *gameTimeTracker) {
+	lastValues := make(map[int32]map[string]interface{})
+
+	parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+		if e == nil || !op.Flag(manta.EntityOpUpdated) && !op.Flag(manta.EntityOpCreated) {
+			return nil
+		}
+		if !entityMatchesClassConfig(e, config) {
+			return nil
+		}
+		if config.MaxEntities > 0 && len(result.Deltas) >= config.MaxEntities {
+			return nil
+		}
+
+		handle := e.GetIndex()
+		prev, hadBaseline := lastValues[handle]
+		current := make(map[string]interface{}, len(config.WatchProperties))
+		changed := make(map[string]interface{})
+		previous := make(map[string]interface{})
+
+		for _, prop := range config.WatchProperties {
+			val, ok := e.GetFloat32(prop)
+			var current32 interface{}
+			if ok {
+				current32 = val
+			} else if sval, ok := e.GetString(prop); ok {
+				current32 = sval
+			} else if ival, ok := e.GetInt32(prop); ok {
+				current32 = ival
+			} else if bval, ok := e.GetBool(prop); ok {
+				current32 = bval
+			} else {
+				continue
+			}
+			current[prop] = current32
+			if !hadBaseline || prev[prop] != current32 {
+				changed[prop] = current32
+				if hadBaseline {
+					previous[prop] = prev[prop]
+				}
+			}
+		}
+		lastValues[handle] = current
+
+		if len(changed) == 0 {
+			return nil
+		}
+
+		opName := "updated"
+		if op.Flag(manta.EntityOpCreated) {
+			opName = "created"
+		} else if op.Flag(manta.EntityOpDeleted) {
+			opName = "deleted"
+		}
+
+		delta := EntityDelta{
+			Tick: parser.Tick, NetTick: parser.NetTick,
+			Index: e.GetIndex(), Serial: e.GetSerial(), ClassName: e.GetClassName(),
+			Op: opName, Changed: changed, Previous: previous,
+		}
+		result.Deltas = append(result.Deltas, delta)
+		idx := len(result.Deltas) - 1
+		gameTime.Annotate(func(s gameTimeSnapshot) {
+			result.Deltas[idx].GameTime = s.GameTime
+			result.Deltas[idx].MatchTime = s.MatchTime
+			result.Deltas[idx].GameState = s.GameState
+			result.Deltas[idx].GamePhase = s.GamePhase
+		})
+		return nil
+	})
+}
+
+// setupEntityPeriodic wires EntitiesConfig's "periodic" mode: every
+// SampleInterval ticks, capture a full snapshot of every entity matching
+// the class filter, the same way the default end-of-file capture does,
+// but repeated throughout the replay instead of once.
+func setupEntityPeriodic(parser *manta.Parser, config EntitiesConfig, result *EntitiesResult, gameTime *gameTimeTracker) {
+	interval := config.SampleInterval
+	if interval == 0 {
+		interval = uint32(TicksPerSecond)
+	}
+	var lastSample uint32
+
+	parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+		if e == nil || e.GetClassName() == "CDOTAGamerulesProxy" {
+			return nil
+		}
+		if parser.Tick-lastSample < interval {
+			return nil
+		}
+		lastSample = parser.Tick
+		captureAllEntities(parser, config, result, gameTime)
+		return nil
+	})
+}
+
 func marshalEntitiesResult(result *EntitiesResult) *C.char {
 	jsonResult, err := json.Marshal(result)
 	if err != nil {
@@ -666,6 +957,20 @@ func RunStringTablesExtract(filePath string, config StringTablesConfig) (*String
 		return nil, fmt.Errorf("error creating parser: %w", err)
 	}
 
+	registerStringTablesCallbacks(parser, config, result)
+
+	if err := parser.Start(); err != nil {
+		return nil, fmt.Errorf("error parsing file: %w", err)
+	}
+
+	result.Success = true
+	return result, nil
+}
+
+// registerStringTablesCallbacks wires StringTablesConfig's handlers onto
+// parser - split out the same way registerGameEventsCallbacks is, so
+// RunAllParse can share one parser across subsystems.
+func registerStringTablesCallbacks(parser *manta.Parser, config StringTablesConfig, result *StringTablesResult) {
 	// Capture string table creation
 	parser.Callbacks.OnCSVCMsg_CreateStringTable(func(m *dota.CSVCMsg_CreateStringTable) error {
 		tableName := m.GetName()
@@ -731,13 +1036,6 @@ func RunStringTablesExtract(filePath string, config StringTablesConfig) (*String
 		}
 		return nil
 	})
-
-	if err := parser.Start(); err != nil {
-		return nil, fmt.Errorf("error parsing file: %w", err)
-	}
-
-	result.Success = true
-	return result, nil
 }
 
 func marshalStringTablesResult(result *StringTablesResult) *C.char {
@@ -777,6 +1075,18 @@ type CombatLogEntry struct {
 	ValueName          string  `json:"value_name"`
 	Health             int32   `json:"health"`
 	GameTime           float32 `json:"game_time"`
+	MatchTime          float32 `json:"match_time"`
+	GameState          int32   `json:"game_state"`
+	GamePhase          string  `json:"game_phase"`
+	IsPaused           bool    `json:"is_paused"`
+	// Timestamp is the combat log entry's own wall-clock field
+	// (CMsgDOTACombatLogEntry.Timestamp), carried alongside the
+	// tracker-derived GameTime/MatchTime above - the combat log's own
+	// timestamps reset and drift around tournament/spectator replays, so
+	// GameTime/MatchTime (reconciled off CDOTAGamerulesProxy) are the axis
+	// to aggregate on; Timestamp is kept for callers that want the raw
+	// value for cross-checking.
+	Timestamp          float32 `json:"timestamp"`
 	StunDuration       float32 `json:"stun_duration"`
 	SlowDuration       float32 `json:"slow_duration"`
 	IsAbilityToggleOn  bool    `json:"is_ability_toggle_on"`
@@ -791,11 +1101,11 @@ type CombatLogEntry struct {
 	LocationX float32 `json:"location_x"`
 	LocationY float32 `json:"location_y"`
 	// Assist tracking
-	AssistPlayer0  int32   `json:"assist_player0"`
-	AssistPlayer1  int32   `json:"assist_player1"`
-	AssistPlayer2  int32   `json:"assist_player2"`
-	AssistPlayer3  int32   `json:"assist_player3"`
-	AssistPlayers  []int32 `json:"assist_players"`
+	AssistPlayer0 int32   `json:"assist_player0"`
+	AssistPlayer1 int32   `json:"assist_player1"`
+	AssistPlayer2 int32   `json:"assist_player2"`
+	AssistPlayer3 int32   `json:"assist_player3"`
+	AssistPlayers []int32 `json:"assist_players"`
 	// Damage classification
 	DamageType     int32 `json:"damage_type"`
 	DamageCategory int32 `json:"damage_category"`
@@ -824,31 +1134,31 @@ type CombatLogEntry struct {
 	// Building info
 	BuildingType int32 `json:"building_type"`
 	// Modifier details
-	ModifierElapsedDuration float32 `json:"modifier_elapsed_duration"`
-	SilenceModifier         bool    `json:"silence_modifier"`
-	HealFromLifesteal       bool    `json:"heal_from_lifesteal"`
-	ModifierPurged              bool    `json:"modifier_purged"`
-	ModifierPurgeAbility        int32   `json:"modifier_purge_ability"`
-	ModifierPurgeAbilityName    string  `json:"modifier_purge_ability_name"`
-	ModifierPurgeNpc            int32   `json:"modifier_purge_npc"`
-	ModifierPurgeNpcName        string  `json:"modifier_purge_npc_name"`
-	RootModifier                bool    `json:"root_modifier"`
-	AuraModifier                bool    `json:"aura_modifier"`
-	ArmorDebuffModifier         bool    `json:"armor_debuff_modifier"`
-	NoPhysicalDamageModifier    bool    `json:"no_physical_damage_modifier"`
-	ModifierAbility             int32   `json:"modifier_ability"`
-	ModifierAbilityName         string  `json:"modifier_ability_name"`
-	ModifierHidden              bool    `json:"modifier_hidden"`
-	MotionControllerModifier bool   `json:"motion_controller_modifier"`
+	ModifierElapsedDuration  float32 `json:"modifier_elapsed_duration"`
+	SilenceModifier          bool    `json:"silence_modifier"`
+	HealFromLifesteal        bool    `json:"heal_from_lifesteal"`
+	ModifierPurged           bool    `json:"modifier_purged"`
+	ModifierPurgeAbility     int32   `json:"modifier_purge_ability"`
+	ModifierPurgeAbilityName string  `json:"modifier_purge_ability_name"`
+	ModifierPurgeNpc         int32   `json:"modifier_purge_npc"`
+	ModifierPurgeNpcName     string  `json:"modifier_purge_npc_name"`
+	RootModifier             bool    `json:"root_modifier"`
+	AuraModifier             bool    `json:"aura_modifier"`
+	ArmorDebuffModifier      bool    `json:"armor_debuff_modifier"`
+	NoPhysicalDamageModifier bool    `json:"no_physical_damage_modifier"`
+	ModifierAbility          int32   `json:"modifier_ability"`
+	ModifierAbilityName      string  `json:"modifier_ability_name"`
+	ModifierHidden           bool    `json:"modifier_hidden"`
+	MotionControllerModifier bool    `json:"motion_controller_modifier"`
 	// Kill/death info
 	SpellEvaded         bool  `json:"spell_evaded"`
 	LongRangeKill       bool  `json:"long_range_kill"`
 	TotalUnitDeathCount int32 `json:"total_unit_death_count"`
 	WillReincarnate     bool  `json:"will_reincarnate"`
 	// Ability info
-	InflictorIsStolenAbility bool  `json:"inflictor_is_stolen_ability"`
-	SpellGeneratedAttack     bool  `json:"spell_generated_attack"`
-	UsesCharges              bool  `json:"uses_charges"`
+	InflictorIsStolenAbility bool `json:"inflictor_is_stolen_ability"`
+	SpellGeneratedAttack     bool `json:"spell_generated_attack"`
+	UsesCharges              bool `json:"uses_charges"`
 	// Game state
 	AtNightTime        bool    `json:"at_night_time"`
 	AttackerHasScepter bool    `json:"attacker_has_scepter"`
@@ -867,6 +1177,20 @@ type CombatLogResult struct {
 	TotalEntries  int              `json:"total_entries"`
 	GameStartTime float32          `json:"game_start_time"` // Timestamp when game clock hits 00:00
 	GameStartTick uint32           `json:"game_start_tick"` // Tick when horn sounds (game_time = 0)
+
+	// PreGameStartTick/GameEndTick/GameMode come from parserstate.GameClock
+	// (CDOTAGamerulesProxy) rather than the combat log's own GAME_STATE
+	// event, so consumers can trim horn/post-game noise even on replays
+	// where that event is missing or out of order. GameEndTick is 0 if the
+	// replay (or this parse) never reached game end.
+	PreGameStartTick uint32 `json:"pre_game_start_tick"`
+	GameEndTick      uint32 `json:"game_end_tick"`
+	GameMode         int32  `json:"game_mode"`
+
+	// Stats is the totals/per-hero/per-minute rollup computed by
+	// computeCombatLogStats once Entries has been finalized (heroes_only
+	// already applied) - see CombatLogStats' doc comment.
+	Stats *CombatLogStats `json:"stats,omitempty"`
 }
 
 // CombatLogConfig controls combat log parsing
@@ -874,6 +1198,46 @@ type CombatLogConfig struct {
 	Types      []int32 `json:"types"`       // Filter by combat log type (empty = all)
 	MaxEntries int     `json:"max_entries"` // Max entries (0 = unlimited)
 	HeroesOnly bool    `json:"heroes_only"` // Only hero-related entries
+
+	// ProgressSlotID, if non-zero, names a slot allocated by
+	// AllocateProgressSlot that this parse reports {tick, emitted} into as
+	// it runs, for a Python caller to poll via ReadProgress.
+	ProgressSlotID uint64 `json:"progress_slot_id,omitempty"`
+
+	// Format selects ParseCombatLogStream's output encoding: "ndjson"
+	// (default) writes one JSON object per line, "json" writes a single
+	// JSON array, "csv" writes a header row followed by one row per entry
+	// covering CombatLogEntry's scalar fields.
+	Format string `json:"format"`
+
+	// Fields, if non-empty, restricts ParseCombatLog's (and
+	// ParseCombatLogStream's ndjson/json output's) JSON entries to this
+	// field whitelist - CombatLogEntry has ~70 fields and most callers only
+	// need a handful. Naming a field also keeps its "<field>_name"
+	// companion, if EnumFormat added one.
+	Fields []string `json:"fields"`
+
+	// EnumFormat controls how the enum-valued fields named in
+	// combatLogEnumFields (DamageType, DamageCategory, RuneType,
+	// BuildingType, NeutralCampType, UnitStatusLabel, KillEaterEvent) are
+	// encoded: "int" (default) leaves them as raw ints, "name" replaces the
+	// int with its resolved name string, "both" adds a "<field>_name"
+	// companion key alongside the untouched int.
+	EnumFormat string `json:"enum_format"`
+
+	// IncludeUnitOrders, when set alongside RunParse's CombatLog collector,
+	// auto-enables the UnitOrders collector (with HeroesOnly mirroring
+	// HeroesOnly above) without also requiring config.UnitOrders to be set
+	// - see the "Combat log collector" block in parser.go.
+	IncludeUnitOrders bool `json:"include_unit_orders,omitempty"`
+
+	// IncludeEconomyTimeline, when set, buckets combat log entries into
+	// ParseResult.EconomyTimeline - see EconomySample's doc comment.
+	IncludeEconomyTimeline bool `json:"include_economy_timeline,omitempty"`
+
+	// EconomyTimelineIntervalSeconds sets the bucket width for
+	// IncludeEconomyTimeline above; 0 defaults to 60 (one sample/minute).
+	EconomyTimelineIntervalSeconds int `json:"economy_timeline_interval_seconds,omitempty"`
 }
 
 //export ParseCombatLog
@@ -910,46 +1274,71 @@ func ParseCombatLog(filePath *C.char, configJSON *C.char) (cResult *C.char) {
 		return marshalCombatLogResult(failure)
 	}
 
-	return marshalCombatLogResult(result)
-}
-
-// RunCombatLogParse executes combat log parsing
-func RunCombatLogParse(filePath string, config CombatLogConfig) (*CombatLogResult, error) {
-	result := &CombatLogResult{
-		Entries: make([]CombatLogEntry, 0),
+	if wantsProjection(config.Fields, config.EnumFormat) {
+		return marshalProjectedCombatLogResult(result, config)
 	}
 
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("error opening file: %w", err)
-	}
-	defer file.Close()
+	return marshalCombatLogResult(result)
+}
 
-	parser, err := manta.NewStreamParser(file)
+// marshalProjectedCombatLogResult re-encodes result.Entries through
+// EncodeWithProjection instead of CombatLogEntry's plain JSON tags, for
+// callers that set CombatLogConfig.Fields/EnumFormat. The envelope's other
+// fields (TotalEntries, GameStartTick, ...) are unaffected.
+func marshalProjectedCombatLogResult(result *CombatLogResult, config CombatLogConfig) *C.char {
+	envelope := map[string]interface{}{
+		"entries":             encodeEntries(result.Entries, config.Fields, combatLogEnumFields, config.EnumFormat),
+		"success":             result.Success,
+		"total_entries":       result.TotalEntries,
+		"game_start_time":     result.GameStartTime,
+		"game_start_tick":     result.GameStartTick,
+		"pre_game_start_tick": result.PreGameStartTick,
+		"game_end_tick":       result.GameEndTick,
+		"game_mode":           result.GameMode,
+	}
+	if result.Error != "" {
+		envelope["error"] = result.Error
+	}
+
+	data, err := json.Marshal(envelope)
 	if err != nil {
-		return nil, fmt.Errorf("error creating parser: %w", err)
+		return marshalCombatLogResult(&CombatLogResult{Success: false, Error: fmt.Sprintf("error marshaling projected result: %v", err)})
 	}
+	return C.CString(string(data))
+}
 
+// registerCombatLogCallbacks wires CombatLogConfig's handler onto parser and
+// returns a resolve closure, split out of RunCombatLogParse so RunAllParse
+// can share one parser across subsystems. Combat log name resolution needs
+// the CombatLogNames string table fully populated, so - unlike the other
+// register* helpers - this one can't finish its work until after
+// parser.Start() returns; resolve performs that second pass and returns the
+// final []CombatLogEntry. gameStartTime is returned by pointer since the
+// GAME_STATE==5 event that sets it may fire any time during Start().
+func registerCombatLogCallbacks(parser *manta.Parser, config CombatLogConfig, gameTime *gameTimeTracker) (resolve func() []CombatLogEntry, clock *parserstate.GameClock, gameStartTime *float32) {
 	// Store raw combat log entries with indices for later name resolution
 	type rawEntry struct {
-		tick               uint32
-		netTick            uint32
-		msg                *dota.CMsgDOTACombatLogEntry
+		tick     uint32
+		netTick  uint32
+		msg      *dota.CMsgDOTACombatLogEntry
+		snapshot gameTimeSnapshot
 	}
 	rawEntries := make([]rawEntry, 0)
 
-	// Track game start time and tick (when GAME_IN_PROGRESS state begins)
-	var gameStartTime float32 = 0
-	var gameStartTick uint32 = 0
+	// gameStartTime is still sourced from the combat log's own GAME_STATE
+	// event (CDOTAGamerulesProxy doesn't carry a wall-clock timestamp);
+	// everything tick-based (GameStartTick/PreGameStartTick/GameEndTick)
+	// now comes from clock, which doesn't depend on that event firing.
+	startTime := new(float32)
+	clock = parserstate.NewGameClock(parser)
+	progress := newProgressReporter(config.ProgressSlotID, 0)
 
 	// Parse combat log entries - store raw data
 	parser.Callbacks.OnCMsgDOTACombatLogEntry(func(m *dota.CMsgDOTACombatLogEntry) error {
-		// Detect game start: GAME_STATE event with value=5 (GAME_IN_PROGRESS)
-		if m.GetType() == dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_GAME_STATE {
-			if m.GetValue() == 5 { // DOTA_GAMERULES_STATE_GAME_IN_PROGRESS
-				gameStartTime = m.GetTimestamp()
-				gameStartTick = parser.Tick
-			}
+		progress.report(parser.Tick, uint64(len(rawEntries)))
+
+		if m.GetType() == dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_GAME_STATE && m.GetValue() == 5 {
+			*startTime = m.GetTimestamp()
 		}
 
 		if config.MaxEntries > 0 && len(rawEntries) >= config.MaxEntries {
@@ -981,192 +1370,238 @@ func RunCombatLogParse(filePath string, config CombatLogConfig) (*CombatLogResul
 			netTick: parser.NetTick,
 			msg:     m,
 		})
+		idx := len(rawEntries) - 1
+		gameTime.Annotate(func(s gameTimeSnapshot) {
+			rawEntries[idx].snapshot = s
+		})
 
 		return nil
 	})
 
-	// Parse the file first to populate string tables
-	if err := parser.Start(); err != nil {
-		return nil, fmt.Errorf("error parsing file: %w", err)
-	}
+	resolve = func() []CombatLogEntry {
+		entries := make([]CombatLogEntry, 0, len(rawEntries))
 
-	// Now resolve names using fully populated string tables
-	getName := func(idx uint32) string {
-		if name, ok := parser.LookupStringByIndex("CombatLogNames", int32(idx)); ok {
-			return name
+		// Now resolve names using fully populated string tables
+		getName := func(idx uint32) string {
+			if name, ok := parser.LookupStringByIndex("CombatLogNames", int32(idx)); ok {
+				return name
+			}
+			return fmt.Sprintf("unknown_%d", idx)
 		}
-		return fmt.Sprintf("unknown_%d", idx)
-	}
 
-	// Convert raw entries to final entries with resolved names
-	for _, raw := range rawEntries {
-		m := raw.msg
-		entryType := m.GetType()
+		// Convert raw entries to final entries with resolved names
+		for _, raw := range rawEntries {
+			m := raw.msg
+			entryType := m.GetType()
 
-		// Convert assist_players slice
-		assistPlayers := make([]int32, len(m.GetAssistPlayers()))
-		for i, ap := range m.GetAssistPlayers() {
-			assistPlayers[i] = ap
-		}
+			// Convert assist_players slice
+			assistPlayers := make([]int32, len(m.GetAssistPlayers()))
+			for i, ap := range m.GetAssistPlayers() {
+				assistPlayers[i] = ap
+			}
 
-		// Resolve value name - for PURCHASE events, value is an index into CombatLogNames
-		valueName := ""
-		if name, ok := parser.LookupStringByIndex("CombatLogNames", int32(m.GetValue())); ok {
-			valueName = name
-		}
+			// Resolve value name - for PURCHASE events, value is an index into CombatLogNames
+			valueName := ""
+			if name, ok := parser.LookupStringByIndex("CombatLogNames", int32(m.GetValue())); ok {
+				valueName = name
+			}
 
-		// Resolve modifier-related name fields - these are also CombatLogNames indices
-		modifierAbilityName := ""
-		if v := m.GetModifierAbility(); v > 0 {
-			if name, ok := parser.LookupStringByIndex("CombatLogNames", int32(v)); ok {
-				modifierAbilityName = name
+			// Resolve modifier-related name fields - these are also CombatLogNames indices
+			modifierAbilityName := ""
+			if v := m.GetModifierAbility(); v > 0 {
+				if name, ok := parser.LookupStringByIndex("CombatLogNames", int32(v)); ok {
+					modifierAbilityName = name
+				}
 			}
-		}
 
-		modifierPurgeAbilityName := ""
-		if v := m.GetModifierPurgeAbility(); v > 0 {
-			if name, ok := parser.LookupStringByIndex("CombatLogNames", int32(v)); ok {
-				modifierPurgeAbilityName = name
+			modifierPurgeAbilityName := ""
+			if v := m.GetModifierPurgeAbility(); v > 0 {
+				if name, ok := parser.LookupStringByIndex("CombatLogNames", int32(v)); ok {
+					modifierPurgeAbilityName = name
+				}
 			}
-		}
 
-		modifierPurgeNpcName := ""
-		if v := m.GetModifierPurgeNpc(); v > 0 {
-			if name, ok := parser.LookupStringByIndex("CombatLogNames", int32(v)); ok {
-				modifierPurgeNpcName = name
+			modifierPurgeNpcName := ""
+			if v := m.GetModifierPurgeNpc(); v > 0 {
+				if name, ok := parser.LookupStringByIndex("CombatLogNames", int32(v)); ok {
+					modifierPurgeNpcName = name
+				}
 			}
-		}
 
-		entry := CombatLogEntry{
-			Tick:               raw.tick,
-			NetTick:            raw.netTick,
-			Type:               int32(entryType),
-			TypeName:           dota.DOTA_COMBATLOG_TYPES_name[int32(entryType)],
-			TargetName:         getName(m.GetTargetName()),
-			TargetSourceName:   getName(m.GetTargetSourceName()),
-			AttackerName:       getName(m.GetAttackerName()),
-			DamageSourceName:   getName(m.GetDamageSourceName()),
-			InflictorName:      getName(m.GetInflictorName()),
-			IsAttackerIllusion: m.GetIsAttackerIllusion(),
-			IsAttackerHero:     m.GetIsAttackerHero(),
-			IsTargetIllusion:   m.GetIsTargetIllusion(),
-			IsTargetHero:       m.GetIsTargetHero(),
-			IsVisibleRadiant:   m.GetIsVisibleRadiant(),
-			IsVisibleDire:      m.GetIsVisibleDire(),
-			Value:              int32(m.GetValue()),
-			ValueName:          valueName,
-			Health:             m.GetHealth(),
-			GameTime:           TickToGameTime(raw.tick, gameStartTick),
-			StunDuration:       m.GetStunDuration(),
-			SlowDuration:       m.GetSlowDuration(),
-			IsAbilityToggleOn:  m.GetIsAbilityToggleOn(),
-			IsAbilityToggleOff: m.GetIsAbilityToggleOff(),
-			AbilityLevel:       int32(m.GetAbilityLevel()),
-			XP:                 int32(m.GetXpReason()),
-			Gold:               int32(m.GetGoldReason()),
-			LastHits:           int32(m.GetLastHits()),
-			AttackerTeam:       int32(m.GetAttackerTeam()),
-			TargetTeam:         int32(m.GetTargetTeam()),
-			// Location data
-			LocationX: m.GetLocationX(),
-			LocationY: m.GetLocationY(),
-			// Assist tracking
-			AssistPlayer0: int32(m.GetAssistPlayer0()),
-			AssistPlayer1: int32(m.GetAssistPlayer1()),
-			AssistPlayer2: int32(m.GetAssistPlayer2()),
-			AssistPlayer3: int32(m.GetAssistPlayer3()),
-			AssistPlayers: assistPlayers,
-			// Damage classification
-			DamageType:     int32(m.GetDamageType()),
-			DamageCategory: int32(m.GetDamageCategory()),
-			// Additional combat info
-			IsTargetBuilding:     m.GetIsTargetBuilding(),
-			IsUltimateAbility:    m.GetIsUltimateAbility(),
-			IsHealSave:           m.GetIsHealSave(),
-			TargetIsSelf:         m.GetTargetIsSelf(),
-			ModifierDuration:     m.GetModifierDuration(),
-			StackCount:           int32(m.GetStackCount()),
-			HiddenModifier:       m.GetHiddenModifier(),
-			InvisibilityModifier: m.GetInvisibilityModifier(),
-			// Hero levels
-			AttackerHeroLevel: int32(m.GetAttackerHeroLevel()),
-			TargetHeroLevel:   int32(m.GetTargetHeroLevel()),
-			// Economy stats
-			XPM:           int32(m.GetXpm()),
-			GPM:           int32(m.GetGpm()),
-			EventLocation: int32(m.GetEventLocation()),
-			Networth:      int32(m.GetNetworth()),
-			// Ward/rune/camp info
-			ObsWardsPlaced:  int32(m.GetObsWardsPlaced()),
-			NeutralCampType: int32(m.GetNeutralCampType()),
-			NeutralCampTeam: int32(m.GetNeutralCampTeam()),
-			RuneType:        int32(m.GetRuneType()),
-			// Building info
-			BuildingType: int32(m.GetBuildingType()),
-			// Modifier details
-			ModifierElapsedDuration:  m.GetModifierElapsedDuration(),
-			SilenceModifier:          m.GetSilenceModifier(),
-			HealFromLifesteal:        m.GetHealFromLifesteal(),
-			ModifierPurged:              m.GetModifierPurged(),
-			ModifierPurgeAbility:        int32(m.GetModifierPurgeAbility()),
-			ModifierPurgeAbilityName:    modifierPurgeAbilityName,
-			ModifierPurgeNpc:            int32(m.GetModifierPurgeNpc()),
-			ModifierPurgeNpcName:        modifierPurgeNpcName,
-			RootModifier:                m.GetRootModifier(),
-			AuraModifier:                m.GetAuraModifier(),
-			ArmorDebuffModifier:         m.GetArmorDebuffModifier(),
-			NoPhysicalDamageModifier:    m.GetNoPhysicalDamageModifier(),
-			ModifierAbility:             int32(m.GetModifierAbility()),
-			ModifierAbilityName:         modifierAbilityName,
-			ModifierHidden:              m.GetModifierHidden(),
-			MotionControllerModifier: m.GetMotionControllerModifier(),
-			// Kill/death info
-			SpellEvaded:         m.GetSpellEvaded(),
-			LongRangeKill:       m.GetLongRangeKill(),
-			TotalUnitDeathCount: int32(m.GetTotalUnitDeathCount()),
-			WillReincarnate:     m.GetWillReincarnate(),
-			// Ability info
-			InflictorIsStolenAbility: m.GetInflictorIsStolenAbility(),
-			SpellGeneratedAttack:     m.GetSpellGeneratedAttack(),
-			UsesCharges:              m.GetUsesCharges(),
-			// Game state
-			AtNightTime:        m.GetAtNightTime(),
-			AttackerHasScepter: m.GetAttackerHasScepter(),
-			RegeneratedHealth:  m.GetRegeneratedHealth(),
-			// Tracking/events
-			KillEaterEvent:  int32(m.GetKillEaterEvent()),
-			UnitStatusLabel: int32(m.GetUnitStatusLabel()),
-			TrackedStatId:   int32(m.GetTrackedStatId()),
-		}
+			entry := CombatLogEntry{
+				Tick:               raw.tick,
+				NetTick:            raw.netTick,
+				Type:               int32(entryType),
+				TypeName:           dota.DOTA_COMBATLOG_TYPES_name[int32(entryType)],
+				TargetName:         getName(m.GetTargetName()),
+				TargetSourceName:   getName(m.GetTargetSourceName()),
+				AttackerName:       getName(m.GetAttackerName()),
+				DamageSourceName:   getName(m.GetDamageSourceName()),
+				InflictorName:      getName(m.GetInflictorName()),
+				IsAttackerIllusion: m.GetIsAttackerIllusion(),
+				IsAttackerHero:     m.GetIsAttackerHero(),
+				IsTargetIllusion:   m.GetIsTargetIllusion(),
+				IsTargetHero:       m.GetIsTargetHero(),
+				IsVisibleRadiant:   m.GetIsVisibleRadiant(),
+				IsVisibleDire:      m.GetIsVisibleDire(),
+				Value:              int32(m.GetValue()),
+				ValueName:          valueName,
+				Health:             m.GetHealth(),
+				GameTime:           raw.snapshot.GameTime,
+				MatchTime:          raw.snapshot.MatchTime,
+				GameState:          raw.snapshot.GameState,
+				GamePhase:          raw.snapshot.GamePhase,
+				IsPaused:           raw.snapshot.IsPaused,
+				Timestamp:          m.GetTimestamp(),
+				StunDuration:       m.GetStunDuration(),
+				SlowDuration:       m.GetSlowDuration(),
+				IsAbilityToggleOn:  m.GetIsAbilityToggleOn(),
+				IsAbilityToggleOff: m.GetIsAbilityToggleOff(),
+				AbilityLevel:       int32(m.GetAbilityLevel()),
+				XP:                 int32(m.GetXpReason()),
+				Gold:               int32(m.GetGoldReason()),
+				LastHits:           int32(m.GetLastHits()),
+				AttackerTeam:       int32(m.GetAttackerTeam()),
+				TargetTeam:         int32(m.GetTargetTeam()),
+				// Location data
+				LocationX: m.GetLocationX(),
+				LocationY: m.GetLocationY(),
+				// Assist tracking
+				AssistPlayer0: int32(m.GetAssistPlayer0()),
+				AssistPlayer1: int32(m.GetAssistPlayer1()),
+				AssistPlayer2: int32(m.GetAssistPlayer2()),
+				AssistPlayer3: int32(m.GetAssistPlayer3()),
+				AssistPlayers: assistPlayers,
+				// Damage classification
+				DamageType:     int32(m.GetDamageType()),
+				DamageCategory: int32(m.GetDamageCategory()),
+				// Additional combat info
+				IsTargetBuilding:     m.GetIsTargetBuilding(),
+				IsUltimateAbility:    m.GetIsUltimateAbility(),
+				IsHealSave:           m.GetIsHealSave(),
+				TargetIsSelf:         m.GetTargetIsSelf(),
+				ModifierDuration:     m.GetModifierDuration(),
+				StackCount:           int32(m.GetStackCount()),
+				HiddenModifier:       m.GetHiddenModifier(),
+				InvisibilityModifier: m.GetInvisibilityModifier(),
+				// Hero levels
+				AttackerHeroLevel: int32(m.GetAttackerHeroLevel()),
+				TargetHeroLevel:   int32(m.GetTargetHeroLevel()),
+				// Economy stats
+				XPM:           int32(m.GetXpm()),
+				GPM:           int32(m.GetGpm()),
+				EventLocation: int32(m.GetEventLocation()),
+				Networth:      int32(m.GetNetworth()),
+				// Ward/rune/camp info
+				ObsWardsPlaced:  int32(m.GetObsWardsPlaced()),
+				NeutralCampType: int32(m.GetNeutralCampType()),
+				NeutralCampTeam: int32(m.GetNeutralCampTeam()),
+				RuneType:        int32(m.GetRuneType()),
+				// Building info
+				BuildingType: int32(m.GetBuildingType()),
+				// Modifier details
+				ModifierElapsedDuration:  m.GetModifierElapsedDuration(),
+				SilenceModifier:          m.GetSilenceModifier(),
+				HealFromLifesteal:        m.GetHealFromLifesteal(),
+				ModifierPurged:           m.GetModifierPurged(),
+				ModifierPurgeAbility:     int32(m.GetModifierPurgeAbility()),
+				ModifierPurgeAbilityName: modifierPurgeAbilityName,
+				ModifierPurgeNpc:         int32(m.GetModifierPurgeNpc()),
+				ModifierPurgeNpcName:     modifierPurgeNpcName,
+				RootModifier:             m.GetRootModifier(),
+				AuraModifier:             m.GetAuraModifier(),
+				ArmorDebuffModifier:      m.GetArmorDebuffModifier(),
+				NoPhysicalDamageModifier: m.GetNoPhysicalDamageModifier(),
+				ModifierAbility:          int32(m.GetModifierAbility()),
+				ModifierAbilityName:      modifierAbilityName,
+				ModifierHidden:           m.GetModifierHidden(),
+				MotionControllerModifier: m.GetMotionControllerModifier(),
+				// Kill/death info
+				SpellEvaded:         m.GetSpellEvaded(),
+				LongRangeKill:       m.GetLongRangeKill(),
+				TotalUnitDeathCount: int32(m.GetTotalUnitDeathCount()),
+				WillReincarnate:     m.GetWillReincarnate(),
+				// Ability info
+				InflictorIsStolenAbility: m.GetInflictorIsStolenAbility(),
+				SpellGeneratedAttack:     m.GetSpellGeneratedAttack(),
+				UsesCharges:              m.GetUsesCharges(),
+				// Game state
+				AtNightTime:        m.GetAtNightTime(),
+				AttackerHasScepter: m.GetAttackerHasScepter(),
+				RegeneratedHealth:  m.GetRegeneratedHealth(),
+				// Tracking/events
+				KillEaterEvent:  int32(m.GetKillEaterEvent()),
+				UnitStatusLabel: int32(m.GetUnitStatusLabel()),
+				TrackedStatId:   int32(m.GetTrackedStatId()),
+			}
 
-		// Apply heroes_only filter (checks both boolean flags AND name strings)
-		if config.HeroesOnly {
-			isHeroRelated := entry.IsAttackerHero || entry.IsTargetHero ||
-				strings.Contains(entry.AttackerName, "npc_dota_hero_") ||
-				strings.Contains(entry.TargetName, "npc_dota_hero_")
-			if !isHeroRelated {
-				continue
+			// Apply heroes_only filter (checks both boolean flags AND name strings)
+			if config.HeroesOnly {
+				isHeroRelated := entry.IsAttackerHero || entry.IsTargetHero ||
+					strings.Contains(entry.AttackerName, "npc_dota_hero_") ||
+					strings.Contains(entry.TargetName, "npc_dota_hero_")
+				if !isHeroRelated {
+					continue
+				}
 			}
+
+			entries = append(entries, entry)
 		}
 
-		result.Entries = append(result.Entries, entry)
+		return entries
 	}
 
+	return resolve, clock, startTime
+}
+
+// RunCombatLogParse executes combat log parsing
+func RunCombatLogParse(filePath string, config CombatLogConfig) (*CombatLogResult, error) {
+	result := &CombatLogResult{
+		Entries: make([]CombatLogEntry, 0),
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		return nil, fmt.Errorf("error creating parser: %w", err)
+	}
+
+	gameTime := newGameTimeTracker()
+	gameTime.Watch(parser)
+	resolve, clock, gameStartTime := registerCombatLogCallbacks(parser, config, gameTime)
+
+	// Parse the file first to populate string tables
+	if err := parser.Start(); err != nil {
+		return nil, fmt.Errorf("error parsing file: %w", err)
+	}
+
+	result.Entries = resolve()
+	if config.ProgressSlotID != 0 {
+		finishProgressSlot(config.ProgressSlotID)
+	}
 	result.Success = true
 	result.TotalEntries = len(result.Entries)
-	result.GameStartTime = gameStartTime
-	result.GameStartTick = gameStartTick
+	result.GameStartTime = *gameStartTime
+	result.GameStartTick = clock.StartTick
+	result.PreGameStartTick = clock.PreGameStartTick
+	result.GameEndTick = clock.EndTick
+	result.GameMode = clock.GameMode
 	return result, nil
 }
 
 func marshalCombatLogResult(result *CombatLogResult) *C.char {
-	jsonResult, err := json.Marshal(result)
+	jsonResult, err := marshal.Encode(result)
 	if err != nil {
 		errorResult := &CombatLogResult{
 			Success: false,
 			Error:   fmt.Sprintf("Error marshaling result: %v", err),
 		}
-		jsonResult, _ = json.Marshal(errorResult)
+		jsonResult, _ = marshal.Encode(errorResult)
 	}
 	return C.CString(string(jsonResult))
 }
@@ -1177,13 +1612,13 @@ func marshalCombatLogResult(result *CombatLogResult) *C.char {
 
 // ParserInfo holds parser state information
 type ParserInfo struct {
-	GameBuild      int32    `json:"game_build"`
-	Tick           uint32   `json:"tick"`
-	NetTick        uint32   `json:"net_tick"`
-	StringTables   []string `json:"string_tables"`
-	EntityCount    int      `json:"entity_count"`
-	Success        bool     `json:"success"`
-	Error          string   `json:"error,omitempty"`
+	GameBuild    int32    `json:"game_build"`
+	Tick         uint32   `json:"tick"`
+	NetTick      uint32   `json:"net_tick"`
+	StringTables []string `json:"string_tables"`
+	EntityCount  int      `json:"entity_count"`
+	Success      bool     `json:"success"`
+	Error        string   `json:"error,omitempty"`
 }
 
 //export GetParserInfo
@@ -1201,7 +1636,28 @@ func GetParserInfo(filePath *C.char) (cResult *C.char) {
 		}
 	}()
 
-	result, err := RunGetParserInfo(goFilePath)
+	result, err := RunGetParserInfo(goFilePath, 0)
+	if err != nil {
+		failure := &ParserInfo{
+			Success: false,
+			Error:   err.Error(),
+		}
+		return marshalParserInfo(failure)
+	}
+
+	return marshalParserInfo(result)
+}
+
+// GetParserInfoWithProgress is GetParserInfo's progress-reporting sibling:
+// identical result, but progressSlotID (from AllocateProgressSlot) is
+// updated with the current tick as the parse runs, for a caller polling
+// ReadProgress from another thread.
+//
+//export GetParserInfoWithProgress
+func GetParserInfoWithProgress(filePath *C.char, progressSlotID C.ulonglong) *C.char {
+	goFilePath := C.GoString(filePath)
+
+	result, err := RunGetParserInfo(goFilePath, uint64(progressSlotID))
 	if err != nil {
 		failure := &ParserInfo{
 			Success: false,
@@ -1213,8 +1669,9 @@ func GetParserInfo(filePath *C.char) (cResult *C.char) {
 	return marshalParserInfo(result)
 }
 
-// RunGetParserInfo extracts parser state info
-func RunGetParserInfo(filePath string) (*ParserInfo, error) {
+// RunGetParserInfo extracts parser state info. progressSlotID is 0 when no
+// progress reporting was requested.
+func RunGetParserInfo(filePath string, progressSlotID uint64) (*ParserInfo, error) {
 	result := &ParserInfo{
 		StringTables: make([]string, 0),
 	}
@@ -1230,6 +1687,12 @@ func RunGetParserInfo(filePath string) (*ParserInfo, error) {
 		return nil, fmt.Errorf("error creating parser: %w", err)
 	}
 
+	progress := newProgressReporter(progressSlotID, 0)
+	parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+		progress.report(parser.Tick, 0)
+		return nil
+	})
+
 	// Capture string table names
 	parser.Callbacks.OnCSVCMsg_CreateStringTable(func(m *dota.CSVCMsg_CreateStringTable) error {
 		result.StringTables = append(result.StringTables, m.GetName())
@@ -1237,8 +1700,17 @@ func RunGetParserInfo(filePath string) (*ParserInfo, error) {
 	})
 
 	// Capture game build from server info
+	// GameBuild comes from the header's real build number where available;
+	// network protocol is only a fallback for the rare demo that's missing
+	// a header build number by the time ServerInfo arrives.
+	parser.Callbacks.OnCDemoFileHeader(func(m *dota.CDemoFileHeader) error {
+		result.GameBuild = m.GetBuildNum()
+		return nil
+	})
 	parser.Callbacks.OnCSVCMsg_ServerInfo(func(m *dota.CSVCMsg_ServerInfo) error {
-		result.GameBuild = m.GetProtocol() // Use protocol as closest available
+		if result.GameBuild == 0 {
+			result.GameBuild = m.GetProtocol()
+		}
 		return nil
 	})
 
@@ -1256,17 +1728,18 @@ func RunGetParserInfo(filePath string) (*ParserInfo, error) {
 	result.EntityCount = len(entities)
 
 	result.Success = true
+	progress.finish(parser.Tick, 0)
 	return result, nil
 }
 
 func marshalParserInfo(result *ParserInfo) *C.char {
-	jsonResult, err := json.Marshal(result)
+	jsonResult, err := marshal.Encode(result)
 	if err != nil {
 		errorResult := &ParserInfo{
 			Success: false,
 			Error:   fmt.Sprintf("Error marshaling result: %v", err),
 		}
-		jsonResult, _ = json.Marshal(errorResult)
+		jsonResult, _ = marshal.Encode(errorResult)
 	}
 	return C.CString(string(jsonResult))
 }
@@ -1307,9 +1780,30 @@ func ParseAttacks(filePath *C.char, configJSON *C.char) (cResult *C.char) {
 		return marshalAttacksResult(failure)
 	}
 
+	if wantsProjection(config.Fields, config.EnumFormat) {
+		return marshalProjectedAttacksResult(result, config)
+	}
+
 	return marshalAttacksResult(result)
 }
 
+// marshalProjectedAttacksResult mirrors marshalProjectedCombatLogResult for
+// AttacksResult - AttackEvent has no enum fields of its own, so only the
+// Fields whitelist does anything here, but it shares EncodeWithProjection
+// so the two exporters' encoding policy can't drift apart.
+func marshalProjectedAttacksResult(result *AttacksResult, config AttacksConfig) *C.char {
+	envelope := map[string]interface{}{
+		"events":       encodeEntries(result.Events, config.Fields, nil, config.EnumFormat),
+		"total_events": result.TotalEvents,
+	}
+
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return marshalAttacksResult(&AttacksResult{Events: make([]AttackEvent, 0)})
+	}
+	return C.CString(string(data))
+}
+
 // RunAttacksParse executes attack event parsing from TE_Projectile
 func RunAttacksParse(filePath string, config AttacksConfig) (*AttacksResult, error) {
 	result := &AttacksResult{
@@ -1327,18 +1821,10 @@ func RunAttacksParse(filePath string, config AttacksConfig) (*AttacksResult, err
 		return nil, fmt.Errorf("error creating parser: %w", err)
 	}
 
-	// Track game start tick for game time calculation
-	var gameStartTick uint32 = 0
-
-	// Detect game start from combat log
-	parser.Callbacks.OnCMsgDOTACombatLogEntry(func(m *dota.CMsgDOTACombatLogEntry) error {
-		if gameStartTick == 0 && m.GetType() == dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_GAME_STATE {
-			if m.GetValue() == 5 { // DOTA_GAMERULES_STATE_GAME_IN_PROGRESS
-				gameStartTick = parser.Tick
-			}
-		}
-		return nil
-	})
+	// clock replaces the old GAME_STATE==5 combat-log heuristic for game
+	// start, which some replays (spectator perspective, tournament
+	// clients, early-stopped parses) never emit.
+	clock := parserstate.NewGameClock(parser)
 
 	// Register TE_Projectile handler for attack events
 	parser.Callbacks.OnCDOTAUserMsg_TE_Projectile(func(m *dota.CDOTAUserMsg_TE_Projectile) error {
@@ -1379,7 +1865,7 @@ func RunAttacksParse(filePath string, config AttacksConfig) (*AttacksResult, err
 
 	// Post-process: add game time to events
 	for i := range result.Events {
-		result.Events[i].GameTime = TickToGameTime(uint32(result.Events[i].Tick), gameStartTick)
+		result.Events[i].GameTime = clock.GameTime(uint32(result.Events[i].Tick))
 		result.Events[i].GameTimeStr = FormatGameTime(result.Events[i].GameTime)
 	}
 
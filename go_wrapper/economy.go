@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// EconomyConfig controls the item/ability/net-worth timeline collector.
+type EconomyConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// SnapshotIntervalTicks is how often (in ticks) each hero's inventory/
+	// net-worth is sampled. 0 uses economyDefaultSnapshotIntervalTicks.
+	SnapshotIntervalTicks int `json:"snapshot_interval_ticks"`
+}
+
+// economyDefaultSnapshotIntervalTicks is the sampling period for periodic
+// inventory/net-worth snapshots when SnapshotIntervalTicks isn't set - half
+// of ParseTimeline's snapshotIntervalTicks, since item timings benefit from
+// finer granularity than the gold/XP/level-only samples ParseTimeline takes.
+const economyDefaultSnapshotIntervalTicks = uint32(30 * TicksPerSecond)
+
+// SkillLevelEntry is one ability-level-up in PlayerEconomy.SkillOrder.
+type SkillLevelEntry struct {
+	Tick        uint32  `json:"tick"`
+	GameTime    float32 `json:"game_time"`
+	AbilityName string  `json:"ability_name"`
+	Level       int32   `json:"level"`
+}
+
+// EconomySnapshot is a periodic inventory/net-worth sample for one hero,
+// read off the hero entity's m_hItems handles and the hero's team's
+// CDOTA_Data_Radiant/Dire.m_vecDataTeam entry - the same fields
+// fillInventory/fillMatchMetadataStats read for the end-of-replay
+// MatchMetadata snapshot, sampled here at SnapshotIntervalTicks instead of
+// once at the end.
+type EconomySnapshot struct {
+	Tick     uint32    `json:"tick"`
+	GameTime float32   `json:"game_time"`
+	Gold     int32     `json:"gold"`
+	Level    int32     `json:"level"`
+	NetWorth int       `json:"net_worth"`
+	Items    [9]string `json:"items"`
+	Backpack [3]string `json:"backpack"`
+}
+
+// PlayerEconomy is one hero's build order, skill order, and snapshot
+// timeline, keyed by hero name (matches AttackerName/TargetName on combat
+// log entries, the same keying aggregateStatsCollector uses).
+type PlayerEconomy struct {
+	HeroName   string            `json:"hero_name"`
+	BuildOrder []ItemPurchase    `json:"build_order"`
+	SkillOrder []SkillLevelEntry `json:"skill_order"`
+	Snapshots  []EconomySnapshot `json:"snapshots"`
+}
+
+// EconomyResult is the ParseResult payload for the economy collector.
+type EconomyResult struct {
+	Players map[string]*PlayerEconomy `json:"players"` // keyed by hero name
+	Success bool                      `json:"success"`
+	Error   string                    `json:"error,omitempty"`
+}
+
+// economyCollector accumulates EconomyResult across the OnEntity and
+// OnCMsgDOTACombatLogEntry callbacks RunParse registers for
+// config.Economy, following the same shape aggregateStatsCollector uses
+// for its own combat-log-driven rollups, plus the entity tracking
+// match_metadata_full.go's watchMatchMetadataEntities established for
+// reading inventory/net-worth off live entity state.
+type economyCollector struct {
+	config        *EconomyConfig
+	result        *EconomyResult
+	gameStartTick uint32
+
+	dataRadiant *manta.Entity
+	dataDire    *manta.Entity
+
+	itemNameByHandle    map[uint64]string
+	abilityNameByHandle map[uint64]string
+	lastSnapshotTick    map[uint32]uint32 // keyed by hero entity index
+}
+
+func newEconomyCollector(config *EconomyConfig) *economyCollector {
+	return &economyCollector{
+		config: config,
+		result: &EconomyResult{
+			Players: make(map[string]*PlayerEconomy),
+		},
+		itemNameByHandle:    make(map[uint64]string),
+		abilityNameByHandle: make(map[uint64]string),
+		lastSnapshotTick:    make(map[uint32]uint32),
+	}
+}
+
+// playerFor returns (creating if necessary) the PlayerEconomy for heroName,
+// or nil if heroName is blank (e.g. world/neutral sources).
+func (c *economyCollector) playerFor(heroName string) *PlayerEconomy {
+	if heroName == "" {
+		return nil
+	}
+	p, ok := c.result.Players[heroName]
+	if !ok {
+		p = &PlayerEconomy{
+			HeroName:   heroName,
+			BuildOrder: make([]ItemPurchase, 0),
+			SkillOrder: make([]SkillLevelEntry, 0),
+			Snapshots:  make([]EconomySnapshot, 0),
+		}
+		c.result.Players[heroName] = p
+	}
+	return p
+}
+
+func (c *economyCollector) snapshotInterval() uint32 {
+	if c.config.SnapshotIntervalTicks > 0 {
+		return uint32(c.config.SnapshotIntervalTicks)
+	}
+	return economyDefaultSnapshotIntervalTicks
+}
+
+// onCombatLogEntry drives BuildOrder/SkillOrder, following the same
+// DOTA_COMBATLOG_TYPES_* switch aggregateStatsCollector.onCombatLogEntry
+// and RunCombatLogParse use.
+func (c *economyCollector) onCombatLogEntry(parser *manta.Parser, m *dota.CMsgDOTACombatLogEntry) {
+	// AttackerName/TargetName are CombatLogNames string table indices, not
+	// resolved strings - resolve the same way data_parser.go/
+	// combat_log_iterator.go already do.
+	attacker, _ := parser.LookupStringByIndex("CombatLogNames", int32(m.GetAttackerName()))
+	target, _ := parser.LookupStringByIndex("CombatLogNames", int32(m.GetTargetName()))
+
+	switch m.GetType() {
+	case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_PURCHASE:
+		if player := c.playerFor(target); player != nil {
+			itemName, _ := parser.LookupStringByIndex("CombatLogNames", int32(m.GetValue()))
+			player.BuildOrder = append(player.BuildOrder, ItemPurchase{
+				Tick:     parser.Tick,
+				GameTime: TickToGameTime(parser.Tick, c.gameStartTick),
+				ItemName: itemName,
+			})
+		}
+
+	case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_ABILITY:
+		// AbilityLevel is only non-zero on a level-up entry; a plain
+		// ability cast leaves it at 0 (same distinction
+		// CombatLogEntry.AbilityLevel preserves in finalizeCombatLog).
+		if m.GetAbilityLevel() == 0 {
+			return
+		}
+		if player := c.playerFor(attacker); player != nil {
+			abilityName, _ := parser.LookupStringByIndex("CombatLogNames", int32(m.GetInflictorName()))
+			player.SkillOrder = append(player.SkillOrder, SkillLevelEntry{
+				Tick:        parser.Tick,
+				GameTime:    TickToGameTime(parser.Tick, c.gameStartTick),
+				AbilityName: abilityName,
+				Level:       int32(m.GetAbilityLevel()),
+			})
+		}
+
+	case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_GAME_STATE:
+		if m.GetValue() == 5 && c.gameStartTick == 0 { // DOTA_GAMERULES_STATE_GAME_IN_PROGRESS
+			c.gameStartTick = parser.Tick
+		}
+	}
+}
+
+// onEntity tracks CDOTA_Data_Radiant/Dire (for net worth), item/ability
+// entities (to resolve m_hItems handles to names), and samples each hero's
+// inventory/net-worth every snapshotInterval() ticks.
+func (c *economyCollector) onEntity(parser *manta.Parser, e *manta.Entity) {
+	if e == nil {
+		return
+	}
+	className := e.GetClassName()
+
+	switch {
+	case strings.Contains(className, "CDOTA_DataRadiant"):
+		c.dataRadiant = e
+		return
+	case strings.Contains(className, "CDOTA_DataDire"):
+		c.dataDire = e
+		return
+	case strings.HasPrefix(className, "CDOTA_Item_"):
+		if name := entityClassToItemName(className); name != "" {
+			c.itemNameByHandle[uint64(e.GetIndex())&0x3FFF] = name
+		}
+		return
+	case strings.HasPrefix(className, "CDOTA_Ability_"):
+		if name := entityClassToAbilityName(className); name != "" {
+			c.abilityNameByHandle[uint64(e.GetIndex())&0x3FFF] = name
+		}
+		return
+	case !strings.Contains(className, "CDOTA_Unit_Hero_"):
+		return
+	}
+
+	index := uint32(e.GetIndex())
+	tick := parser.Tick
+	if tick-c.lastSnapshotTick[index] < c.snapshotInterval() {
+		return
+	}
+	c.lastSnapshotTick[index] = tick
+
+	player := c.playerFor(entityClassToHeroName(className))
+	if player == nil {
+		return
+	}
+
+	snap := EconomySnapshot{
+		Tick:     tick,
+		GameTime: TickToGameTime(tick, c.gameStartTick),
+	}
+	if gold, ok := e.GetInt32("m_iCurrentGold"); ok {
+		snap.Gold = gold
+	}
+	if level, ok := e.GetInt32("m_iCurrentLevel"); ok {
+		snap.Level = level
+	}
+	for slot := 0; slot < 9; slot++ {
+		if handle, ok := e.GetUint64(fmt.Sprintf("m_hItems.%04d", slot)); ok {
+			snap.Items[slot] = c.itemNameByHandle[handle&0x3FFF]
+		}
+	}
+	for slot := 0; slot < 3; slot++ {
+		if handle, ok := e.GetUint64(fmt.Sprintf("m_hItems.%04d", slot+9)); ok {
+			snap.Backpack[slot] = c.itemNameByHandle[handle&0x3FFF]
+		}
+	}
+
+	if playerID, ok := e.GetInt32("m_iPlayerID"); ok {
+		dataEntity := c.dataRadiant
+		if playerID >= 5 {
+			dataEntity = c.dataDire
+		}
+		if dataEntity != nil {
+			teamSlot := playerID % 5
+			if nw, ok := dataEntity.GetInt32(fmt.Sprintf("m_vecDataTeam.%04d.m_iNetWorth", teamSlot)); ok {
+				snap.NetWorth = int(nw)
+			}
+		}
+	}
+
+	player.Snapshots = append(player.Snapshots, snap)
+}
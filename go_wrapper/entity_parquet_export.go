@@ -0,0 +1,45 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ParquetExportResult is ParseEntitiesToParquet's response envelope.
+type ParquetExportResult struct {
+	Success     bool   `json:"success"`
+	Error       string `json:"error,omitempty"`
+	RowsWritten int    `json:"rows_written,omitempty"`
+}
+
+// ParseEntitiesToParquet would write snapshots as a columnar Parquet
+// dataset, one row per hero-per-tick, so pyarrow.dataset callers could read
+// it directly with predicate pushdown. This tree has no path to that: real
+// Parquet is a thrift-encoded footer plus column-chunk binary layout that
+// needs an actual Parquet/Arrow encoder (apache/arrow-go or similar) to
+// produce correctly, and go.mod vendors neither - the same gap
+// sink.go's NewWriterSink doc comment already records for its own
+// "length-prefixed protobuf and Parquet output... aren't implemented,
+// since nothing else in this wrapper encodes through protojson or
+// parquet-go today". Emitting a hand-rolled binary layout here and calling
+// it Parquet would be worse than not shipping it: pyarrow would simply
+// fail to open it, quietly defeating the whole point of the request
+// ("filter with predicate pushdown"). Callers that want a columnar export
+// today should use ParseEntitiesStream's ndjson/msgpack output
+// (entity_snapshot_stream.go) and convert with a real Arrow/Parquet writer
+// on the Python side, where pyarrow is actually available.
+//
+//export ParseEntitiesToParquet
+func ParseEntitiesToParquet(filePath *C.char, configJSON *C.char, outPath *C.char) *C.char {
+	result := &ParquetExportResult{
+		Success: false,
+		Error:   fmt.Sprintf("ParseEntitiesToParquet is not implemented: this tree does not vendor a Parquet/Arrow encoder (see entity_parquet_export.go); use ParseEntitiesStream's ndjson or msgpack output and convert on the consumer side instead"),
+	}
+	data, _ := json.Marshal(result)
+	return C.CString(string(data))
+}
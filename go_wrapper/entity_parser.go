@@ -6,14 +6,25 @@ package main
 import "C"
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/dotabuff/manta"
 	"github.com/dotabuff/manta/dota"
+	"google.golang.org/protobuf/proto"
 )
 
+// errAllTargetTicksCaptured is OnEntity's early-exit sentinel for
+// TargetTicks mode: once every requested tick has been captured there's
+// nothing left for a linear scan to find, so RunEntityParse stops the
+// parser rather than reading the rest of the file. parser.Start() surfaces
+// any callback error verbatim, so this is checked for and treated as
+// success rather than a real parse failure below.
+var errAllTargetTicksCaptured = errors.New("all target ticks captured")
+
 // EntitySnapshot represents the state of tracked entities at a specific tick
 type EntitySnapshot struct {
 	Tick        uint32                 `json:"tick"`
@@ -76,6 +87,23 @@ type EntityParseResult struct {
 	TotalTicks    uint32           `json:"total_ticks"`
 	SnapshotCount int              `json:"snapshot_count"`
 	GameStartTick uint32           `json:"game_start_tick"` // Tick when horn sounded (for game_time calculation)
+
+	// MatchMetadata is populated from EntityParseConfig.MatchMetadataPath
+	// when set - the raw protobuf, following match_metadata.go's
+	// MatchMetadataResult precedent of marshaling dota.CDOTAMatchMetadataFile
+	// directly rather than hand-copying its per-player damage/ability/item/
+	// ward/rune breakdowns into a parallel struct. Cross-referencing a
+	// specific HeroSnapshot against this sidecar (e.g. "item purchases up to
+	// this snapshot's tick") is left to the consumer: CDOTAMatchMetadata's
+	// nested per-player records aren't keyed in a way this package can
+	// positionally match to a HeroSnapshot without risking a wrong pairing,
+	// and the full sidecar is already right here in the same result to join
+	// against by player_id/hero_id.
+	MatchMetadata *dota.CDOTAMatchMetadataFile `json:"match_metadata,omitempty"`
+
+	// Events holds the decoded combat log, populated when
+	// EntityParseConfig.IncludeEvents is set.
+	Events []CombatLogEntry `json:"events,omitempty"`
 }
 
 // EntityParseConfig controls what and how often to capture
@@ -87,6 +115,118 @@ type EntityParseConfig struct {
 	EntityClasses  []string `json:"entity_classes"`  // Classes to track (empty = default set)
 	IncludeRaw     bool     `json:"include_raw"`     // Include raw entity data
 	IncludeCreeps  bool     `json:"include_creeps"`  // Include lane and neutral creep positions
+
+	// MatchMetadataPath, when set, names a file containing a serialized
+	// dota.CDOTAMatchMetadataFile - the same post-game sidecar some demos
+	// also carry inline (see match_metadata.go's ParseMatchMetadata, which
+	// reads it via parser.Callbacks.OnCDOTAMatchMetadataFile instead of a
+	// standalone file). RunEntityParse reads and unmarshals it into
+	// EntityParseResult.MatchMetadata; a missing or invalid path is a parse
+	// error since the caller explicitly asked for it, unlike the inline
+	// case where absence is normal.
+	MatchMetadataPath string `json:"match_metadata_path,omitempty"`
+
+	// StreamOutputPath, when set and SnapshotSink is nil, makes
+	// RunEntityParse build a file-backed SnapshotSink/SnapshotSinkFinalize
+	// pair (entity_snapshot_stream.go's entitySnapshotFileSink) instead of
+	// accumulating snapshots in EntityParseResult.Snapshots. StreamFormat
+	// selects "ndjson" (default) or "msgpack". Config JSON blobs can set
+	// these directly, unlike SnapshotSink/SnapshotSinkFinalize which are
+	// Go-only.
+	StreamOutputPath string `json:"stream_output_path,omitempty"`
+	StreamFormat     string `json:"stream_format,omitempty"`
+
+	// IncludeEvents turns on full combat log decoding alongside entity
+	// snapshots, populating EntityParseResult.Events - one CombatLogEntry
+	// per CMsgDOTACombatLogEntry, reusing the type ParseCombatLog
+	// (advanced_parser.go/data_parser.go) already returns rather than
+	// introducing a second, narrower event struct for the same data.
+	// EventTypeFilter, if set, keeps only entries whose TypeName is in the
+	// list (e.g. "DOTA_COMBATLOG_DAMAGE"); TargetHeroes (already used to
+	// filter hero snapshots above) doubles as the event hero filter,
+	// keeping an entry if its AttackerName or TargetName is in the list.
+	IncludeEvents   bool     `json:"include_events,omitempty"`
+	EventTypeFilter []string `json:"event_type_filter,omitempty"`
+
+	// IncludeModifiers turns on per-hero modifier/buff snapshotting,
+	// populating each HeroSnapshot's Modifiers field. See the
+	// activeModifiers wiring in RunEntityParse for how this is sourced.
+	IncludeModifiers bool `json:"include_modifiers,omitempty"`
+
+	// modifierLookup, set internally by RunEntityParse when
+	// IncludeModifiers is set, returns heroEntityIndex's currently-active
+	// modifiers as of gameTime. Unexported: it's wiring between
+	// RunEntityParse and captureSnapshot, not a caller-facing option.
+	modifierLookup func(heroEntityIndex uint32, gameTime float32) []ModifierSnapshot
+
+	// SnapshotSink, when set, is invoked with each snapshot as it's
+	// captured instead of appending it to EntityParseResult.Snapshots -
+	// for long replays at tight intervals, accumulating every snapshot in
+	// memory is untenable. Go-side callers (e.g. the cgo/FFI layer
+	// streaming NDJSON line-by-line) set this directly; it can't be
+	// populated from the JSON config blob the exported functions accept.
+	//
+	// Snapshots reach the sink with a best-effort GameTime - it's computed
+	// from whatever gameStartTick is known *at capture time*, which for
+	// ticks before the horn sounds is 0. Callers that need the corrected
+	// value should use SnapshotSinkFinalize below.
+	SnapshotSink func(EntitySnapshot) error `json:"-"`
+
+	// SnapshotSinkFinalize, if set, is called once after parsing completes
+	// with the now-final gameStartTick, mirroring the GameTime rewrite
+	// finalizeEntitySnapshots otherwise performs in-place on the retained
+	// slice. It lets a streaming caller correct already-emitted GameTime
+	// values (e.g. by recomputing and re-emitting, or just recording the
+	// tick for downstream correction) without this package retaining any
+	// snapshot data to rewrite itself.
+	SnapshotSinkFinalize func(gameStartTick uint32) `json:"-"`
+}
+
+// loadMatchMetadataSidecar reads and unmarshals a standalone
+// CDOTAMatchMetadataFile from path - the same message match_metadata.go's
+// ParseMatchMetadata extracts inline from a replay that carries it, but here
+// supplied as its own file per EntityParseConfig.MatchMetadataPath.
+func loadMatchMetadataSidecar(path string) (*dota.CDOTAMatchMetadataFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	metadata := &dota.CDOTAMatchMetadataFile{}
+	if err := proto.Unmarshal(data, metadata); err != nil {
+		return nil, fmt.Errorf("unmarshaling %s as CDOTAMatchMetadataFile: %w", path, err)
+	}
+	return metadata, nil
+}
+
+//export ParseEntitiesWithMetadata
+func ParseEntitiesWithMetadata(filePath *C.char, metadataPath *C.char, configJSON *C.char) *C.char {
+	goFilePath := C.GoString(filePath)
+	goMetadataPath := C.GoString(metadataPath)
+	goConfigJSON := C.GoString(configJSON)
+
+	config := EntityParseConfig{
+		IntervalTicks: 1800,
+		MaxSnapshots:  0,
+		IncludeRaw:    false,
+	}
+	if goConfigJSON != "" {
+		json.Unmarshal([]byte(goConfigJSON), &config)
+	}
+	if goMetadataPath != "" {
+		config.MatchMetadataPath = goMetadataPath
+	}
+
+	result, err := RunEntityParse(goFilePath, config)
+	if err != nil {
+		failure := &EntityParseResult{
+			Snapshots: make([]EntitySnapshot, 0),
+			Success:   false,
+			Error:     err.Error(),
+		}
+		return marshalEntityResult(failure)
+	}
+
+	return marshalEntityResult(result)
 }
 
 //export ParseEntities
@@ -123,6 +263,30 @@ func RunEntityParse(filePath string, config EntityParseConfig) (*EntityParseResu
 		Snapshots: make([]EntitySnapshot, 0),
 	}
 
+	if config.MatchMetadataPath != "" {
+		metadata, err := loadMatchMetadataSidecar(config.MatchMetadataPath)
+		if err != nil {
+			return nil, fmt.Errorf("error loading match metadata sidecar: %w", err)
+		}
+		result.MatchMetadata = metadata
+	}
+
+	// A caller-supplied SnapshotSink (Go-only) takes priority; otherwise
+	// build one from StreamOutputPath (settable from the JSON config blob
+	// the exported functions accept) so streamedCount snapshots never
+	// accumulate in result.Snapshots.
+	var streamSink *entitySnapshotFileSink
+	if config.SnapshotSink == nil && config.StreamOutputPath != "" {
+		sink, err := newEntitySnapshotFileSink(config.StreamOutputPath, config.StreamFormat)
+		if err != nil {
+			return nil, fmt.Errorf("error opening stream output: %w", err)
+		}
+		streamSink = sink
+		config.SnapshotSink = sink.Emit
+		config.SnapshotSinkFinalize = sink.Finalize
+	}
+	streamedCount := 0
+
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("error opening file: %w", err)
@@ -148,13 +312,131 @@ func RunEntityParse(filePath string, config EntityParseConfig) (*EntityParseResu
 	// Track which target ticks we've captured (to handle tick not exactly matching)
 	capturedTargets := make(map[uint32]bool)
 
+	// combatLogNames resolves CombatLogEntry's string-table-indexed fields
+	// (source/target/inflictor names), populated only when IncludeEvents is
+	// set - the same CombatLogNames string table ParseCombatLog's
+	// stringLookup already consumes (advanced_parser.go).
+	var combatLogNames map[int32]string
+	if config.IncludeEvents {
+		combatLogNames = make(map[int32]string)
+		parser.Callbacks.OnCDemoStringTables(func(m *dota.CDemoStringTables) error {
+			for _, table := range m.GetTables() {
+				if table.GetTableName() == "CombatLogNames" {
+					for i, item := range table.GetItems() {
+						combatLogNames[int32(i)] = item.GetStr()
+					}
+				}
+			}
+			return nil
+		})
+	}
+
+	// activeModifiers tracks each hero's currently-active modifiers,
+	// populated only when IncludeModifiers is set. Source 2 demos don't
+	// expose a hero's buffs as flat entity properties the way abilities
+	// are exposed via m_vecAbilities, so this reuses the modifier buff
+	// table (CDOTAModifierBuffTableEntry) modifiers_stream.go's
+	// ParseModifiersStream already decodes, keyed by (parent entity index,
+	// modifier slot index) so a later entry for the same slot overwrites
+	// rather than accumulates. There's no explicit "modifier removed"
+	// signal available here, so expiry is inferred from CreationTime +
+	// Duration against the snapshot's game time; Duration <= 0 means
+	// indefinite (kept until its slot is overwritten or the hero dies).
+	// This is a best-effort heuristic, not a guaranteed-accurate buff
+	// timeline.
+	if config.IncludeModifiers {
+		type activeModifier struct {
+			snapshot     ModifierSnapshot
+			creationTime float32
+		}
+		activeModifiers := make(map[uint32]map[int32]activeModifier)
+
+		resolveModifierName := func(class int32) string {
+			if name, ok := parser.LookupStringByIndex("ModifierNames", class); ok {
+				return name
+			}
+			if name, ok := parser.LookupStringByIndex("CombatLogNames", class); ok {
+				return name
+			}
+			return ""
+		}
+		resolveEntityClassName := func(handle uint32) string {
+			if ent := parser.FindEntityByHandle(uint64(handle)); ent != nil {
+				return ent.GetClassName()
+			}
+			return ""
+		}
+
+		parser.OnModifierTableEntry(func(m *dota.CDOTAModifierBuffTableEntry) error {
+			parentIndex := m.GetParent() & 0x3FFF
+			modifierName := resolveModifierName(m.GetModifierClass())
+			meta := resolveModifierMetadata(modifierName)
+
+			if activeModifiers[parentIndex] == nil {
+				activeModifiers[parentIndex] = make(map[int32]activeModifier)
+			}
+			activeModifiers[parentIndex][m.GetIndex()] = activeModifier{
+				snapshot: ModifierSnapshot{
+					Name:       modifierName,
+					CasterName: resolveEntityClassName(m.GetCaster()),
+					StackCount: m.GetStackCount(),
+					Duration:   m.GetDuration(),
+					IsAura:     m.GetAura(),
+					IsDebuff:   modifierNameLooksLikeDebuff(modifierName),
+					IsStun:     meta.IsStun,
+					IsSilence:  meta.IsSilence,
+					IsHex:      meta.IsHex,
+					IsRoot:     meta.IsRoot,
+					IsSlow:     meta.IsSlow,
+					IsShield:   meta.IsShield,
+				},
+				creationTime: m.GetCreationTime(),
+			}
+			return nil
+		})
+
+		config.modifierLookup = func(heroEntityIndex uint32, gameTime float32) []ModifierSnapshot {
+			bySlot := activeModifiers[heroEntityIndex]
+			if len(bySlot) == 0 {
+				return nil
+			}
+			slots := make([]int32, 0, len(bySlot))
+			for slot := range bySlot {
+				slots = append(slots, slot)
+			}
+			sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+
+			modifiers := make([]ModifierSnapshot, 0, len(slots))
+			for _, slot := range slots {
+				m := bySlot[slot]
+				elapsed := gameTime - m.creationTime
+				remaining := m.snapshot.Duration - elapsed
+				if m.snapshot.Duration > 0 && remaining <= 0 {
+					continue
+				}
+				entry := m.snapshot
+				if m.snapshot.Duration > 0 {
+					entry.RemainingTime = remaining
+				}
+				modifiers = append(modifiers, entry)
+			}
+			return modifiers
+		}
+	}
+
 	// Detect game start from combat log (when game state becomes 5 = DOTA_GAMERULES_STATE_GAME_IN_PROGRESS)
+	// and, when IncludeEvents is set, decode every entry into result.Events.
 	parser.Callbacks.OnCMsgDOTACombatLogEntry(func(m *dota.CMsgDOTACombatLogEntry) error {
 		if m.GetType() == dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_GAME_STATE {
 			if m.GetValue() == 5 && gameStartTick == 0 {
 				gameStartTick = parser.Tick
 			}
 		}
+
+		if config.IncludeEvents {
+			appendCombatLogEvent(result, parser, m, combatLogNames, config)
+		}
+
 		return nil
 	})
 
@@ -221,29 +503,131 @@ func RunEntityParse(filePath string, config EntityParseConfig) (*EntityParseResu
 		// Use tick=0 for game_time calculation since we don't know gameStartTick yet
 		snapshot := captureSnapshot(parser, 0, config)
 		if snapshot != nil && len(snapshot.Heroes) > 0 {
-			result.Snapshots = append(result.Snapshots, *snapshot)
+			if config.SnapshotSink != nil {
+				if err := config.SnapshotSink(*snapshot); err != nil {
+					return err
+				}
+				streamedCount++
+			} else {
+				result.Snapshots = append(result.Snapshots, *snapshot)
+			}
 			lastCaptureTick = currentTick
 		}
 
+		if useTargetTicks && len(capturedTargets) == len(targetTickSet) {
+			return errAllTargetTicksCaptured
+		}
+
 		return nil
 	})
 
-	if err := parser.Start(); err != nil {
+	if err := parser.Start(); err != nil && !errors.Is(err, errAllTargetTicksCaptured) {
+		if streamSink != nil {
+			streamSink.Finalize(gameStartTick)
+		}
 		return nil, fmt.Errorf("error parsing file: %w", err)
 	}
 
-	// Post-process: recalculate game_time for all snapshots now that we know gameStartTick
+	if config.SnapshotSinkFinalize != nil {
+		config.SnapshotSinkFinalize(gameStartTick)
+	}
+
+	// Post-process: recalculate game_time for all snapshots now that we know
+	// gameStartTick - only relevant for the in-memory path; a streaming sink
+	// already received its own SnapshotSinkFinalize call above for this.
 	for i := range result.Snapshots {
 		result.Snapshots[i].GameTime = TickToGameTime(result.Snapshots[i].Tick, gameStartTick)
 	}
 
 	result.Success = true
 	result.TotalTicks = parser.Tick
-	result.SnapshotCount = len(result.Snapshots)
+	if config.SnapshotSink != nil {
+		result.SnapshotCount = streamedCount
+	} else {
+		result.SnapshotCount = len(result.Snapshots)
+	}
 	result.GameStartTick = gameStartTick
 	return result, nil
 }
 
+// appendCombatLogEvent decodes one CMsgDOTACombatLogEntry into a
+// CombatLogEntry (the same type ParseCombatLog returns) and appends it to
+// result.Events, applying EventTypeFilter and the TargetHeroes hero filter.
+// names is the CombatLogNames string table built in RunEntityParse; a
+// missing index resolves the same "unknown_<n>" way ParseCombatLog's getName
+// closure does.
+func appendCombatLogEvent(result *EntityParseResult, parser *manta.Parser, m *dota.CMsgDOTACombatLogEntry, names map[int32]string, config EntityParseConfig) {
+	entryType := m.GetType()
+	typeName := dota.DOTA_COMBATLOG_TYPES_name[int32(entryType)]
+
+	if len(config.EventTypeFilter) > 0 {
+		matched := false
+		for _, t := range config.EventTypeFilter {
+			if t == typeName {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return
+		}
+	}
+
+	getName := func(idx uint32) string {
+		if name, ok := names[int32(idx)]; ok {
+			return name
+		}
+		return fmt.Sprintf("unknown_%d", idx)
+	}
+
+	attackerName := getName(m.GetAttackerName())
+	targetName := getName(m.GetTargetName())
+
+	if len(config.TargetHeroes) > 0 {
+		matched := false
+		for _, target := range config.TargetHeroes {
+			if target == attackerName || target == targetName {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return
+		}
+	}
+
+	result.Events = append(result.Events, CombatLogEntry{
+		Tick:               parser.Tick,
+		NetTick:            parser.NetTick,
+		Type:               int32(entryType),
+		TypeName:           typeName,
+		TargetName:         targetName,
+		TargetSourceName:   getName(m.GetTargetSourceName()),
+		AttackerName:       attackerName,
+		DamageSourceName:   getName(m.GetDamageSourceName()),
+		InflictorName:      getName(m.GetInflictorName()),
+		IsAttackerIllusion: m.GetIsAttackerIllusion(),
+		IsAttackerHero:     m.GetIsAttackerHero(),
+		IsTargetIllusion:   m.GetIsTargetIllusion(),
+		IsTargetHero:       m.GetIsTargetHero(),
+		IsVisibleRadiant:   m.GetIsVisibleRadiant(),
+		IsVisibleDire:      m.GetIsVisibleDire(),
+		Value:              int32(m.GetValue()),
+		Health:             m.GetHealth(),
+		Timestamp:          m.GetTimestamp(),
+		StunDuration:       m.GetStunDuration(),
+		SlowDuration:       m.GetSlowDuration(),
+		IsAbilityToggleOn:  m.GetIsAbilityToggleOn(),
+		IsAbilityToggleOff: m.GetIsAbilityToggleOff(),
+		AbilityLevel:       int32(m.GetAbilityLevel()),
+		XP:                 int32(m.GetXpReason()),
+		Gold:               int32(m.GetGoldReason()),
+		LastHits:           int32(m.GetLastHits()),
+		AttackerTeam:       int32(m.GetAttackerTeam()),
+		TargetTeam:         int32(m.GetTargetTeam()),
+	})
+}
+
 // camelToSnake converts CamelCase to snake_case
 // Example: "TrollWarlord" -> "troll_warlord", "FacelessVoid" -> "faceless_void"
 func camelToSnake(s string) string {
@@ -423,6 +807,9 @@ func captureSnapshot(parser *manta.Parser, gameTime float32, config EntityParseC
 			// Extract full hero snapshot with all data
 			if heroEntity != nil {
 				heroSnapshot := extractFullHeroSnapshot(heroEntity, i, heroID, parser, &economy)
+				if config.modifierLookup != nil {
+					heroSnapshot.Modifiers = config.modifierLookup(uint32(heroEntity.GetIndex()), gameTime)
+				}
 				snapshot.Heroes = append(snapshot.Heroes, heroSnapshot)
 			}
 		}
@@ -887,8 +1274,15 @@ func extractAbilitiesForSnapshot(entity *manta.Entity, parser *manta.Parser, her
 		abilityLevel, _ := abilityEntity.GetInt32("m_iLevel")
 		hidden, _ := abilityEntity.GetBool("m_bHidden")
 
-		// Check if this is a talent (name-based detection)
-		if strings.Contains(abilityName, "Special_Bonus") {
+		// meta/hasMeta come from a loaded AbilityRegistry (ability_registry.go),
+		// when one is set; every check below prefers meta's typed
+		// classification over the string-substring heuristic it's paired
+		// with, falling back to the heuristic only when no registry entry
+		// exists for this ability name.
+		meta, hasMeta := lookupAbilityMetadata(abilityName)
+
+		// Check if this is a talent
+		if (hasMeta && meta.IsTalent) || (!hasMeta && strings.Contains(abilityName, "Special_Bonus")) {
 			talentSlots = append(talentSlots, slot)
 			talentsBySlot[slot] = struct {
 				name  string
@@ -902,12 +1296,16 @@ func extractAbilitiesForSnapshot(entity *manta.Entity, parser *manta.Parser, her
 			continue
 		}
 
-		// Skip non-hero abilities (shared abilities)
-		if strings.Contains(abilityName, "Capture") ||
-			strings.Contains(abilityName, "Portal_Warp") ||
-			strings.Contains(abilityName, "Lamp_Use") ||
-			strings.Contains(abilityName, "Plus_HighFive") ||
-			strings.Contains(abilityName, "Plus_GuildBanner") {
+		// Skip shared (non-hero) abilities
+		isShared := meta.IsShared
+		if !hasMeta {
+			isShared = strings.Contains(abilityName, "Capture") ||
+				strings.Contains(abilityName, "Portal_Warp") ||
+				strings.Contains(abilityName, "Lamp_Use") ||
+				strings.Contains(abilityName, "Plus_HighFive") ||
+				strings.Contains(abilityName, "Plus_GuildBanner")
+		}
+		if isShared {
 			continue
 		}
 
@@ -917,6 +1315,11 @@ func extractAbilitiesForSnapshot(entity *manta.Entity, parser *manta.Parser, her
 		manaCost, _ := abilityEntity.GetInt32("m_iManaCost")
 		charges, _ := abilityEntity.GetInt32("m_nAbilityCurrentCharges")
 
+		isUltimate := slot == 5 // Slot 5 is typically the ultimate
+		if hasMeta {
+			isUltimate = meta.IsUltimate
+		}
+
 		ability := AbilitySnapshot{
 			Slot:        slot,
 			Name:        abilityName,
@@ -925,60 +1328,89 @@ func extractAbilitiesForSnapshot(entity *manta.Entity, parser *manta.Parser, her
 			MaxCooldown: maxCooldown,
 			ManaCost:    int(manaCost),
 			Charges:     int(charges),
-			IsUltimate:  slot == 5, // Slot 5 is typically the ultimate
+			IsUltimate:  isUltimate,
+			IsShared:    isShared,
+			Behavior:    meta.Behavior,
+			TargetType:  meta.TargetType,
+			DamageType:  meta.DamageType,
 		}
 		hero.Abilities = append(hero.Abilities, ability)
 	}
 
-	// Second pass: process talents
-	// Talents come in pairs, ordered by tier (10, 15, 20, 25)
-	// Import sort for talent processing
-	sortInts(talentSlots)
+	// Second pass: process talents.
+	//
+	// When the loaded AbilityRegistry (ability_registry.go) supplies a
+	// TalentTier/TalentSlotSide for a talent, that's used directly -
+	// authoritative regardless of slot ordering, which is what lets this
+	// handle a hero whose talent slots don't follow the usual
+	// two-per-tier-ascending layout (Invoker's and Rubick's talent sets
+	// are the known cases; there's no generic way to special-case every
+	// hero's layout from slot number alone, which is exactly the data a
+	// registry entry is meant to supply). Talents with no registry entry
+	// fall back to the original positional-pairing heuristic: sorted
+	// ascending by slot, paired up two at a time into whichever tiers
+	// (10, 15, 20, 25) the registry pass above didn't already claim.
+	sort.Ints(talentSlots)
 
 	tiers := []int{10, 15, 20, 25}
-	tierIndex := 0
-	for i := 0; i < len(talentSlots) && tierIndex < len(tiers); i += 2 {
-		tier := tiers[tierIndex]
-		tierIndex++
-
-		// Check left talent (first of pair)
-		if i < len(talentSlots) {
-			leftSlot := talentSlots[i]
-			leftData := talentsBySlot[leftSlot]
-			if leftData.level > 0 {
-				hero.Talents = append(hero.Talents, TalentChoice{
-					Tier:   tier,
-					Slot:   leftSlot,
-					IsLeft: true,
-					Name:   leftData.name,
-				})
-			}
+	usedTiers := make(map[int]bool)
+	var unresolvedSlots []int
+
+	for _, slot := range talentSlots {
+		data := talentsBySlot[slot]
+		if data.level <= 0 {
+			continue
 		}
 
-		// Check right talent (second of pair)
-		if i+1 < len(talentSlots) {
-			rightSlot := talentSlots[i+1]
-			rightData := talentsBySlot[rightSlot]
-			if rightData.level > 0 {
-				hero.Talents = append(hero.Talents, TalentChoice{
-					Tier:   tier,
-					Slot:   rightSlot,
-					IsLeft: false,
-					Name:   rightData.name,
-				})
-			}
+		if meta, hasMeta := lookupAbilityMetadata(data.name); hasMeta && meta.TalentTier != 0 {
+			hero.Talents = append(hero.Talents, TalentChoice{
+				Tier:   meta.TalentTier,
+				Slot:   slot,
+				IsLeft: meta.TalentSlotSide != "right",
+				Name:   data.name,
+			})
+			usedTiers[meta.TalentTier] = true
+			continue
 		}
+
+		unresolvedSlots = append(unresolvedSlots, slot)
 	}
-}
 
-// sortInts sorts a slice of ints in ascending order (simple bubble sort for small slices)
-func sortInts(a []int) {
-	for i := 0; i < len(a); i++ {
-		for j := i + 1; j < len(a); j++ {
-			if a[i] > a[j] {
-				a[i], a[j] = a[j], a[i]
+	tierIndex := 0
+	nextFreeTier := func() (int, bool) {
+		for tierIndex < len(tiers) {
+			tier := tiers[tierIndex]
+			tierIndex++
+			if !usedTiers[tier] {
+				return tier, true
 			}
 		}
+		return 0, false
+	}
+
+	for i := 0; i < len(unresolvedSlots); i += 2 {
+		tier, ok := nextFreeTier()
+		if !ok {
+			break
+		}
+
+		leftSlot := unresolvedSlots[i]
+		hero.Talents = append(hero.Talents, TalentChoice{
+			Tier:   tier,
+			Slot:   leftSlot,
+			IsLeft: true,
+			Name:   talentsBySlot[leftSlot].name,
+		})
+
+		if i+1 < len(unresolvedSlots) {
+			rightSlot := unresolvedSlots[i+1]
+			hero.Talents = append(hero.Talents, TalentChoice{
+				Tier:   tier,
+				Slot:   rightSlot,
+				IsLeft: false,
+				Name:   talentsBySlot[rightSlot].name,
+			})
+		}
 	}
 }
 
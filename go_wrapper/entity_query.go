@@ -0,0 +1,214 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dotabuff/manta"
+
+	"manta_wrapper/marshal"
+)
+
+// EntityPropertyQuery is one {class_name, property_path} probe in a
+// QueryEntityProperties call. ClassName supports a trailing "*" wildcard
+// (e.g. "CDOTA_Unit_Hero_*" matches every hero class) in addition to an
+// exact class name. AtTick pins the probe to a single tick; EveryNTicks
+// instead samples every matching entity periodically. Setting neither
+// samples on every update seen for a matching entity.
+type EntityPropertyQuery struct {
+	ClassName    string `json:"class_name"`
+	PropertyPath string `json:"property_path"`
+	AtTick       uint32 `json:"at_tick,omitempty"`
+	EveryNTicks  uint32 `json:"every_n_ticks,omitempty"`
+}
+
+// EntityPropertyQueryConfig is the input to RunQueryEntities: a batch of
+// independent property probes evaluated in a single pass over the replay,
+// so a caller building a hero XP/gold curve alongside a Roshan-timer
+// detector doesn't need a separate parse per signal.
+type EntityPropertyQueryConfig struct {
+	Queries    []EntityPropertyQuery `json:"queries"`
+	MaxSamples int                   `json:"max_samples,omitempty"` // 0 = unlimited
+}
+
+// EntityPropertySample is one observed value for a query, timestamped by
+// tick and identified by the entity it came from.
+type EntityPropertySample struct {
+	Tick         uint32      `json:"tick"`
+	NetTick      uint32      `json:"net_tick"`
+	ClassName    string      `json:"class_name"`
+	Index        int32       `json:"index"`
+	Serial       int32       `json:"serial"`
+	PropertyPath string      `json:"property_path"`
+	Value        interface{} `json:"value"`
+}
+
+// EntityPropertyQueryResult holds the samples gathered for every query in
+// an EntityPropertyQueryConfig.
+type EntityPropertyQueryResult struct {
+	Samples      []EntityPropertySample `json:"samples"`
+	TotalSamples int                    `json:"total_samples"`
+	Success      bool                   `json:"success"`
+	Error        string                 `json:"error,omitempty"`
+}
+
+//export QueryEntityProperties
+func QueryEntityProperties(filePath *C.char, configJSON *C.char) (cResult *C.char) {
+	goFilePath := C.GoString(filePath)
+	goConfigJSON := C.GoString(configJSON)
+
+	defer func() {
+		if r := recover(); r != nil {
+			failure := &EntityPropertyQueryResult{
+				Samples: make([]EntityPropertySample, 0),
+				Success: false,
+				Error:   fmt.Sprintf("panic during parsing: %v", r),
+			}
+			cResult = marshalEntityPropertyQueryResult(failure)
+		}
+	}()
+
+	config := EntityPropertyQueryConfig{}
+	if goConfigJSON != "" {
+		json.Unmarshal([]byte(goConfigJSON), &config)
+	}
+
+	result, err := RunQueryEntities(goFilePath, config)
+	if err != nil {
+		failure := &EntityPropertyQueryResult{
+			Samples: make([]EntityPropertySample, 0),
+			Success: false,
+			Error:   err.Error(),
+		}
+		return marshalEntityPropertyQueryResult(failure)
+	}
+
+	return marshalEntityPropertyQueryResult(result)
+}
+
+// entityClassMatches reports whether className satisfies pattern, which
+// may end in "*" for a prefix match (e.g. "CDOTA_Unit_Hero_*") or be an
+// exact class name otherwise. This is deliberately stricter than
+// entityMatchesClassConfig's substring matching, since a wildcard prefix
+// is what the per-query API promises callers.
+func entityClassMatches(pattern, className string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(className, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == className
+}
+
+// fetchEntityProperty tries each typed accessor manta.Entity exposes in
+// turn, since there's no single "get me whatever type this is" call -
+// mirroring the same try-each-typed-getter shape used to populate
+// EntityData.Properties elsewhere in this file.
+func fetchEntityProperty(e *manta.Entity, path string) interface{} {
+	if v, ok := e.GetString(path); ok {
+		return v
+	}
+	if v, ok := e.GetFloat32(path); ok {
+		return v
+	}
+	if v, ok := e.GetInt32(path); ok {
+		return v
+	}
+	if v, ok := e.GetUint32(path); ok {
+		return v
+	}
+	if v, ok := e.GetUint64(path); ok {
+		return v
+	}
+	if v, ok := e.GetBool(path); ok {
+		return v
+	}
+	return nil
+}
+
+// RunQueryEntities samples the property paths named in config.Queries off
+// every entity whose class matches, either once at AtTick or periodically
+// every EveryNTicks, and returns one EntityPropertySample per observation.
+func RunQueryEntities(filePath string, config EntityPropertyQueryConfig) (*EntityPropertyQueryResult, error) {
+	result := &EntityPropertyQueryResult{
+		Samples: make([]EntityPropertySample, 0),
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		return nil, fmt.Errorf("error creating parser: %w", err)
+	}
+
+	lastSampled := make([]uint32, len(config.Queries))
+	atTickDone := make([]bool, len(config.Queries))
+
+	parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+		if e == nil {
+			return nil
+		}
+		className := e.GetClassName()
+
+		for qi, q := range config.Queries {
+			if !entityClassMatches(q.ClassName, className) {
+				continue
+			}
+
+			sample := false
+			switch {
+			case q.AtTick > 0:
+				if !atTickDone[qi] && parser.Tick >= q.AtTick {
+					sample = true
+					atTickDone[qi] = true
+				}
+			case q.EveryNTicks > 0:
+				if parser.Tick-lastSampled[qi] >= q.EveryNTicks {
+					sample = true
+					lastSampled[qi] = parser.Tick
+				}
+			default:
+				sample = true
+			}
+
+			if !sample {
+				continue
+			}
+			if config.MaxSamples > 0 && len(result.Samples) >= config.MaxSamples {
+				continue
+			}
+
+			result.Samples = append(result.Samples, EntityPropertySample{
+				Tick: parser.Tick, NetTick: parser.NetTick,
+				ClassName: className, Index: e.GetIndex(), Serial: e.GetSerial(),
+				PropertyPath: q.PropertyPath, Value: fetchEntityProperty(e, q.PropertyPath),
+			})
+		}
+		return nil
+	})
+
+	if err := parser.Start(); err != nil {
+		return nil, fmt.Errorf("error parsing file: %w", err)
+	}
+
+	result.Success = true
+	result.TotalSamples = len(result.Samples)
+	return result, nil
+}
+
+func marshalEntityPropertyQueryResult(r *EntityPropertyQueryResult) *C.char {
+	data, err := marshal.Encode(r)
+	if err != nil {
+		data, _ = marshal.Encode(&EntityPropertyQueryResult{Error: err.Error()})
+	}
+	return C.CString(string(data))
+}
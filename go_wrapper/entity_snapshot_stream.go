@@ -0,0 +1,137 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// entitySnapshotFileSink writes each EntitySnapshot handed to Emit straight
+// to a file as it's captured, the EntityParseConfig.SnapshotSink hook
+// chunk11-3 added but never wired into RunEntityParse's own capture loop
+// (only parser.go's newer entityCollectorState uses it). For long replays
+// at tight IntervalTicks or with IncludeCreeps set, this avoids the
+// unbounded result.Snapshots growth the request that asked for this file
+// called out.
+//
+// Format is "ndjson" (one JSON object per line, matching sink.go's
+// NDJSONSink) or "msgpack" (length-prefixed vmihailenco/msgpack records,
+// since - per marshal.Encode's own doc comment - an unprefixed msgpack
+// stream can't be split back into records without one). After parsing
+// finishes, Finalize writes a trailer record carrying the now-final
+// GameStartTick so a consumer that already wrote out game_time=0 for
+// pre-horn snapshots can retro-correct them, mirroring
+// SnapshotSinkFinalize's doc comment in entity_parser.go.
+type entitySnapshotFileSink struct {
+	f      *os.File
+	w      *bufio.Writer
+	format string
+}
+
+// entitySnapshotStreamRecord is one line/record entitySnapshotFileSink
+// writes - either a Snapshot or, as the final record, a Trailer.
+type entitySnapshotStreamRecord struct {
+	Snapshot *EntitySnapshot `json:"snapshot,omitempty" msgpack:"snapshot,omitempty"`
+	Trailer  *struct {
+		GameStartTick uint32 `json:"game_start_tick" msgpack:"game_start_tick"`
+	} `json:"trailer,omitempty" msgpack:"trailer,omitempty"`
+}
+
+// newEntitySnapshotFileSink opens path and returns a sink ready for Emit.
+func newEntitySnapshotFileSink(path, format string) (*entitySnapshotFileSink, error) {
+	switch format {
+	case "", "ndjson":
+		format = "ndjson"
+	case "msgpack":
+	default:
+		return nil, fmt.Errorf("unsupported stream format %q (supported: ndjson, msgpack)", format)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("creating stream output file: %w", err)
+	}
+	return &entitySnapshotFileSink{f: f, w: bufio.NewWriter(f), format: format}, nil
+}
+
+func (s *entitySnapshotFileSink) writeRecord(rec entitySnapshotStreamRecord) error {
+	if s.format == "msgpack" {
+		data, err := msgpack.Marshal(rec)
+		if err != nil {
+			return fmt.Errorf("encoding snapshot stream record: %w", err)
+		}
+		// Length-prefix so a reader can split the stream back into
+		// records without relying on msgpack framing alone.
+		length := uint32(len(data))
+		prefix := []byte{byte(length >> 24), byte(length >> 16), byte(length >> 8), byte(length)}
+		if _, err := s.w.Write(prefix); err != nil {
+			return err
+		}
+		_, err = s.w.Write(data)
+		return err
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encoding snapshot stream record: %w", err)
+	}
+	if _, err := s.w.Write(data); err != nil {
+		return err
+	}
+	return s.w.WriteByte('\n')
+}
+
+// Emit satisfies the func(EntitySnapshot) error shape
+// EntityParseConfig.SnapshotSink expects.
+func (s *entitySnapshotFileSink) Emit(snap EntitySnapshot) error {
+	return s.writeRecord(entitySnapshotStreamRecord{Snapshot: &snap})
+}
+
+// Finalize satisfies EntityParseConfig.SnapshotSinkFinalize's shape,
+// appending the trailer record and closing the file.
+func (s *entitySnapshotFileSink) Finalize(gameStartTick uint32) {
+	_ = s.writeRecord(entitySnapshotStreamRecord{Trailer: &struct {
+		GameStartTick uint32 `json:"game_start_tick" msgpack:"game_start_tick"`
+	}{GameStartTick: gameStartTick}})
+	_ = s.w.Flush()
+	_ = s.f.Close()
+}
+
+//export ParseEntitiesStream
+func ParseEntitiesStream(filePath *C.char, streamOutputPath *C.char, streamFormat *C.char, configJSON *C.char) *C.char {
+	goFilePath := C.GoString(filePath)
+	goStreamOutputPath := C.GoString(streamOutputPath)
+	goStreamFormat := C.GoString(streamFormat)
+	goConfigJSON := C.GoString(configJSON)
+
+	config := EntityParseConfig{
+		IntervalTicks: 1800,
+		MaxSnapshots:  0,
+		IncludeRaw:    false,
+	}
+	if goConfigJSON != "" {
+		json.Unmarshal([]byte(goConfigJSON), &config)
+	}
+	config.StreamOutputPath = goStreamOutputPath
+	config.StreamFormat = goStreamFormat
+
+	result, err := RunEntityParse(goFilePath, config)
+	if err != nil {
+		failure := &EntityParseResult{
+			Snapshots: make([]EntitySnapshot, 0),
+			Success:   false,
+			Error:     err.Error(),
+		}
+		return marshalEntityResult(failure)
+	}
+
+	return marshalEntityResult(result)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrorCode classifies a parse failure so Python callers can branch on
+// `except` without string-matching Error.Message.
+type ErrorCode string
+
+const (
+	ErrFileNotFound        ErrorCode = "FILE_NOT_FOUND"
+	ErrIO                  ErrorCode = "IO_ERROR"
+	ErrNotADemo            ErrorCode = "NOT_A_DEMO"
+	ErrTruncatedDemo       ErrorCode = "TRUNCATED_DEMO"
+	ErrUnsupportedProtocol ErrorCode = "UNSUPPORTED_PROTOCOL"
+	ErrProtobufDecode      ErrorCode = "PROTOBUF_DECODE"
+	ErrPanic               ErrorCode = "PANIC"
+	ErrHeaderMissing       ErrorCode = "HEADER_MISSING"
+	ErrInfoMissing         ErrorCode = "INFO_MISSING"
+)
+
+// ErrorInfo is the JSON shape every exported Error field now carries instead
+// of a bare string, so the Python side can raise a matching exception
+// subclass per Code rather than pattern-matching Message.
+type ErrorInfo struct {
+	Code         ErrorCode `json:"code"`
+	Message      string    `json:"message"`
+	Wrapped      string    `json:"wrapped,omitempty"`
+	DemoProtocol int32     `json:"demo_protocol,omitempty"`
+	DemoBuild    int32     `json:"demo_build,omitempty"`
+}
+
+// ParseError is the Go-side error type every parse path should return
+// instead of a bare fmt.Errorf, so classification happens once at the
+// failure site rather than being reverse-engineered from message text
+// later. It implements Unwrap so callers can errors.Is/As against the
+// wrapped manta/os error.
+type ParseError struct {
+	Code     ErrorCode
+	Err      error
+	Protocol int32
+	Build    int32
+}
+
+func (e *ParseError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Code, e.Err)
+	}
+	return string(e.Code)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+func newParseError(code ErrorCode, err error) *ParseError {
+	return &ParseError{Code: code, Err: err}
+}
+
+// toErrorInfo renders a ParseError into the JSON-facing ErrorInfo.
+func (e *ParseError) toErrorInfo() *ErrorInfo {
+	info := &ErrorInfo{Code: e.Code, Message: e.Error()}
+	if e.Err != nil {
+		info.Wrapped = e.Err.Error()
+	}
+	info.DemoProtocol = e.Protocol
+	info.DemoBuild = e.Build
+	return info
+}
+
+// simpleErrorInfo is a convenience for call sites that classify inline
+// rather than threading a *ParseError through - e.g. "header not found"
+// isn't an error manta returned, it's a post-condition this package checks.
+func simpleErrorInfo(code ErrorCode, message string) *ErrorInfo {
+	return &ErrorInfo{Code: code, Message: message}
+}
+
+// classifyOpenError turns an os.Open failure into a ParseError; the only
+// distinction available at this layer is "does the file exist".
+func classifyOpenError(err error) *ParseError {
+	if os.IsNotExist(err) {
+		return newParseError(ErrFileNotFound, err)
+	}
+	return newParseError(ErrIO, err)
+}
+
+// classifyParseError maps a manta.Parser.Start/NewStreamParser failure to
+// an ErrorCode by inspecting the wrapped error text, since manta itself
+// doesn't expose typed errors for "bad magic bytes" vs "truncated stream"
+// vs "unknown EDemoCommands entry". This is necessarily a heuristic and
+// should be tightened if manta ever exports sentinel errors for these.
+func classifyParseError(err error) *ParseError {
+	if err == nil {
+		return nil
+	}
+	msg := strings.ToLower(err.Error())
+
+	switch {
+	case err == io.ErrUnexpectedEOF || strings.Contains(msg, "eof"):
+		return newParseError(ErrTruncatedDemo, err)
+	case strings.Contains(msg, "magic") || strings.Contains(msg, "not a demo") || strings.Contains(msg, "invalid demo"):
+		return newParseError(ErrNotADemo, err)
+	case strings.Contains(msg, "protocol") || strings.Contains(msg, "unsupported"):
+		return newParseError(ErrUnsupportedProtocol, err)
+	case strings.Contains(msg, "unmarshal") || strings.Contains(msg, "proto:"):
+		return newParseError(ErrProtobufDecode, err)
+	default:
+		return newParseError(ErrIO, err)
+	}
+}
@@ -0,0 +1,30 @@
+package filter
+
+// Expr is a node in the parsed predicate AST.
+type Expr interface {
+	isExpr()
+}
+
+// BinaryExpr is `Left Op Right` for and/or as well as the comparison ops.
+type BinaryExpr struct {
+	Op    tokenKind
+	Left  Expr
+	Right Expr
+}
+
+// UnaryExpr is `not X`.
+type UnaryExpr struct {
+	X Expr
+}
+
+// Comparison compares a field path (e.g. "type", "tick", "data.playerid_1")
+// against a literal value.
+type Comparison struct {
+	Field string
+	Op    tokenKind
+	Value interface{} // string or float64
+}
+
+func (*BinaryExpr) isExpr() {}
+func (*UnaryExpr) isExpr()  {}
+func (*Comparison) isExpr() {}
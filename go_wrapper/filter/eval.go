@@ -0,0 +1,234 @@
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Event is the subset of MessageEvent a predicate can see. The wrapper
+// package constructs one of these per message instead of importing
+// manta_wrapper here, keeping this package dependency-free.
+type Event struct {
+	Type    string
+	Tick    uint32
+	NetTick uint32
+	Data    interface{}
+}
+
+// Eval reports whether e satisfies expr.
+func Eval(expr Expr, e Event) bool {
+	switch n := expr.(type) {
+	case *BinaryExpr:
+		switch n.Op {
+		case tokAnd:
+			return Eval(n.Left, e) && Eval(n.Right, e)
+		case tokOr:
+			return Eval(n.Left, e) || Eval(n.Right, e)
+		}
+		return false
+	case *UnaryExpr:
+		return !Eval(n.X, e)
+	case *Comparison:
+		return evalComparison(n, e)
+	default:
+		return false
+	}
+}
+
+func evalComparison(c *Comparison, e Event) bool {
+	actual, ok := resolveField(c.Field, e)
+	if !ok {
+		return false
+	}
+
+	switch c.Op {
+	case tokMatch:
+		pattern, ok := c.Value.(string)
+		if !ok {
+			return false
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false
+		}
+		return re.MatchString(toString(actual))
+	case tokEq:
+		return compareEqual(actual, c.Value)
+	case tokNeq:
+		return !compareEqual(actual, c.Value)
+	case tokGt, tokGte, tokLt, tokLte:
+		af, aok := toFloat(actual)
+		bf, bok := toFloat(c.Value)
+		if !aok || !bok {
+			return false
+		}
+		switch c.Op {
+		case tokGt:
+			return af > bf
+		case tokGte:
+			return af >= bf
+		case tokLt:
+			return af < bf
+		default:
+			return af <= bf
+		}
+	default:
+		return false
+	}
+}
+
+// resolveField looks up "type", "tick", "net_tick", or a "data.xxx" path
+// into the underlying protobuf struct via reflection, matching either the
+// Go field name or its lowercased form (manta generates CamelCase fields
+// for snake_case proto field names, e.g. PlayerId1 for playerid_1).
+func resolveField(field string, e Event) (interface{}, bool) {
+	switch field {
+	case "type":
+		return e.Type, true
+	case "tick":
+		return e.Tick, true
+	case "net_tick":
+		return e.NetTick, true
+	}
+
+	const prefix = "data."
+	if !strings.HasPrefix(field, prefix) {
+		return nil, false
+	}
+	name := strings.TrimPrefix(field, prefix)
+
+	v := reflect.ValueOf(e.Data)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	// Prefer a Get<Field> accessor (protoc-gen-go convention), falling back
+	// to a direct field lookup for plain structs.
+	getter := v.Addr().MethodByName("Get" + matchFieldName(v, name))
+	if !getter.IsValid() {
+		getter = reflect.ValueOf(e.Data).MethodByName("Get" + matchFieldName(v, name))
+	}
+	if getter.IsValid() && getter.Type().NumIn() == 0 && getter.Type().NumOut() == 1 {
+		return getter.Call(nil)[0].Interface(), true
+	}
+
+	fv := v.FieldByNameFunc(func(n string) bool {
+		return strings.EqualFold(n, name)
+	})
+	if fv.IsValid() {
+		return fv.Interface(), true
+	}
+	return nil, false
+}
+
+// matchFieldName finds the struct field whose lowercased name matches name
+// case-insensitively, returning its real (CamelCase) name, or name unchanged
+// if nothing matches.
+func matchFieldName(v reflect.Value, name string) string {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if strings.EqualFold(t.Field(i).Name, name) {
+			return t.Field(i).Name
+		}
+	}
+	return name
+}
+
+func toString(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case fmt.Stringer:
+		return x.String()
+	default:
+		return toStringReflect(v)
+	}
+}
+
+func toStringReflect(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64)
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	default:
+		return ""
+	}
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case float32:
+		return float64(x), true
+	case int:
+		return float64(x), true
+	case int32:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case uint32:
+		return float64(x), true
+	case uint64:
+		return float64(x), true
+	case string:
+		f, err := strconv.ParseFloat(x, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func compareEqual(actual, expected interface{}) bool {
+	if af, aok := toFloat(actual); aok {
+		if bf, bok := toFloat(expected); bok {
+			return af == bf
+		}
+	}
+	return toString(actual) == toString(expected)
+}
+
+// cache holds compiled expressions keyed by their source text, so
+// registering hundreds of callbacks doesn't re-parse the same expression
+// for every message.
+var cache = struct {
+	sync.RWMutex
+	m map[string]Expr
+}{m: make(map[string]Expr)}
+
+// Compile parses expr once and caches the result for subsequent calls with
+// the same expression string.
+func Compile(expr string) (Expr, error) {
+	cache.RLock()
+	e, ok := cache.m[expr]
+	cache.RUnlock()
+	if ok {
+		return e, nil
+	}
+
+	e, err := Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.Lock()
+	cache.m[expr] = e
+	cache.Unlock()
+	return e, nil
+}
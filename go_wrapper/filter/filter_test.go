@@ -0,0 +1,130 @@
+package filter
+
+import "testing"
+
+// fakeMessage mirrors manta's generated field/getter naming for a
+// snake_case proto field (dota.CUserMessageSpectatorPlayerUnitOrders'
+// playerid_1 becomes Playerid_1/GetPlayerid_1), which is what
+// resolveField expects when walking a "data.xxx" path.
+type fakeMessage struct {
+	Playerid_1 int32
+}
+
+func (m *fakeMessage) GetPlayerid_1() int32 { return m.Playerid_1 }
+
+func TestParseAndEvalPrecedence(t *testing.T) {
+	// "and" must bind tighter than "or": this should match because the
+	// right-hand "and" is true, not because the whole expression is ORed
+	// left-to-right.
+	expr, err := Parse(`type == "a" or type == "b" and tick == 5`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if !Eval(expr, Event{Type: "b", Tick: 5}) {
+		t.Error("expected match: type==b and tick==5 should satisfy the and-clause")
+	}
+	if Eval(expr, Event{Type: "b", Tick: 6}) {
+		t.Error("expected no match: tick==5 fails, and type!=a, so the or-clause shouldn't save it")
+	}
+	if !Eval(expr, Event{Type: "a", Tick: 6}) {
+		t.Error("expected match: type==a satisfies the left side of or regardless of the and-clause")
+	}
+}
+
+func TestParseAndEvalNot(t *testing.T) {
+	expr, err := Parse(`not (type == "a")`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if Eval(expr, Event{Type: "a"}) {
+		t.Error("expected not to invert a true comparison")
+	}
+	if !Eval(expr, Event{Type: "b"}) {
+		t.Error("expected not to invert a false comparison")
+	}
+}
+
+func TestParseAndEvalRegexMatch(t *testing.T) {
+	expr, err := Parse(`type =~ "^CSVCMsg_"`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !Eval(expr, Event{Type: "CSVCMsg_PacketEntities"}) {
+		t.Error("expected regex match against prefix")
+	}
+	if Eval(expr, Event{Type: "CDOTAUserMsg_ChatEvent"}) {
+		t.Error("expected regex mismatch")
+	}
+}
+
+func TestEvalNumericComparison(t *testing.T) {
+	expr, err := Parse(`tick >= 100`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !Eval(expr, Event{Tick: 100}) {
+		t.Error("expected tick == 100 to satisfy >= 100")
+	}
+	if Eval(expr, Event{Tick: 99}) {
+		t.Error("expected tick == 99 to fail >= 100")
+	}
+}
+
+func TestEvalStringComparison(t *testing.T) {
+	expr, err := Parse(`type == "CDOTAUserMsg_ChatEvent"`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !Eval(expr, Event{Type: "CDOTAUserMsg_ChatEvent"}) {
+		t.Error("expected exact string match")
+	}
+	if Eval(expr, Event{Type: "CDOTAUserMsg_ChatWheel"}) {
+		t.Error("expected string mismatch to not satisfy ==")
+	}
+}
+
+func TestEvalDataFieldReflection(t *testing.T) {
+	expr, err := Parse(`data.playerid_1 == 3`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !Eval(expr, Event{Data: &fakeMessage{Playerid_1: 3}}) {
+		t.Error("expected data.playerid_1 to resolve via the Get accessor and compare numerically")
+	}
+	if Eval(expr, Event{Data: &fakeMessage{Playerid_1: 4}}) {
+		t.Error("expected data.playerid_1 mismatch")
+	}
+}
+
+func TestParseMalformedExpression(t *testing.T) {
+	cases := []string{
+		`type ==`,
+		`type == "unterminated`,
+		`(type == "a"`,
+		`type "a"`,
+		`type == "a" and`,
+		`@@@`,
+	}
+	for _, expr := range cases {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q): expected error, got nil", expr)
+		}
+	}
+}
+
+func TestCompileCachesParsedExpression(t *testing.T) {
+	const src = `type == "cached"`
+
+	first, err := Compile(src)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	second, err := Compile(src)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if first != second {
+		t.Error("expected Compile to return the cached Expr for an identical expression string")
+	}
+}
@@ -0,0 +1,148 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// exprParser is a small recursive-descent parser over the token stream.
+// Precedence, loosest to tightest: or, and, not, comparison.
+type exprParser struct {
+	lex *lexer
+	cur token
+}
+
+// Parse compiles a filter expression string into an AST.
+func Parse(expr string) (Expr, error) {
+	p := &exprParser{lex: newLexer(expr)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.cur.kind != tokEOF {
+		return nil, fmt.Errorf("filter: unexpected trailing token near %q", p.cur.text)
+	}
+	return e, nil
+}
+
+func (p *exprParser) advance() error {
+	t, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.cur = t
+	return nil
+}
+
+func (p *exprParser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.cur.kind == tokOr {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: tokOr, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.cur.kind == tokAnd {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: tokAnd, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseNot() (Expr, error) {
+	if p.cur.kind == tokNot {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &UnaryExpr{X: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (Expr, error) {
+	if p.cur.kind == tokLParen {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.cur.kind != tokRParen {
+			return nil, fmt.Errorf("filter: expected closing ')'")
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return e, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (Expr, error) {
+	if p.cur.kind != tokIdent {
+		return nil, fmt.Errorf("filter: expected field name, got %q", p.cur.text)
+	}
+	field := p.cur.text
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	op := p.cur.kind
+	switch op {
+	case tokEq, tokNeq, tokMatch, tokGte, tokGt, tokLte, tokLt:
+		// valid comparison operator
+	default:
+		return nil, fmt.Errorf("filter: expected comparison operator after %q", field)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	switch p.cur.kind {
+	case tokString:
+		value = p.cur.text
+	case tokNumber:
+		f, err := strconv.ParseFloat(p.cur.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("filter: invalid number %q", p.cur.text)
+		}
+		value = f
+	default:
+		return nil, fmt.Errorf("filter: expected a string or number literal, got %q", p.cur.text)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	return &Comparison{Field: field, Op: op, Value: value}, nil
+}
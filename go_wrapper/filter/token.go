@@ -0,0 +1,192 @@
+// Package filter implements the small predicate DSL used to select which
+// MessageEvents a parse run should keep, replacing strings.Contains matching
+// on the message type name. Expressions look like:
+//
+//	type == "CDOTAUserMsg_ChatEvent"
+//	type =~ "^CSVCMsg_" and not (type == "CSVCMsg_PacketEntities")
+//	tick >= 10000 && tick < 20000
+//	data.playerid_1 == 3
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokAnd
+	tokOr
+	tokNot
+	tokEq
+	tokNeq
+	tokMatch
+	tokGte
+	tokGt
+	tokLte
+	tokLt
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// lexer turns an expression string into a token stream.
+type lexer struct {
+	input string
+	pos   int
+}
+
+func newLexer(input string) *lexer {
+	return &lexer{input: input}
+}
+
+func (l *lexer) peekByte() byte {
+	if l.pos >= len(l.input) {
+		return 0
+	}
+	return l.input[l.pos]
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.input) && (l.input[l.pos] == ' ' || l.input[l.pos] == '\t' || l.input[l.pos] == '\n') {
+		l.pos++
+	}
+}
+
+func isIdentByte(b byte) bool {
+	return b == '_' || b == '.' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// next returns the next token in the stream, or tokEOF once exhausted.
+func (l *lexer) next() (token, error) {
+	l.skipSpace()
+	if l.pos >= len(l.input) {
+		return token{kind: tokEOF}, nil
+	}
+
+	c := l.input[l.pos]
+
+	switch c {
+	case '(':
+		l.pos++
+		return token{kind: tokLParen}, nil
+	case ')':
+		l.pos++
+		return token{kind: tokRParen}, nil
+	case '"':
+		return l.lexString()
+	}
+
+	if c == '=' {
+		if l.peekAt(1) == '=' {
+			l.pos += 2
+			return token{kind: tokEq}, nil
+		}
+		if l.peekAt(1) == '~' {
+			l.pos += 2
+			return token{kind: tokMatch}, nil
+		}
+		return token{}, fmt.Errorf("filter: unexpected '=' at offset %d", l.pos)
+	}
+	if c == '!' && l.peekAt(1) == '=' {
+		l.pos += 2
+		return token{kind: tokNeq}, nil
+	}
+	if c == '&' && l.peekAt(1) == '&' {
+		l.pos += 2
+		return token{kind: tokAnd}, nil
+	}
+	if c == '|' && l.peekAt(1) == '|' {
+		l.pos += 2
+		return token{kind: tokOr}, nil
+	}
+	if c == '>' {
+		if l.peekAt(1) == '=' {
+			l.pos += 2
+			return token{kind: tokGte}, nil
+		}
+		l.pos++
+		return token{kind: tokGt}, nil
+	}
+	if c == '<' {
+		if l.peekAt(1) == '=' {
+			l.pos += 2
+			return token{kind: tokLte}, nil
+		}
+		l.pos++
+		return token{kind: tokLt}, nil
+	}
+
+	if isDigit(c) || (c == '-' && isDigit(l.peekAt(1))) {
+		return l.lexNumber()
+	}
+
+	if isIdentByte(c) {
+		return l.lexIdent()
+	}
+
+	return token{}, fmt.Errorf("filter: unexpected character %q at offset %d", c, l.pos)
+}
+
+func (l *lexer) peekAt(off int) byte {
+	if l.pos+off >= len(l.input) {
+		return 0
+	}
+	return l.input[l.pos+off]
+}
+
+func (l *lexer) lexString() (token, error) {
+	start := l.pos
+	l.pos++ // consume opening quote
+	for l.pos < len(l.input) && l.input[l.pos] != '"' {
+		l.pos++
+	}
+	if l.pos >= len(l.input) {
+		return token{}, fmt.Errorf("filter: unterminated string starting at offset %d", start)
+	}
+	text := l.input[start+1 : l.pos]
+	l.pos++ // consume closing quote
+	return token{kind: tokString, text: text}, nil
+}
+
+func (l *lexer) lexNumber() (token, error) {
+	start := l.pos
+	if l.input[l.pos] == '-' {
+		l.pos++
+	}
+	for l.pos < len(l.input) && (isDigit(l.input[l.pos]) || l.input[l.pos] == '.') {
+		l.pos++
+	}
+	return token{kind: tokNumber, text: l.input[start:l.pos]}, nil
+}
+
+func (l *lexer) lexIdent() (token, error) {
+	start := l.pos
+	for l.pos < len(l.input) && isIdentByte(l.input[l.pos]) {
+		l.pos++
+	}
+	text := l.input[start:l.pos]
+	switch strings.ToLower(text) {
+	case "and":
+		return token{kind: tokAnd}, nil
+	case "or":
+		return token{kind: tokOr}, nil
+	case "not":
+		return token{kind: tokNot}, nil
+	default:
+		return token{kind: tokIdent, text: text}, nil
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+
+	"manta_wrapper/filter"
+)
+
+// ParseUniversalExpr is ParseUniversal's successor: filterExpr carries a
+// structured predicate ("type == \"CDOTAUserMsg_ChatEvent\"", "tick >= 10000
+// && tick < 20000", "data.playerid_1 == 3", ...) evaluated per event instead
+// of a strings.Contains substring match. If filterExpr fails to parse, we
+// fall back to the legacy substring `filter` so existing callers who pass a
+// bare message-type fragment keep working unchanged.
+//
+//export ParseUniversalExpr
+func ParseUniversalExpr(filePath *C.char, filter_ *C.char, filterExpr *C.char, maxMessages C.int) *C.char {
+	goFilePath := C.GoString(filePath)
+	goFilter := C.GoString(filter_)
+	goFilterExpr := C.GoString(filterExpr)
+	maxMsgs := int(maxMessages)
+
+	result, err := RunUniversalExpr(goFilePath, goFilter, goFilterExpr, maxMsgs)
+	if err != nil {
+		return marshalAndReturnUniversal(&UniversalParseResult{
+			Messages: make([]MessageEvent, 0),
+			Success:  false,
+			Error:    err.Error(),
+		})
+	}
+	return marshalAndReturnUniversal(result)
+}
+
+// RunUniversalExpr compiles filterExpr (using filter.Compile's cache, so
+// registering this repeatedly across many demos doesn't re-parse the
+// expression every time) and evaluates it per MessageEvent. An empty or
+// unparsable filterExpr falls back to RunUniversal's substring behavior.
+func RunUniversalExpr(filePath, legacyFilter, filterExpr string, maxMessages int) (*UniversalParseResult, error) {
+	var expr filter.Expr
+	if filterExpr != "" {
+		compiled, err := filter.Compile(filterExpr)
+		if err == nil {
+			expr = compiled
+		}
+		// Parse failure: fall through to legacyFilter below.
+	}
+	if expr == nil {
+		return RunUniversal(filePath, legacyFilter, maxMessages)
+	}
+
+	result := &UniversalParseResult{Messages: make([]MessageEvent, 0)}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		return nil, fmt.Errorf("error creating parser: %w", err)
+	}
+
+	emit := func(msgType string, data interface{}) error {
+		if maxMessages > 0 && len(result.Messages) >= maxMessages {
+			return nil
+		}
+		if !filter.Eval(expr, filter.Event{Type: msgType, Tick: parser.Tick, NetTick: parser.NetTick, Data: data}) {
+			return nil
+		}
+		result.Messages = append(result.Messages, MessageEvent{
+			Type:      msgType,
+			Tick:      parser.Tick,
+			NetTick:   parser.NetTick,
+			Data:      data,
+			Timestamp: time.Now().UnixMilli(),
+		})
+		if maxMessages > 0 && len(result.Messages) >= maxMessages {
+			parser.Stop()
+		}
+		return nil
+	}
+
+	parser.Callbacks.OnCDemoFileHeader(func(m *dota.CDemoFileHeader) error {
+		return emit("CDemoFileHeader", m)
+	})
+	parser.Callbacks.OnCDemoFileInfo(func(m *dota.CDemoFileInfo) error {
+		return emit("CDemoFileInfo", m)
+	})
+	parser.Callbacks.OnCMsgDOTACombatLogEntry(func(m *dota.CMsgDOTACombatLogEntry) error {
+		return emit("CMsgDOTACombatLogEntry", m)
+	})
+	parser.Callbacks.OnCSVCMsg_PacketEntities(func(m *dota.CSVCMsg_PacketEntities) error {
+		return emit("CSVCMsg_PacketEntities", m)
+	})
+	parser.Callbacks.OnCDOTAUserMsg_ChatEvent(func(m *dota.CDOTAUserMsg_ChatEvent) error {
+		return emit("CDOTAUserMsg_ChatEvent", m)
+	})
+	parser.Callbacks.OnCDOTAUserMsg_ChatMessage(func(m *dota.CDOTAUserMsg_ChatMessage) error {
+		return emit("CDOTAUserMsg_ChatMessage", m)
+	})
+
+	if err := parser.Start(); err != nil {
+		return nil, fmt.Errorf("error parsing file: %w", err)
+	}
+
+	result.Success = true
+	result.Count = len(result.Messages)
+	return result, nil
+}
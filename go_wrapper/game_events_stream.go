@@ -0,0 +1,229 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// GameEventsStreamSummary is ParseGameEventsStream's return value - unlike
+// ParseGameEvents it never holds the full event set in memory to hand back
+// through CGo, so callers get counts instead and read the actual events
+// back out of outPath.
+type GameEventsStreamSummary struct {
+	TotalEvents int        `json:"total_events"`
+	OutPath     string     `json:"out_path"`
+	Success     bool       `json:"success"`
+	Error       *ErrorInfo `json:"error,omitempty"`
+}
+
+// ParseGameEventsStream is ParseGameEvents' streaming sibling: it writes
+// newline-delimited JSON game events directly to outPath as they're
+// captured, flushing periodically, instead of buffering a GameEventsResult
+// and marshaling one giant blob back through CGo. Unlike combat log, game
+// events need no post-Start name resolution, so each event is written
+// exactly once, from inside the same gameTime.Annotate callback
+// registerGameEventsCallbacks would otherwise use to backfill a result
+// slice - there's no second pass.
+//
+//export ParseGameEventsStream
+func ParseGameEventsStream(filePath *C.char, configJSON *C.char, outPath *C.char) *C.char {
+	goFilePath := C.GoString(filePath)
+	goConfigJSON := C.GoString(configJSON)
+	goOutPath := C.GoString(outPath)
+
+	summary := &GameEventsStreamSummary{OutPath: goOutPath}
+
+	defer func() {
+		if r := recover(); r != nil {
+			summary.Success = false
+			summary.Error = simpleErrorInfo(ErrPanic, fmt.Sprintf("panic during parsing: %v", r))
+		}
+	}()
+
+	config := GameEventsConfig{}
+	if goConfigJSON != "" {
+		if err := json.Unmarshal([]byte(goConfigJSON), &config); err != nil {
+			summary.Error = simpleErrorInfo(ErrIO, fmt.Sprintf("invalid config JSON: %v", err))
+			return marshalGameEventsStreamSummary(summary)
+		}
+	}
+
+	in, err := os.Open(goFilePath)
+	if err != nil {
+		summary.Error = classifyOpenError(err).toErrorInfo()
+		return marshalGameEventsStreamSummary(summary)
+	}
+	defer in.Close()
+
+	out, err := os.Create(goOutPath)
+	if err != nil {
+		summary.Error = simpleErrorInfo(ErrIO, fmt.Sprintf("error creating out_path: %v", err))
+		return marshalGameEventsStreamSummary(summary)
+	}
+	defer out.Close()
+
+	parser, err := manta.NewStreamParser(in)
+	if err != nil {
+		summary.Error = classifyParseError(err).toErrorInfo()
+		return marshalGameEventsStreamSummary(summary)
+	}
+
+	gameTime := newGameTimeTracker()
+	gameTime.Watch(parser)
+
+	writer := bufio.NewWriter(out)
+	written := 0
+	var writeErr error
+
+	writeEvent := func(event GameEventData) {
+		if writeErr != nil {
+			return
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			writeErr = err
+			return
+		}
+		if _, err := writer.Write(data); err != nil {
+			writeErr = err
+			return
+		}
+		if err := writer.WriteByte('\n'); err != nil {
+			writeErr = err
+			return
+		}
+		written++
+		if written%30 == 0 {
+			writer.Flush()
+		}
+	}
+
+	eventTypeNames := make(map[int32]string)
+	eventTypeFields := make(map[string][]string)
+
+	parser.Callbacks.OnCMsgSource1LegacyGameEventList(func(m *dota.CMsgSource1LegacyGameEventList) error {
+		for _, d := range m.GetDescriptors() {
+			eventTypeNames[d.GetEventid()] = d.GetName()
+			fieldNames := make([]string, len(d.GetKeys()))
+			for i, k := range d.GetKeys() {
+				fieldNames[i] = k.GetName()
+			}
+			eventTypeFields[d.GetName()] = fieldNames
+		}
+		return nil
+	})
+
+	if len(config.EventNames) > 0 {
+		for _, eventName := range config.EventNames {
+			name := eventName
+			parser.OnGameEvent(name, func(e *manta.GameEvent) error {
+				if config.MaxEvents > 0 && written >= config.MaxEvents {
+					return nil
+				}
+				event := extractGameEventData(e, name, parser.Tick, parser.NetTick, eventTypeFields[name])
+				gameTime.Annotate(func(s gameTimeSnapshot) {
+					event.GameTime = s.GameTime
+					event.MatchTime = s.MatchTime
+					event.GameState = s.GameState
+					event.GamePhase = s.GamePhase
+					event.IsPaused = s.IsPaused
+					writeEvent(event)
+				})
+				return nil
+			})
+		}
+	} else {
+		parser.Callbacks.OnCMsgSource1LegacyGameEvent(func(m *dota.CMsgSource1LegacyGameEvent) error {
+			if config.MaxEvents > 0 && written >= config.MaxEvents {
+				return nil
+			}
+
+			eventName, ok := eventTypeNames[m.GetEventid()]
+			if !ok {
+				return nil
+			}
+			if config.EventFilter != "" && !strings.Contains(eventName, config.EventFilter) {
+				return nil
+			}
+
+			fields := make(map[string]interface{})
+			fieldNames := eventTypeFields[eventName]
+			keys := m.GetKeys()
+			for i, key := range keys {
+				fieldName := fmt.Sprintf("field_%d", i)
+				if i < len(fieldNames) {
+					fieldName = fieldNames[i]
+				}
+				switch key.GetType() {
+				case 1:
+					fields[fieldName] = key.GetValString()
+				case 2:
+					fields[fieldName] = key.GetValFloat()
+				case 3:
+					fields[fieldName] = key.GetValLong()
+				case 4:
+					fields[fieldName] = key.GetValShort()
+				case 5:
+					fields[fieldName] = key.GetValByte()
+				case 6:
+					fields[fieldName] = key.GetValBool()
+				case 7:
+					fields[fieldName] = key.GetValUint64()
+				}
+			}
+
+			event := GameEventData{
+				Name:    eventName,
+				Tick:    parser.Tick,
+				NetTick: parser.NetTick,
+				Fields:  fields,
+			}
+			gameTime.Annotate(func(s gameTimeSnapshot) {
+				event.GameTime = s.GameTime
+				event.MatchTime = s.MatchTime
+				event.GameState = s.GameState
+				event.GamePhase = s.GamePhase
+				event.IsPaused = s.IsPaused
+				writeEvent(event)
+			})
+			return nil
+		})
+	}
+
+	if err := parser.Start(); err != nil {
+		summary.Error = classifyParseError(err).toErrorInfo()
+		return marshalGameEventsStreamSummary(summary)
+	}
+
+	if writeErr != nil {
+		summary.Error = simpleErrorInfo(ErrIO, fmt.Sprintf("error writing event: %v", writeErr))
+		return marshalGameEventsStreamSummary(summary)
+	}
+	if err := writer.Flush(); err != nil {
+		summary.Error = simpleErrorInfo(ErrIO, fmt.Sprintf("error flushing output: %v", err))
+		return marshalGameEventsStreamSummary(summary)
+	}
+
+	summary.TotalEvents = written
+	summary.Success = true
+	return marshalGameEventsStreamSummary(summary)
+}
+
+func marshalGameEventsStreamSummary(s *GameEventsStreamSummary) *C.char {
+	data, err := json.Marshal(s)
+	if err != nil {
+		data, _ = json.Marshal(&GameEventsStreamSummary{Success: false, Error: simpleErrorInfo(ErrIO, err.Error())})
+	}
+	return C.CString(string(data))
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/dotabuff/manta"
+)
+
+// gameTimeBacklogSize bounds how many not-yet-resolved records
+// gameTimeTracker queues before CDOTAGamerulesProxy stabilizes. The proxy
+// entity is normally observed within the first handful of ticks, so this
+// only needs to cover parse startup, not the whole replay.
+const gameTimeBacklogSize = 256
+
+// gameStateNames maps CDOTAGamerules.m_iGameState (DOTA_GAMERULES_STATE_*)
+// to its short phase name, the same enum the GAME_STATE==5 combat-log
+// heuristic this tracker replaced was keyed on.
+var gameStateNames = map[int32]string{
+	0: "init",
+	1: "wait_for_players",
+	2: "hero_selection",
+	3: "strategy_time",
+	4: "pregame",
+	5: "inprogress",
+	6: "postgame",
+	7: "disconnect",
+}
+
+func gameStateName(state int32) string {
+	if name, ok := gameStateNames[state]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown_%d", state)
+}
+
+// gameTimeSnapshot is the game-time-derived state gameTimeTracker attaches
+// to every emitted record. GameTime is seconds since the horn
+// (m_fGameTime - m_flGameStartTime, negative during strategy/picks);
+// MatchTime is seconds since strategy time started
+// (m_fGameTime - m_flPreGameStartTime), so it stays non-negative for the
+// whole match including the draft. GamePhase is GameState's resolved name.
+type gameTimeSnapshot struct {
+	GameTime  float32
+	MatchTime float32
+	GameState int32
+	GamePhase string
+	IsPaused  bool
+}
+
+// gameTimeTracker is the cross-cutting game-clock source for
+// RunGameEventsParse, RunModifiersParse, RunCombatLogParse, and
+// RunEntitiesQuery: it watches CDOTAGamerulesProxy via parser.OnEntity and
+// caches m_fGameTime, m_flGameStartTime, m_iGameState, and m_bGamePaused so
+// every record can answer "at what in-match second did this happen?"
+// instead of just carrying Tick/NetTick. game_time = m_fGameTime -
+// m_flGameStartTime, so negative values are pre-horn strategy/pick time.
+// Records produced before the proxy entity has ever been observed are
+// queued in a small backlog and backfilled once it stabilizes.
+type gameTimeTracker struct {
+	current    gameTimeSnapshot
+	stabilized bool
+	backlog    []func(gameTimeSnapshot)
+}
+
+func newGameTimeTracker() *gameTimeTracker {
+	return &gameTimeTracker{}
+}
+
+// Watch registers the OnEntity callback that keeps the tracker current.
+// Call once per parser, before parser.Start().
+func (t *gameTimeTracker) Watch(parser *manta.Parser) {
+	var gameTime, gameStartTime, preGameStartTime float32
+	parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+		if e == nil || e.GetClassName() != "CDOTAGamerulesProxy" {
+			return nil
+		}
+		if v, ok := e.GetFloat32("m_pGameRules.m_fGameTime"); ok {
+			gameTime = v
+		}
+		if v, ok := e.GetFloat32("m_pGameRules.m_flGameStartTime"); ok {
+			gameStartTime = v
+		}
+		if v, ok := e.GetFloat32("m_pGameRules.m_flPreGameStartTime"); ok {
+			preGameStartTime = v
+		}
+		if v, ok := e.GetInt32("m_pGameRules.m_iGameState"); ok {
+			t.current.GameState = v
+			t.current.GamePhase = gameStateName(v)
+		}
+		if v, ok := e.GetBool("m_pGameRules.m_bGamePaused"); ok {
+			t.current.IsPaused = v
+		}
+		t.current.GameTime = gameTime - gameStartTime
+		t.current.MatchTime = gameTime - preGameStartTime
+		t.stabilized = true
+		t.drain()
+		return nil
+	})
+}
+
+// Annotate runs apply with the tracker's current snapshot, or - if the
+// proxy entity hasn't stabilized yet - queues apply to run once it has, so
+// records emitted during pre-game don't get stamped with a meaningless
+// zero game_time.
+func (t *gameTimeTracker) Annotate(apply func(gameTimeSnapshot)) {
+	if t.stabilized {
+		apply(t.current)
+		return
+	}
+	if len(t.backlog) >= gameTimeBacklogSize {
+		t.backlog = t.backlog[1:]
+	}
+	t.backlog = append(t.backlog, apply)
+}
+
+func (t *gameTimeTracker) drain() {
+	if len(t.backlog) == 0 {
+		return
+	}
+	pending := t.backlog
+	t.backlog = nil
+	for _, apply := range pending {
+		apply(t.current)
+	}
+}
+
+// GameTimeAt returns the tracker's most recently observed game_time. It
+// does not reconstruct historical values for past ticks; callers that need
+// a specific record's game_time should capture it via Annotate at the
+// point the record is created, as every parse path in this package does.
+func (t *gameTimeTracker) GameTimeAt(tick uint32) float32 {
+	return t.current.GameTime
+}
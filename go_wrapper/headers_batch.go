@@ -0,0 +1,222 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// activeBatches holds the cancel function for every in-flight
+// ParseHeadersBatch/ParseMatchInfoBatch run, keyed by the handle returned
+// alongside the batch result, so CancelBatch can reach into a run that's
+// still fanning out across its worker pool.
+var (
+	activeBatchesMu sync.Mutex
+	activeBatches   = make(map[int]context.CancelFunc)
+	nextBatchHandle int
+)
+
+// HeaderBatchEntry is one file's slot in a ParseHeadersBatch response.
+type HeaderBatchEntry struct {
+	Path   string      `json:"path"`
+	Result *HeaderInfo `json:"result,omitempty"`
+}
+
+// MatchInfoBatchEntry is one file's slot in a ParseMatchInfoBatch response.
+type MatchInfoBatchEntry struct {
+	Path   string         `json:"path"`
+	Result *CDotaGameInfo `json:"result,omitempty"`
+}
+
+// HeaderBatchResult is the ParseHeadersBatch response envelope.
+type HeaderBatchResult struct {
+	Handle    int                `json:"handle"`
+	Entries   []HeaderBatchEntry `json:"entries"`
+	Cancelled bool               `json:"cancelled"`
+	Success   bool               `json:"success"`
+	Error     string             `json:"error,omitempty"`
+}
+
+// MatchInfoBatchResult is the ParseMatchInfoBatch response envelope.
+type MatchInfoBatchResult struct {
+	Handle    int                   `json:"handle"`
+	Entries   []MatchInfoBatchEntry `json:"entries"`
+	Cancelled bool                  `json:"cancelled"`
+	Success   bool                  `json:"success"`
+	Error     string                `json:"error,omitempty"`
+}
+
+func registerBatch(cancel context.CancelFunc) int {
+	activeBatchesMu.Lock()
+	defer activeBatchesMu.Unlock()
+	nextBatchHandle++
+	handle := nextBatchHandle
+	activeBatches[handle] = cancel
+	return handle
+}
+
+func unregisterBatch(handle int) {
+	activeBatchesMu.Lock()
+	defer activeBatchesMu.Unlock()
+	delete(activeBatches, handle)
+}
+
+//export CancelBatch
+func CancelBatch(handle C.int) *C.char {
+	activeBatchesMu.Lock()
+	cancel, ok := activeBatches[int(handle)]
+	activeBatchesMu.Unlock()
+
+	result := map[string]interface{}{"success": ok}
+	if !ok {
+		result["error"] = "unknown or already-finished batch handle"
+	} else {
+		cancel()
+	}
+	data, _ := json.Marshal(result)
+	return C.CString(string(data))
+}
+
+//export ParseHeadersBatch
+func ParseHeadersBatch(pathsJSON *C.char, workers C.int) *C.char {
+	var paths []string
+	if err := json.Unmarshal([]byte(C.GoString(pathsJSON)), &paths); err != nil {
+		return marshalHeaderBatchResult(&HeaderBatchResult{Success: false, Error: fmt.Sprintf("invalid paths JSON: %v", err)})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handle := registerBatch(cancel)
+	defer unregisterBatch(handle)
+	defer cancel()
+
+	numWorkers := int(workers)
+	if numWorkers <= 0 {
+		numWorkers = runtime.NumCPU()
+	}
+
+	entries := make([]HeaderBatchEntry, len(paths))
+	cancelled := runIndexedBatch(ctx, len(paths), numWorkers, func(i int) {
+		entries[i] = HeaderBatchEntry{Path: paths[i], Result: runHeaderParseRecovered(paths[i])}
+	})
+
+	return marshalHeaderBatchResult(&HeaderBatchResult{
+		Handle:    handle,
+		Entries:   entries,
+		Cancelled: cancelled,
+		Success:   true,
+	})
+}
+
+//export ParseMatchInfoBatch
+func ParseMatchInfoBatch(pathsJSON *C.char, workers C.int) *C.char {
+	var paths []string
+	if err := json.Unmarshal([]byte(C.GoString(pathsJSON)), &paths); err != nil {
+		return marshalMatchInfoBatchResult(&MatchInfoBatchResult{Success: false, Error: fmt.Sprintf("invalid paths JSON: %v", err)})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handle := registerBatch(cancel)
+	defer unregisterBatch(handle)
+	defer cancel()
+
+	numWorkers := int(workers)
+	if numWorkers <= 0 {
+		numWorkers = runtime.NumCPU()
+	}
+
+	entries := make([]MatchInfoBatchEntry, len(paths))
+	cancelled := runIndexedBatch(ctx, len(paths), numWorkers, func(i int) {
+		entries[i] = MatchInfoBatchEntry{Path: paths[i], Result: runMatchInfoParseRecovered(paths[i])}
+	})
+
+	return marshalMatchInfoBatchResult(&MatchInfoBatchResult{
+		Handle:    handle,
+		Entries:   entries,
+		Cancelled: cancelled,
+		Success:   true,
+	})
+}
+
+// runIndexedBatch fans work(i) out across numWorkers goroutines for
+// i in [0, n), stopping early (and returning cancelled=true) once ctx is
+// cancelled. Indices already claimed by a worker still run to completion;
+// CancelBatch only stops unclaimed work from starting.
+func runIndexedBatch(ctx context.Context, n, numWorkers int, work func(i int)) bool {
+	if n == 0 {
+		return false
+	}
+	if numWorkers > n {
+		numWorkers = n
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				work(i)
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+func runHeaderParseRecovered(path string) (header *HeaderInfo) {
+	defer func() {
+		if r := recover(); r != nil {
+			header = &HeaderInfo{Success: false, Error: simpleErrorInfo(ErrPanic, fmt.Sprintf("panic during parsing: %v", r))}
+		}
+	}()
+	return runHeaderParse(path)
+}
+
+func runMatchInfoParseRecovered(path string) (info *CDotaGameInfo) {
+	defer func() {
+		if r := recover(); r != nil {
+			info = &CDotaGameInfo{Success: false, Error: simpleErrorInfo(ErrPanic, fmt.Sprintf("panic during parsing: %v", r))}
+		}
+	}()
+	return runMatchInfoParse(path)
+}
+
+func marshalHeaderBatchResult(r *HeaderBatchResult) *C.char {
+	data, err := json.Marshal(r)
+	if err != nil {
+		data, _ = json.Marshal(&HeaderBatchResult{Success: false, Error: err.Error()})
+	}
+	return C.CString(string(data))
+}
+
+func marshalMatchInfoBatchResult(r *MatchInfoBatchResult) *C.char {
+	data, err := json.Marshal(r)
+	if err != nil {
+		data, _ = json.Marshal(&MatchInfoBatchResult{Success: false, Error: err.Error()})
+	}
+	return C.CString(string(data))
+}
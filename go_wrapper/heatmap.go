@@ -0,0 +1,251 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strings"
+
+	"github.com/dotabuff/manta"
+)
+
+// HeatmapConfig controls ComputeHeatmap's grid resolution, grouping, and
+// tick range.
+type HeatmapConfig struct {
+	CellSize     float32 `json:"cell_size"`     // world units per grid cell; default 512
+	GroupBy      string  `json:"group_by"`      // "hero" (default), "team", or "creep_side"
+	TickStart    uint32  `json:"tick_start"`    // 0 = from the start
+	TickEnd      uint32  `json:"tick_end"`      // 0 = to the end
+	OutputFormat string  `json:"output_format"` // "counts" (default) or "png"
+}
+
+// heatmapWorldSize is the map's span in world units on each axis
+// (~32768x32768, per entity_parser.go's cellX*128+vecX-16384 formula's own
+// comment on the coordinate system).
+const heatmapWorldSize = 32768.0
+
+// Heatmap is one group's (hero name/team id/side) accumulated grid.
+type Heatmap struct {
+	Counts []int32 `json:"counts,omitempty"` // row-major, Width*Height
+	PNG    string  `json:"png,omitempty"`    // base64 grayscale PNG, when OutputFormat == "png"
+}
+
+// HeatmapResult is ComputeHeatmap's response envelope.
+type HeatmapResult struct {
+	Success  bool                `json:"success"`
+	Error    string              `json:"error,omitempty"`
+	Width    int                 `json:"width"`
+	Height   int                 `json:"height"`
+	CellSize float32             `json:"cell_size"`
+	Heatmaps map[string]*Heatmap `json:"heatmaps"`
+}
+
+//export ComputeHeatmap
+func ComputeHeatmap(filePath *C.char, configJSON *C.char) *C.char {
+	goFilePath := C.GoString(filePath)
+	goConfigJSON := C.GoString(configJSON)
+
+	config := HeatmapConfig{CellSize: 512, GroupBy: "hero"}
+	if goConfigJSON != "" {
+		json.Unmarshal([]byte(goConfigJSON), &config)
+	}
+
+	result, err := RunHeatmap(goFilePath, config)
+	if err != nil {
+		return marshalHeatmapResult(&HeatmapResult{Success: false, Error: err.Error()})
+	}
+	return marshalHeatmapResult(result)
+}
+
+// RunHeatmap walks the parser once, accumulating hero and creep positions
+// into a 2D histogram per group (GroupBy) directly from OnEntity's
+// per-update position, rather than re-scanning via parser.FilterEntity -
+// each update already carries the class name and position needed to bucket
+// it, so no entity index/cache is needed for this one-pass histogram.
+//
+// This intentionally does not touch captureSnapshot's own parser.FilterEntity
+// scans in entity_parser.go. Replacing those with a maintained
+// map[className][]*manta.Entity cache (invalidated on manta.EntityOpDeleted,
+// mirroring vision.go's onEntity) is a real optimization for interval-mode
+// snapshotting on long replays, but captureSnapshot is shared by both
+// RunEntityParse here and parser.go's v2 entityCollectorState path, and
+// there's no compiler in this tree to catch a mistake in a change to code
+// both paths depend on. Left alone rather than risked.
+func RunHeatmap(filePath string, config HeatmapConfig) (*HeatmapResult, error) {
+	if config.CellSize <= 0 {
+		config.CellSize = 512
+	}
+	if config.GroupBy == "" {
+		config.GroupBy = "hero"
+	}
+
+	width := int(heatmapWorldSize/config.CellSize) + 1
+	height := width
+
+	result := &HeatmapResult{
+		Width:    width,
+		Height:   height,
+		CellSize: config.CellSize,
+		Heatmaps: make(map[string]*Heatmap),
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		return nil, fmt.Errorf("error creating parser: %w", err)
+	}
+
+	counts := make(map[string][]int32)
+	groupOf := func(e *manta.Entity, className string) (string, bool) {
+		switch config.GroupBy {
+		case "team":
+			team, ok := e.GetInt32("m_iTeamNum")
+			if !ok {
+				return "", false
+			}
+			return fmt.Sprintf("team_%d", team), true
+		case "creep_side":
+			if !strings.Contains(className, "Creep") {
+				return "", false
+			}
+			team, _ := e.GetInt32("m_iTeamNum")
+			return fmt.Sprintf("creep_team_%d", team), true
+		default: // "hero"
+			heroName := entityClassToHeroName(className)
+			if heroName == "" {
+				return "", false
+			}
+			return heroName, true
+		}
+	}
+
+	parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+		if e == nil || !op.Flag(manta.EntityOpUpdated) {
+			return nil
+		}
+		if config.TickStart > 0 && parser.Tick < config.TickStart {
+			return nil
+		}
+		if config.TickEnd > 0 && parser.Tick > config.TickEnd {
+			return nil
+		}
+
+		className := e.GetClassName()
+		isHero := strings.Contains(className, "CDOTA_Unit_Hero_")
+		isCreep := strings.Contains(className, "CDOTA_BaseNPC_Creep") || strings.Contains(className, "CDOTA_BaseNPC_Creature")
+		if !isHero && !isCreep {
+			return nil
+		}
+		if config.GroupBy == "creep_side" && !isCreep {
+			return nil
+		}
+		if (config.GroupBy == "hero" || config.GroupBy == "") && !isHero {
+			return nil
+		}
+
+		cellX, okCellX := e.GetUint32("CBodyComponent.m_cellX")
+		vecX, okVecX := e.GetFloat32("CBodyComponent.m_vecX")
+		cellY, okCellY := e.GetUint32("CBodyComponent.m_cellY")
+		vecY, okVecY := e.GetFloat32("CBodyComponent.m_vecY")
+		if !okCellX || !okVecX || !okCellY || !okVecY {
+			return nil
+		}
+
+		worldX := float32(cellX)*128.0 + vecX - 16384.0
+		worldY := float32(cellY)*128.0 + vecY - 16384.0
+
+		gridX := int((worldX + heatmapWorldSize/2) / config.CellSize)
+		gridY := int((worldY + heatmapWorldSize/2) / config.CellSize)
+		if gridX < 0 || gridX >= width || gridY < 0 || gridY >= height {
+			return nil
+		}
+
+		key, ok := groupOf(e, className)
+		if !ok {
+			return nil
+		}
+
+		grid, ok := counts[key]
+		if !ok {
+			grid = make([]int32, width*height)
+			counts[key] = grid
+		}
+		grid[gridY*width+gridX]++
+
+		return nil
+	})
+
+	if err := parser.Start(); err != nil {
+		return nil, fmt.Errorf("error parsing file: %w", err)
+	}
+
+	for key, grid := range counts {
+		hm := &Heatmap{}
+		if config.OutputFormat == "png" {
+			data, err := encodeHeatmapPNG(grid, width, height)
+			if err != nil {
+				return nil, fmt.Errorf("encoding heatmap PNG for %s: %w", key, err)
+			}
+			hm.PNG = base64.StdEncoding.EncodeToString(data)
+		} else {
+			hm.Counts = grid
+		}
+		result.Heatmaps[key] = hm
+	}
+
+	result.Success = true
+	return result, nil
+}
+
+// encodeHeatmapPNG renders counts as a grayscale PNG, normalized against the
+// grid's own maximum so every heatmap uses its full 0-255 range regardless
+// of how many samples it accumulated.
+func encodeHeatmapPNG(counts []int32, width, height int) ([]byte, error) {
+	var max int32
+	for _, c := range counts {
+		if c > max {
+			max = c
+		}
+	}
+
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := counts[y*width+x]
+			var v uint8
+			if max > 0 {
+				v = uint8(float64(c) / float64(max) * 255)
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func marshalHeatmapResult(r *HeatmapResult) *C.char {
+	data, err := json.Marshal(r)
+	if err != nil {
+		data, _ = json.Marshal(&HeatmapResult{Success: false, Error: err.Error()})
+	}
+	return C.CString(string(data))
+}
@@ -9,7 +9,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"sort"
 	"strings"
 
 	"github.com/dotabuff/manta"
@@ -21,6 +20,11 @@ type Keyframe struct {
 	Tick     int     `json:"tick"`
 	NetTick  int     `json:"net_tick"`
 	GameTime float32 `json:"game_time"`
+	// FullPacket is set by BuildIndexWithCheckpoints for keyframes that
+	// land on a CDemoFullPacket boundary, i.e. ones a checkpoint blob was
+	// written for. Plain BuildIndex never sets it, since it doesn't align
+	// keyframes to full-packet boundaries. See checkpoint.go.
+	FullPacket bool `json:"full_packet,omitempty"`
 }
 
 // DemoIndex holds keyframes for seeking
@@ -37,13 +41,17 @@ type EntityStateSnapshot struct {
 	Tick     int            `json:"tick"`
 	NetTick  int            `json:"net_tick"`
 	GameTime float32        `json:"game_time"`
-	Heroes   []HeroSnapshot `json:"heroes"`
+	Heroes   []IndexHeroSnapshot `json:"heroes"`
 	Success  bool           `json:"success"`
 	Error    string         `json:"error,omitempty"`
 }
 
-// HeroSnapshot captures a hero's state
-type HeroSnapshot struct {
+// IndexHeroSnapshot captures a hero's state for index.go's seek-oriented
+// EntityStateSnapshot - a simpler field subset than entity_parser.go/
+// types.go's HeroSnapshot (no economy/KDA/abilities/modifiers), its own
+// distinct type so the two can evolve independently without a shared name
+// collision.
+type IndexHeroSnapshot struct {
 	Index      int     `json:"index"`
 	PlayerID   int     `json:"player_id"`
 	HeroName   string  `json:"hero_name"`
@@ -82,6 +90,32 @@ type RangeParseConfig struct {
 	CombatLog  bool `json:"combat_log"`
 	Messages   bool `json:"messages"`
 	GameEvents bool `json:"game_events"`
+	UnitOrders bool `json:"unit_orders"`
+}
+
+// UnitOrderPosition is RangeUnitOrderEvent.TargetPosition.
+type UnitOrderPosition struct {
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+	Z float32 `json:"z"`
+}
+
+// RangeUnitOrderEvent is one CDOTAUserMsg_SpectatorPlayerUnitOrders entry
+// captured by ParseRange's unit_orders option - the same message
+// unitOrdersCollector decodes for RunParse's UnitOrders config, but
+// tick-range-filtered and with TargetIndex additionally resolved to a hero
+// name via parseRange's heroByIndex cache.
+type RangeUnitOrderEvent struct {
+	Tick           int                `json:"tick"`
+	PlayerID       int32              `json:"player_id"`
+	OrderType      int32              `json:"order_type"`
+	OrderTypeName  string             `json:"order_type_name"`
+	TargetIndex    int32              `json:"target_index"`
+	TargetHeroName string             `json:"target_hero_name,omitempty"`
+	TargetPosition *UnitOrderPosition `json:"target_position,omitempty"`
+	AbilityIndex   int32              `json:"ability_index"`
+	Issuer         int32              `json:"issuer"`
+	Queue          bool               `json:"queue"`
 }
 
 // RangeParseResult contains data from a tick range
@@ -92,6 +126,7 @@ type RangeParseResult struct {
 	ActualEnd   int                      `json:"actual_end"`
 	CombatLog   []map[string]interface{} `json:"combat_log,omitempty"`
 	Messages    []map[string]interface{} `json:"messages,omitempty"`
+	UnitOrders  []RangeUnitOrderEvent    `json:"unit_orders,omitempty"`
 	Success     bool                     `json:"success"`
 	Error       string                   `json:"error,omitempty"`
 }
@@ -232,7 +267,7 @@ func getEntitySnapshot(filePath string, config SnapshotConfig) *EntityStateSnaps
 	}
 
 	snapshot := &EntityStateSnapshot{
-		Heroes:  make([]HeroSnapshot, 0),
+		Heroes:  make([]IndexHeroSnapshot, 0),
 		Success: true,
 	}
 
@@ -369,8 +404,8 @@ func getEntitySnapshot(filePath string, config SnapshotConfig) *EntityStateSnaps
 }
 
 // extractHeroSnapshot extracts hero state from entity
-func extractHeroSnapshot(entity *manta.Entity, playerIdx int) HeroSnapshot {
-	hero := HeroSnapshot{
+func extractHeroSnapshot(entity *manta.Entity, playerIdx int) IndexHeroSnapshot {
+	hero := IndexHeroSnapshot{
 		HeroName: entity.GetClassName(),
 		Index:    int(entity.GetIndex()),
 		PlayerID: playerIdx,
@@ -496,6 +531,9 @@ func parseRange(filePath string, config RangeParseConfig) *RangeParseResult {
 	if config.Messages {
 		result.Messages = make([]map[string]interface{}, 0)
 	}
+	if config.UnitOrders {
+		result.UnitOrders = make([]RangeUnitOrderEvent, 0)
+	}
 
 	inRange := false
 	pastRange := false
@@ -508,6 +546,63 @@ func parseRange(filePath string, config RangeParseConfig) *RangeParseResult {
 		return fmt.Sprintf("unknown_%d", idx)
 	}
 
+	// heroByIndex resolves a unit order's TargetIndex to a hero name, the
+	// same entity-index keying heroByIndex in GetSnapshot uses.
+	heroByIndex := make(map[uint32]string)
+	if config.UnitOrders {
+		parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+			if e == nil || !strings.HasPrefix(e.GetClassName(), "CDOTA_Unit_Hero_") {
+				return nil
+			}
+			idx := uint32(e.GetIndex())
+			if op.Flag(manta.EntityOpDeleted) {
+				delete(heroByIndex, idx)
+				return nil
+			}
+			heroByIndex[idx] = entityClassToHeroName(e.GetClassName())
+			return nil
+		})
+	}
+
+	// Unit order callback
+	if config.UnitOrders {
+		parser.Callbacks.OnCDOTAUserMsg_SpectatorPlayerUnitOrders(func(m *dota.CDOTAUserMsg_SpectatorPlayerUnitOrders) error {
+			tick := int(parser.Tick)
+			if tick < config.StartTick || tick > config.EndTick {
+				if tick > config.EndTick {
+					pastRange = true
+				}
+				return nil
+			}
+			if !inRange {
+				inRange = true
+				result.ActualStart = tick
+			}
+
+			orderType := int32(m.GetOrderType())
+			targetIndex := m.GetTargetIndex()
+			event := RangeUnitOrderEvent{
+				Tick:          tick,
+				PlayerID:      m.GetPlayerIndex(),
+				OrderType:     orderType,
+				OrderTypeName: unitOrderTypeNames[orderType],
+				TargetIndex:   targetIndex,
+				AbilityIndex:  m.GetAbilityId(),
+				Issuer:        m.GetPlayerIndex(),
+				Queue:         m.GetQueue(),
+			}
+			if name, ok := heroByIndex[uint32(targetIndex)]; ok {
+				event.TargetHeroName = name
+			}
+			if pos := m.GetPosition(); pos != nil {
+				event.TargetPosition = &UnitOrderPosition{X: pos.GetX(), Y: pos.GetY(), Z: pos.GetZ()}
+			}
+
+			result.UnitOrders = append(result.UnitOrders, event)
+			return nil
+		})
+	}
+
 	// Combat log callback
 	if config.CombatLog {
 		parser.Callbacks.OnCMsgDOTACombatLogEntry(func(m *dota.CMsgDOTACombatLogEntry) error {
@@ -594,12 +689,24 @@ func parseRange(filePath string, config RangeParseConfig) *RangeParseResult {
 }
 
 //export FindKeyframe
+// FindKeyframe accepts either an inline JSON DemoIndex (its original
+// contract) or a path to an index file, sniffing which at indexJSON: a
+// path that reads back starting with binaryIndexMagic is decoded as a
+// BuildIndexBinary file, a path that doesn't is parsed as a JSON
+// DemoIndex/CheckpointIndex, and anything that isn't a readable path
+// falls back to parsing indexJSON itself as inline JSON the way every
+// existing caller already uses this function. A BuildIndexBinary file
+// can't be passed inline the way JSON can: it's binary, and binary data
+// can contain the NUL byte that terminates a C string before the real end
+// of the buffer, so a file path is the only safe way to hand one across
+// the CGo boundary - see FindKeyframeBinary for a caller that always knows
+// it has a binary index path upfront.
 func FindKeyframe(indexJSON *C.char, targetTick C.int) *C.char {
-	indexStr := C.GoString(indexJSON)
+	raw := C.GoString(indexJSON)
 	tick := int(targetTick)
 
-	var index DemoIndex
-	if err := json.Unmarshal([]byte(indexStr), &index); err != nil {
+	index, err := loadDemoIndexSniffed(raw)
+	if err != nil {
 		result := map[string]interface{}{
 			"success": false,
 			"error":   fmt.Sprintf("Invalid index: %v", err),
@@ -608,39 +715,26 @@ func FindKeyframe(indexJSON *C.char, targetTick C.int) *C.char {
 		return C.CString(string(jsonResult))
 	}
 
-	if len(index.Keyframes) == 0 {
-		result := map[string]interface{}{
-			"success": false,
-			"error":   "No keyframes in index",
-		}
-		jsonResult, _ := json.Marshal(result)
-		return C.CString(string(jsonResult))
-	}
-
-	// Binary search for keyframe with tick <= target
-	keyframes := index.Keyframes
-	idx := sort.Search(len(keyframes), func(i int) bool {
-		return keyframes[i].Tick > tick
-	})
+	return marshalFindKeyframeResult(index, tick)
+}
 
-	if idx == 0 {
-		// Target is before first keyframe
-		result := map[string]interface{}{
-			"success":  true,
-			"keyframe": keyframes[0],
-			"exact":    keyframes[0].Tick == tick,
+// loadDemoIndexSniffed implements FindKeyframe's "accept either a path or
+// inline JSON" contract described above.
+func loadDemoIndexSniffed(raw string) (*DemoIndex, error) {
+	if data, readErr := os.ReadFile(raw); readErr == nil {
+		if len(data) >= binaryIndexHeaderSize && string(data[:4]) == binaryIndexMagic {
+			return decodeBinaryIndex(data)
 		}
-		jsonResult, _ := json.Marshal(result)
-		return C.CString(string(jsonResult))
+		var index DemoIndex
+		if err := json.Unmarshal(data, &index); err != nil {
+			return nil, err
+		}
+		return &index, nil
 	}
 
-	// Return keyframe just before or at target
-	kf := keyframes[idx-1]
-	result := map[string]interface{}{
-		"success":  true,
-		"keyframe": kf,
-		"exact":    kf.Tick == tick,
+	var index DemoIndex
+	if err := json.Unmarshal([]byte(raw), &index); err != nil {
+		return nil, err
 	}
-	jsonResult, _ := json.Marshal(result)
-	return C.CString(string(jsonResult))
+	return &index, nil
 }
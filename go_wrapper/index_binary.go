@@ -0,0 +1,322 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+// binaryIndexMagic identifies a BuildIndexBinary file. FindKeyframe sniffs
+// this to tell a binary index from an inline/on-disk JSON DemoIndex.
+const binaryIndexMagic = "MIDX"
+
+const binaryIndexVersion = 1
+
+const (
+	binaryIndexFlagHasCheckpoints = 1 << 0
+	binaryIndexFlagGzip           = 1 << 1
+)
+
+// binaryIndexHeader is BuildIndexBinary's fixed-size file header. The
+// request that asked for this format described a 16-byte header, but
+// uint32 total_ticks + uint32 game_started + uint32 keyframe_count alone
+// is already 12 bytes - adding the 4-byte magic and 2+2 bytes of
+// version/flags makes it 20, so this implementation is honest about the
+// real size rather than dropping a field to hit 16.
+type binaryIndexHeader struct {
+	Version       uint16
+	Flags         uint16
+	TotalTicks    uint32
+	GameStarted   uint32
+	KeyframeCount uint32
+}
+
+const binaryIndexHeaderSize = 4 + 2 + 2 + 4 + 4 + 4 // magic + header fields
+
+//export BuildIndexBinary
+func BuildIndexBinary(filePath *C.char, intervalTicks C.int, outPath *C.char) *C.char {
+	path := C.GoString(filePath)
+	out := C.GoString(outPath)
+	interval := int(intervalTicks)
+
+	if interval <= 0 {
+		interval = 1800
+	}
+
+	index := buildDemoIndex(path, interval)
+	if !index.Success {
+		return marshalBuildIndexBinaryResult(false, index.Error, out, 0)
+	}
+
+	n, err := writeBinaryIndex(index, nil, out)
+	if err != nil {
+		return marshalBuildIndexBinaryResult(false, err.Error(), out, 0)
+	}
+	return marshalBuildIndexBinaryResult(true, "", out, n)
+}
+
+// BuildIndexWithCheckpointsBinary is BuildIndexWithCheckpoints' binary-index
+// sibling: it writes the same checkpoint blobs to outDir, but the index
+// itself as a BuildIndexBinary file (with the checkpoint blob-path table
+// writeBinaryIndex encodes) instead of a .idx.json.
+//
+//export BuildIndexWithCheckpointsBinary
+func BuildIndexWithCheckpointsBinary(filePath *C.char, intervalTicks C.int, outDir *C.char, outPath *C.char) *C.char {
+	path := C.GoString(filePath)
+	dir := C.GoString(outDir)
+	out := C.GoString(outPath)
+	interval := int(intervalTicks)
+
+	if interval <= 0 {
+		interval = 1800
+	}
+
+	ckptIndex := buildIndexWithCheckpoints(path, interval, dir)
+	if !ckptIndex.Success {
+		return marshalBuildIndexBinaryResult(false, ckptIndex.Error, out, 0)
+	}
+
+	n, err := writeBinaryIndex(&ckptIndex.DemoIndex, ckptIndex.Checkpoints, out)
+	if err != nil {
+		return marshalBuildIndexBinaryResult(false, err.Error(), out, 0)
+	}
+	return marshalBuildIndexBinaryResult(true, "", out, n)
+}
+
+func marshalBuildIndexBinaryResult(success bool, errMsg, path string, bytesWritten int) *C.char {
+	result := map[string]interface{}{
+		"success":       success,
+		"path":          path,
+		"bytes_written": bytesWritten,
+	}
+	if errMsg != "" {
+		result["error"] = errMsg
+	}
+	data, _ := json.Marshal(result)
+	return C.CString(string(data))
+}
+
+// writeBinaryIndex encodes index (plus checkpoints, if this is being
+// called on a CheckpointIndex's embedded DemoIndex) to outPath in the
+// format binaryIndexHeader describes: the header, then gzip-compressed
+// (always - there's no zstd dependency vendored into this tree, so the
+// flags bit records gzip rather than the zstd the request asked for)
+// delta-encoded keyframes, then a trailing checkpoint blob-path table.
+func writeBinaryIndex(index *DemoIndex, checkpoints []CheckpointMeta, outPath string) (int, error) {
+	var body bytes.Buffer
+	varintBuf := make([]byte, binary.MaxVarintLen64)
+
+	writeUvarint := func(v uint64) {
+		n := binary.PutUvarint(varintBuf, v)
+		body.Write(varintBuf[:n])
+	}
+	writeFloat32 := func(f float32) {
+		var b [4]byte
+		binary.LittleEndian.PutUint32(b[:], math.Float32bits(f))
+		body.Write(b[:])
+	}
+
+	var prevTick, prevNetTick int
+	for _, kf := range index.Keyframes {
+		writeUvarint(uint64(kf.Tick - prevTick))
+		writeUvarint(uint64(kf.NetTick - prevNetTick))
+		writeFloat32(kf.GameTime)
+		flag := byte(0)
+		if kf.FullPacket {
+			flag = 1
+		}
+		body.WriteByte(flag)
+		prevTick, prevNetTick = kf.Tick, kf.NetTick
+	}
+
+	hasCheckpoints := len(checkpoints) > 0
+	if hasCheckpoints {
+		writeUvarint(uint64(len(checkpoints)))
+		for _, cp := range checkpoints {
+			writeUvarint(uint64(len(cp.BlobPath)))
+			body.WriteString(cp.BlobPath)
+		}
+	}
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	if _, err := gz.Write(body.Bytes()); err != nil {
+		return 0, fmt.Errorf("error compressing index body: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return 0, fmt.Errorf("error compressing index body: %w", err)
+	}
+
+	flags := uint16(binaryIndexFlagGzip)
+	if hasCheckpoints {
+		flags |= binaryIndexFlagHasCheckpoints
+	}
+
+	var out bytes.Buffer
+	out.WriteString(binaryIndexMagic)
+	binary.Write(&out, binary.LittleEndian, uint16(binaryIndexVersion))
+	binary.Write(&out, binary.LittleEndian, flags)
+	binary.Write(&out, binary.LittleEndian, uint32(index.TotalTicks))
+	binary.Write(&out, binary.LittleEndian, uint32(index.GameStarted))
+	binary.Write(&out, binary.LittleEndian, uint32(len(index.Keyframes)))
+	out.Write(compressed.Bytes())
+
+	if err := os.WriteFile(outPath, out.Bytes(), 0o644); err != nil {
+		return 0, fmt.Errorf("error writing index file: %w", err)
+	}
+	return out.Len(), nil
+}
+
+//export FindKeyframeBinary
+func FindKeyframeBinary(indexPath *C.char, targetTick C.int) *C.char {
+	path := C.GoString(indexPath)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return marshalFindKeyframeError(fmt.Sprintf("Failed to read index file: %v", err))
+	}
+
+	index, err := decodeBinaryIndex(data)
+	if err != nil {
+		return marshalFindKeyframeError(err.Error())
+	}
+	return marshalFindKeyframeResult(index, int(targetTick))
+}
+
+// decodeBinaryIndex parses a BuildIndexBinary file back into a DemoIndex,
+// reversing writeBinaryIndex's delta/varint encoding.
+func decodeBinaryIndex(data []byte) (*DemoIndex, error) {
+	if len(data) < binaryIndexHeaderSize || string(data[:4]) != binaryIndexMagic {
+		return nil, fmt.Errorf("not a binary index file (bad magic)")
+	}
+
+	var header binaryIndexHeader
+	r := bytes.NewReader(data[4:binaryIndexHeaderSize])
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("error reading index header: %w", err)
+	}
+	if header.Version != binaryIndexVersion {
+		return nil, fmt.Errorf("unsupported binary index version %d", header.Version)
+	}
+
+	body := data[binaryIndexHeaderSize:]
+	if header.Flags&binaryIndexFlagGzip != 0 {
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("error decompressing index body: %w", err)
+		}
+		defer gz.Close()
+		decompressed, err := io.ReadAll(gz)
+		if err != nil {
+			return nil, fmt.Errorf("error decompressing index body: %w", err)
+		}
+		body = decompressed
+	}
+
+	index := &DemoIndex{
+		Keyframes:   make([]Keyframe, 0, header.KeyframeCount),
+		TotalTicks:  int(header.TotalTicks),
+		GameStarted: int(header.GameStarted),
+		Success:     true,
+	}
+
+	buf := bytes.NewReader(body)
+	var prevTick, prevNetTick int
+	for i := uint32(0); i < header.KeyframeCount; i++ {
+		dTick, err := binary.ReadUvarint(buf)
+		if err != nil {
+			return nil, fmt.Errorf("error reading keyframe %d: %w", i, err)
+		}
+		dNetTick, err := binary.ReadUvarint(buf)
+		if err != nil {
+			return nil, fmt.Errorf("error reading keyframe %d: %w", i, err)
+		}
+		var gtBytes [4]byte
+		if _, err := io.ReadFull(buf, gtBytes[:]); err != nil {
+			return nil, fmt.Errorf("error reading keyframe %d: %w", i, err)
+		}
+		flag, err := buf.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("error reading keyframe %d: %w", i, err)
+		}
+
+		tick := prevTick + int(dTick)
+		netTick := prevNetTick + int(dNetTick)
+		index.Keyframes = append(index.Keyframes, Keyframe{
+			Tick:       tick,
+			NetTick:    netTick,
+			GameTime:   math.Float32frombits(binary.LittleEndian.Uint32(gtBytes[:])),
+			FullPacket: flag == 1,
+		})
+		prevTick, prevNetTick = tick, netTick
+	}
+
+	// Checkpoint blob-path table, if present, is read back but not
+	// currently surfaced on DemoIndex - callers needing checkpoint resume
+	// still go through BuildIndexWithCheckpoints' JSON CheckpointIndex.
+	if header.Flags&binaryIndexFlagHasCheckpoints != 0 {
+		count, err := binary.ReadUvarint(buf)
+		if err == nil {
+			for i := uint64(0); i < count; i++ {
+				n, err := binary.ReadUvarint(buf)
+				if err != nil {
+					break
+				}
+				skip := make([]byte, n)
+				if _, err := io.ReadFull(buf, skip); err != nil {
+					break
+				}
+			}
+		}
+	}
+
+	return index, nil
+}
+
+func marshalFindKeyframeError(msg string) *C.char {
+	data, _ := json.Marshal(map[string]interface{}{"success": false, "error": msg})
+	return C.CString(string(data))
+}
+
+// marshalFindKeyframeResult runs FindKeyframe's binary-search-over-Keyframes
+// logic against an already-decoded index, shared between FindKeyframe
+// (after it sniffs and decodes a binary index) and FindKeyframeBinary.
+func marshalFindKeyframeResult(index *DemoIndex, tick int) *C.char {
+	if len(index.Keyframes) == 0 {
+		data, _ := json.Marshal(map[string]interface{}{"success": false, "error": "No keyframes in index"})
+		return C.CString(string(data))
+	}
+
+	// Same binary search FindKeyframe runs over a JSON-decoded DemoIndex.
+	keyframes := index.Keyframes
+	idx := sort.Search(len(keyframes), func(i int) bool {
+		return keyframes[i].Tick > tick
+	})
+
+	if idx == 0 {
+		data, _ := json.Marshal(map[string]interface{}{
+			"success":  true,
+			"keyframe": keyframes[0],
+			"exact":    keyframes[0].Tick == tick,
+		})
+		return C.CString(string(data))
+	}
+
+	kf := keyframes[idx-1]
+	data, _ := json.Marshal(map[string]interface{}{
+		"success":  true,
+		"keyframe": kf,
+		"exact":    kf.Tick == tick,
+	})
+	return C.CString(string(data))
+}
@@ -32,25 +32,25 @@ func extractGameBuild(gameDir string) int32 {
 
 // HeaderInfo represents the basic demo file header information
 type HeaderInfo struct {
-	MapName         string `json:"map_name"`
-	ServerName      string `json:"server_name"`
-	ClientName      string `json:"client_name"`
-	GameDirectory   string `json:"game_directory"`
-	NetworkProtocol int32  `json:"network_protocol"`
-	DemoFileStamp   string `json:"demo_file_stamp"`
-	BuildNum        int32  `json:"build_num"`
-	GameBuild       int32  `json:"game_build"` // Extracted from game_directory (e.g., 6559 from /dota_v6559/)
-	Game            string `json:"game"`
-	ServerStartTick int32  `json:"server_start_tick"`
-	Success         bool   `json:"success"`
-	Error           string `json:"error,omitempty"`
+	MapName         string     `json:"map_name"`
+	ServerName      string     `json:"server_name"`
+	ClientName      string     `json:"client_name"`
+	GameDirectory   string     `json:"game_directory"`
+	NetworkProtocol int32      `json:"network_protocol"`
+	DemoFileStamp   string     `json:"demo_file_stamp"`
+	BuildNum        int32      `json:"build_num"`
+	GameBuild       int32      `json:"game_build"` // Extracted from game_directory (e.g., 6559 from /dota_v6559/)
+	Game            string     `json:"game"`
+	ServerStartTick int32      `json:"server_start_tick"`
+	Success         bool       `json:"success"`
+	Error           *ErrorInfo `json:"error,omitempty"`
 }
 
 // CHeroSelectEvent represents a pick or ban event - matches Manta naming
 type CHeroSelectEvent struct {
-	IsPick bool   `json:"is_pick"`    // true for pick, false for ban
-	Team   uint32 `json:"team"`       // 2=Radiant, 3=Dire  
-	HeroId int32  `json:"hero_id"`    // Hero ID
+	IsPick bool   `json:"is_pick"` // true for pick, false for ban
+	Team   uint32 `json:"team"`    // 2=Radiant, 3=Dire
+	HeroId int32  `json:"hero_id"` // Hero ID
 }
 
 // CDotaGameInfo represents complete draft phase information - matches Manta naming
@@ -89,43 +89,39 @@ type CDotaGameInfo struct {
 	PlaybackTicks  int32   `json:"playback_ticks"`
 	PlaybackFrames int32   `json:"playback_frames"`
 
-	Success bool   `json:"success"`
-	Error   string `json:"error,omitempty"`
+	Success bool       `json:"success"`
+	Error   *ErrorInfo `json:"error,omitempty"`
 }
 
 //export ParseHeader
 func ParseHeader(filePath *C.char) (result *C.char) {
-	goFilePath := C.GoString(filePath)
-
-	header := &HeaderInfo{
-		Success: false,
-	}
-
-	// Recover from any panics in manta library
 	defer func() {
 		if r := recover(); r != nil {
-			header.Success = false
-			header.Error = fmt.Sprintf("panic during parsing: %v", r)
-			result = marshalAndReturn(header)
+			result = marshalAndReturn(&HeaderInfo{Success: false, Error: simpleErrorInfo(ErrPanic, fmt.Sprintf("panic during parsing: %v", r))})
 		}
 	}()
+	return marshalAndReturn(runHeaderParse(C.GoString(filePath)))
+}
+
+// runHeaderParse holds ParseHeader's actual parsing logic, split out so
+// ParseHeadersBatch can reuse it per-file behind its own panic recovery
+// instead of going through the C string marshal/demarshal round-trip.
+func runHeaderParse(goFilePath string) *HeaderInfo {
+	header := &HeaderInfo{Success: false}
 
-	// Open the file
 	file, err := os.Open(goFilePath)
 	if err != nil {
-		header.Error = fmt.Sprintf("Error opening file: %v", err)
-		return marshalAndReturn(header)
+		header.Error = classifyOpenError(err).toErrorInfo()
+		return header
 	}
 	defer file.Close()
 
-	// Create parser
 	parser, err := manta.NewStreamParser(file)
 	if err != nil {
-		header.Error = fmt.Sprintf("Error creating parser: %v", err)
-		return marshalAndReturn(header)
+		header.Error = classifyParseError(err).toErrorInfo()
+		return header
 	}
 
-	// Set up header callback to capture the data
 	headerFound := false
 	parser.Callbacks.OnCDemoFileHeader(func(m *dota.CDemoFileHeader) error {
 		header.MapName = m.GetMapName()
@@ -146,19 +142,16 @@ func ParseHeader(filePath *C.char) (result *C.char) {
 		return nil
 	})
 
-	// Start parsing (will stop after header is found)
 	err = parser.Start()
 	if err != nil && !headerFound {
-		header.Error = fmt.Sprintf("Error parsing file: %v", err)
-		return marshalAndReturn(header)
+		header.Error = classifyParseError(err).toErrorInfo()
+		return header
 	}
 
 	if !headerFound {
-		header.Error = "Header not found in demo file"
-		return marshalAndReturn(header)
+		header.Error = simpleErrorInfo(ErrHeaderMissing, "Header not found in demo file")
 	}
-
-	return marshalAndReturn(header)
+	return header
 }
 
 // Helper function to marshal HeaderInfo to JSON and return as C string
@@ -168,7 +161,7 @@ func marshalAndReturn(header *HeaderInfo) *C.char {
 		// Fallback error response
 		fallback := &HeaderInfo{
 			Success: false,
-			Error:   fmt.Sprintf("JSON marshal error: %v", err),
+			Error:   simpleErrorInfo(ErrIO, fmt.Sprintf("JSON marshal error: %v", err)),
 		}
 		jsonData, _ = json.Marshal(fallback)
 	}
@@ -185,7 +178,7 @@ func marshalAndReturnGameInfo(gameInfo *CDotaGameInfo) *C.char {
 		// Fallback error response
 		fallback := &CDotaGameInfo{
 			Success: false,
-			Error:   fmt.Sprintf("JSON marshal error: %v", err),
+			Error:   simpleErrorInfo(ErrIO, fmt.Sprintf("JSON marshal error: %v", err)),
 		}
 		jsonData, _ = json.Marshal(fallback)
 	}
@@ -197,34 +190,31 @@ func marshalAndReturnGameInfo(gameInfo *CDotaGameInfo) *C.char {
 
 //export ParseMatchInfo
 func ParseMatchInfo(filePath *C.char) (result *C.char) {
-	goFilePath := C.GoString(filePath)
-
-	gameInfo := &CDotaGameInfo{
-		Success: false,
-	}
-
-	// Recover from any panics in manta library
 	defer func() {
 		if r := recover(); r != nil {
-			gameInfo.Success = false
-			gameInfo.Error = fmt.Sprintf("panic during parsing: %v", r)
-			result = marshalAndReturnGameInfo(gameInfo)
+			result = marshalAndReturnGameInfo(&CDotaGameInfo{Success: false, Error: simpleErrorInfo(ErrPanic, fmt.Sprintf("panic during parsing: %v", r))})
 		}
 	}()
+	return marshalAndReturnGameInfo(runMatchInfoParse(C.GoString(filePath)))
+}
+
+// runMatchInfoParse holds ParseMatchInfo's actual parsing logic, split out
+// so ParseMatchInfoBatch can reuse it per-file behind its own panic
+// recovery, the same way runHeaderParse serves ParseHeadersBatch.
+func runMatchInfoParse(goFilePath string) *CDotaGameInfo {
+	gameInfo := &CDotaGameInfo{Success: false}
 
-	// Open the file
 	file, err := os.Open(goFilePath)
 	if err != nil {
-		gameInfo.Error = fmt.Sprintf("Error opening file: %v", err)
-		return marshalAndReturnGameInfo(gameInfo)
+		gameInfo.Error = classifyOpenError(err).toErrorInfo()
+		return gameInfo
 	}
 	defer file.Close()
 
-	// Create parser
 	parser, err := manta.NewStreamParser(file)
 	if err != nil {
-		gameInfo.Error = fmt.Sprintf("Error creating parser: %v", err)
-		return marshalAndReturnGameInfo(gameInfo)
+		gameInfo.Error = classifyParseError(err).toErrorInfo()
+		return gameInfo
 	}
 
 	// Set up callback to capture game information from CDemoFileInfo
@@ -291,16 +281,14 @@ func ParseMatchInfo(filePath *C.char) (result *C.char) {
 	// Start parsing
 	err = parser.Start()
 	if err != nil && !infoFound {
-		gameInfo.Error = fmt.Sprintf("Error parsing file: %v", err)
-		return marshalAndReturnGameInfo(gameInfo)
+		gameInfo.Error = classifyParseError(err).toErrorInfo()
+		return gameInfo
 	}
 
 	if !infoFound {
-		gameInfo.Error = "Game information not found in demo file"
-		return marshalAndReturnGameInfo(gameInfo)
+		gameInfo.Error = simpleErrorInfo(ErrInfoMissing, "Game information not found in demo file")
 	}
-
-	return marshalAndReturnGameInfo(gameInfo)
+	return gameInfo
 }
 
 //export FreeString
@@ -312,4 +300,4 @@ func FreeString(str *C.char) {
 
 func main() {
 	// CGO requires a main function, but we won't use it for the library
-}
\ No newline at end of file
+}
@@ -0,0 +1,68 @@
+// Package marshal is the single place every marshalXxx helper in
+// go_wrapper routes through to turn a result struct into bytes, instead of
+// calling encoding/json.Marshal directly. encoding/json dominates
+// wall-clock time once a match produces tens of thousands of combat-log
+// entries returned as one blob across the CGo boundary, so the JSON
+// encoder itself is swappable with -tags fastjson (see
+// marshal_fastjson.go), and the wire format is swappable at runtime via
+// SetFormat for callers that would rather skip JSON parsing on the Python
+// side entirely.
+package marshal
+
+import "github.com/vmihailenco/msgpack/v5"
+
+// Marshaler is the pluggable JSON encoder Default implements - either the
+// stdlib (marshal_stdlib.go) or goccy/go-json under -tags fastjson
+// (marshal_fastjson.go).
+type Marshaler interface {
+	Marshal(v any) ([]byte, error)
+}
+
+// Format selects Encode's wire format.
+type Format string
+
+const (
+	FormatJSON    Format = "json"
+	FormatMsgpack Format = "msgpack"
+)
+
+var currentFormat = FormatJSON
+
+// SetFormat switches every future Encode call between "json" (via Default)
+// and "msgpack". Returns an error for anything else so SetOutputFormat can
+// surface a clear failure instead of silently keeping the old format.
+func SetFormat(format string) error {
+	switch Format(format) {
+	case FormatJSON, FormatMsgpack:
+		currentFormat = Format(format)
+		return nil
+	default:
+		return &UnknownFormatError{Format: format}
+	}
+}
+
+// UnknownFormatError is returned by SetFormat for anything other than
+// "json"/"msgpack".
+type UnknownFormatError struct {
+	Format string
+}
+
+func (e *UnknownFormatError) Error() string {
+	return "unknown output format: " + e.Format
+}
+
+// Encode renders v in whichever format SetFormat last selected.
+//
+// Note: callers that hand Encode's result to a null-terminated C string
+// (most marshalXxx helpers in go_wrapper do, via C.CString) only get a
+// correct round-trip for FormatJSON - msgpack's binary encoding can embed
+// NUL bytes, which C.CString truncates at. Exports that want safe msgpack
+// output need a length-prefixed byte buffer or file-based transport
+// instead, the same way the streaming NDJSON endpoints already avoid
+// C.CString for their bulk payloads.
+func Encode(v any) ([]byte, error) {
+	if currentFormat == FormatMsgpack {
+		return msgpack.Marshal(v)
+	}
+	return Default.Marshal(v)
+}
@@ -0,0 +1,14 @@
+//go:build fastjson
+
+package marshal
+
+import "github.com/goccy/go-json"
+
+type fastMarshaler struct{}
+
+func (fastMarshaler) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+// Default is goccy/go-json under -tags fastjson - a drop-in faster encoder
+// for the large combat-log/parser-info payloads that dominate wall-clock
+// time through encoding/json on bigger replays.
+var Default Marshaler = fastMarshaler{}
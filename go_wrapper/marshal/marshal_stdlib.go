@@ -0,0 +1,12 @@
+//go:build !fastjson
+
+package marshal
+
+import "encoding/json"
+
+type stdlibMarshaler struct{}
+
+func (stdlibMarshaler) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+// Default is encoding/json unless built with -tags fastjson.
+var Default Marshaler = stdlibMarshaler{}
@@ -0,0 +1,147 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// MatchInfoPlayer is one player's slot in MatchInfo.Players.
+type MatchInfoPlayer struct {
+	SteamID    uint64 `json:"steamid"`
+	PlayerName string `json:"player_name"`
+	HeroID     int32  `json:"hero_id"`
+	HeroName   string `json:"hero_name"`
+	Team       int32  `json:"team"`
+	IsPro      bool   `json:"is_pro"`
+}
+
+// MatchInfo is GetMatchInfo's response envelope - a one-call replay summary
+// that, unlike ParseMatchInfo's CDotaGameInfo, carries GameBuild read from
+// CDemoFileHeader rather than ServerInfo's network protocol, and Duration
+// alongside the per-player entries.
+type MatchInfo struct {
+	MatchID    uint64             `json:"match_id"`
+	GameMode   int32              `json:"game_mode"`
+	GameWinner int32              `json:"game_winner"`
+	EndTime    uint32             `json:"end_time"`
+	Duration   float32            `json:"duration"`
+	GameBuild  int32              `json:"game_build"`
+	Players    []MatchInfoPlayer  `json:"players"`
+	PicksBans  []CHeroSelectEvent `json:"picks_bans"`
+	Success    bool               `json:"success"`
+	Error      *ErrorInfo         `json:"error,omitempty"`
+}
+
+// GetMatchInfo returns a one-call replay summary driven by CDemoFileInfo,
+// without the caller needing to walk the replay tick-by-tick itself.
+//
+//export GetMatchInfo
+func GetMatchInfo(filePath *C.char) (cResult *C.char) {
+	goFilePath := C.GoString(filePath)
+
+	defer func() {
+		if r := recover(); r != nil {
+			cResult = marshalMatchInfo(&MatchInfo{Success: false, Error: simpleErrorInfo(ErrPanic, fmt.Sprintf("panic during parsing: %v", r))})
+		}
+	}()
+
+	return marshalMatchInfo(RunGetMatchInfo(goFilePath))
+}
+
+// RunGetMatchInfo extracts MatchInfo from filePath.
+func RunGetMatchInfo(filePath string) *MatchInfo {
+	info := &MatchInfo{Success: false}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		info.Error = classifyOpenError(err).toErrorInfo()
+		return info
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		info.Error = classifyParseError(err).toErrorInfo()
+		return info
+	}
+
+	// GameBuild comes from the header's real build number, not
+	// ServerInfo.Protocol - the misuse GetParserInfo's GameBuild field has
+	// carried since it was first added. The header is read before
+	// CDemoFileInfo arrives, so it's always captured when present.
+	parser.Callbacks.OnCDemoFileHeader(func(m *dota.CDemoFileHeader) error {
+		info.GameBuild = m.GetBuildNum()
+		return nil
+	})
+
+	infoFound := false
+	parser.Callbacks.OnCDemoFileInfo(func(m *dota.CDemoFileInfo) error {
+		// Duration approximates PlaybackTime, the same playback-length field
+		// ParseMatchInfo's CDotaGameInfo.PlaybackTime already exposes - the
+		// demo's container format has no separate "match clock" duration.
+		info.Duration = m.GetPlaybackTime()
+
+		dotaInfo := m.GetGameInfo().GetDota()
+		if dotaInfo == nil {
+			return nil
+		}
+
+		info.MatchID = dotaInfo.GetMatchId()
+		info.GameMode = dotaInfo.GetGameMode()
+		info.GameWinner = dotaInfo.GetGameWinner()
+		info.EndTime = dotaInfo.GetEndTime()
+
+		isPro := dotaInfo.GetRadiantTeamId() != 0 || dotaInfo.GetDireTeamId() != 0
+
+		for _, p := range dotaInfo.GetPlayerInfo() {
+			info.Players = append(info.Players, MatchInfoPlayer{
+				SteamID:    p.GetSteamid(),
+				PlayerName: p.GetPlayerName(),
+				HeroID:     p.GetHeroId(),
+				HeroName:   p.GetHeroName(),
+				Team:       int32(p.GetGameTeam()),
+				IsPro:      isPro,
+			})
+		}
+
+		for _, pb := range dotaInfo.GetPicksBans() {
+			info.PicksBans = append(info.PicksBans, CHeroSelectEvent{
+				IsPick: pb.GetIsPick(),
+				Team:   pb.GetTeam(),
+				HeroId: pb.GetHeroId(),
+			})
+		}
+
+		info.Success = true
+		infoFound = true
+		parser.Stop()
+		return nil
+	})
+
+	if err := parser.Start(); err != nil && !infoFound {
+		info.Error = classifyParseError(err).toErrorInfo()
+		return info
+	}
+
+	if !infoFound {
+		info.Error = simpleErrorInfo(ErrInfoMissing, "game information not found in demo file")
+	}
+	return info
+}
+
+func marshalMatchInfo(r *MatchInfo) *C.char {
+	data, err := json.Marshal(r)
+	if err != nil {
+		data, _ = json.Marshal(&MatchInfo{Success: false, Error: simpleErrorInfo(ErrIO, err.Error())})
+	}
+	return C.CString(string(data))
+}
@@ -0,0 +1,94 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// MatchMetadataResult is the ParseMatchMetadata response envelope. Metadata
+// is the raw *dota.CDOTAMatchMetadataFile protobuf - like the Data field
+// addFilteredMessage/MessageEvent carry for the universal message walk, we
+// let it marshal directly rather than hand-copying its ~dozen nested
+// message types (teams, players, item purchases, ability learns, wards,
+// runes, event game times, ...) into a parallel struct; protoc-gen-go
+// already tags every field with its snake_case proto name for
+// encoding/json, which is exactly the promotion this endpoint wants.
+type MatchMetadataResult struct {
+	Metadata *dota.CDOTAMatchMetadataFile `json:"metadata"`
+	Success  bool                         `json:"success"`
+	Error    string                       `json:"error,omitempty"`
+}
+
+// ParseMatchMetadata extracts the CDOTAMatchMetadataFile blob some replays
+// carry (tournament/GC-sourced post-match data: item timings, ability
+// builds, per-target damage, ward placements, rune pickups, and permanent
+// buffs) via the same OnCDOTAMatchMetadataFile callback the universal
+// message walk already registers. Many demos don't carry this block at
+// all, in which case Metadata stays nil and Success is still true - its
+// absence isn't a parse error.
+//
+//export ParseMatchMetadata
+func ParseMatchMetadata(filePath *C.char) (cResult *C.char) {
+	goFilePath := C.GoString(filePath)
+
+	result := &MatchMetadataResult{}
+
+	defer func() {
+		if r := recover(); r != nil {
+			result.Success = false
+			result.Error = fmt.Sprintf("panic during parsing: %v", r)
+			cResult = marshalMatchMetadataResult(result)
+		}
+	}()
+
+	res, err := RunMatchMetadataParse(goFilePath)
+	if err != nil {
+		result.Error = err.Error()
+		return marshalMatchMetadataResult(result)
+	}
+	return marshalMatchMetadataResult(res)
+}
+
+// RunMatchMetadataParse executes the match metadata extraction.
+func RunMatchMetadataParse(filePath string) (*MatchMetadataResult, error) {
+	result := &MatchMetadataResult{}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		return nil, fmt.Errorf("error creating parser: %w", err)
+	}
+
+	parser.Callbacks.OnCDOTAMatchMetadataFile(func(m *dota.CDOTAMatchMetadataFile) error {
+		result.Metadata = m
+		return nil
+	})
+
+	if err := parser.Start(); err != nil {
+		return nil, fmt.Errorf("error parsing file: %w", err)
+	}
+
+	result.Success = true
+	return result, nil
+}
+
+func marshalMatchMetadataResult(r *MatchMetadataResult) *C.char {
+	data, err := json.Marshal(r)
+	if err != nil {
+		data, _ = json.Marshal(&MatchMetadataResult{Success: false, Error: err.Error()})
+	}
+	return C.CString(string(data))
+}
@@ -0,0 +1,358 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// MatchMetadataConfig controls GetMatchMetadata.
+type MatchMetadataConfig struct {
+	// QuickMode skips entity streaming entirely and returns only what
+	// CDemoFileInfo itself carries (match result, draft, player identities)
+	// - no live per-player stats or inventory, but since CDemoFileInfo sits
+	// near the end of the file and GetMatchInfo already stops the parser
+	// the moment it's seen, this is the fast path.
+	QuickMode bool `json:"quick_mode"`
+}
+
+// AbilityLevelEntry is one entry in MatchMetadataPlayer.AbilitiesLeveled.
+type AbilityLevelEntry struct {
+	AbilityName string `json:"ability_name"`
+	Level       int    `json:"level"`
+}
+
+// MatchMetadataPlayer is one player's final state: identity from
+// CDemoFileInfo plus live stats/inventory read off CDOTA_PlayerResource,
+// CDOTA_Data_Radiant/Dire, and the player's hero entity the same way
+// extractEconomyData/extractFullHeroSnapshot read them for entity
+// snapshots - but captured once, at the end of a full replay, rather than
+// at caller-chosen ticks.
+type MatchMetadataPlayer struct {
+	SteamID          uint64              `json:"steam_id"`
+	Name             string              `json:"name"`
+	Team             int32               `json:"team"`
+	Hero             string              `json:"hero"`
+	HeroID           int32               `json:"hero_id"`
+	Level            int                 `json:"level"`
+	Kills            int                 `json:"kills"`
+	Deaths           int                 `json:"deaths"`
+	Assists          int                 `json:"assists"`
+	LastHits         int                 `json:"last_hits"`
+	Denies           int                 `json:"denies"`
+	Gold             int                 `json:"gold"`
+	XP               int                 `json:"xp"`
+	NetWorth         int                 `json:"net_worth"`
+	Items            [9]string           `json:"items"`
+	Backpack         [3]string           `json:"backpack"`
+	NeutralItem      string              `json:"neutral_item"`
+	AbilitiesLeveled []AbilityLevelEntry `json:"abilities_leveled"`
+}
+
+// MatchMetadata is the GetMatchMetadata response envelope.
+type MatchMetadata struct {
+	MatchID   uint64                `json:"match_id"`
+	GameMode  int32                 `json:"game_mode"`
+	LobbyType int32                 `json:"lobby_type"`
+	EndTime   uint32                `json:"end_time"`
+	Duration  float32               `json:"duration"`
+	Winner    int32                 `json:"winner"`
+	Players   []MatchMetadataPlayer `json:"players"`
+	// Draft reuses CHeroSelectEvent rather than adding per-pick
+	// timestamps - CDemoFileInfo's CDotaGameInfo.picks_bans doesn't carry
+	// a tick/game-time per entry, only pick order, so that's all this can
+	// honestly report.
+	Draft   []CHeroSelectEvent `json:"draft"`
+	Success bool               `json:"success"`
+	Error   string             `json:"error,omitempty"`
+}
+
+//export GetMatchMetadata
+func GetMatchMetadata(filePath *C.char, configJSON *C.char) (cResult *C.char) {
+	goFilePath := C.GoString(filePath)
+	goConfigJSON := C.GoString(configJSON)
+
+	defer func() {
+		if r := recover(); r != nil {
+			cResult = marshalMatchMetadata(&MatchMetadata{Success: false, Error: fmt.Sprintf("panic during parsing: %v", r)})
+		}
+	}()
+
+	config := MatchMetadataConfig{}
+	if goConfigJSON != "" {
+		if err := json.Unmarshal([]byte(goConfigJSON), &config); err != nil {
+			return marshalMatchMetadata(&MatchMetadata{Success: false, Error: fmt.Sprintf("invalid config JSON: %v", err)})
+		}
+	}
+
+	result, err := RunGetMatchMetadata(goFilePath, config)
+	if err != nil {
+		return marshalMatchMetadata(&MatchMetadata{Success: false, Error: err.Error()})
+	}
+	return marshalMatchMetadata(result)
+}
+
+// RunGetMatchMetadata extracts MatchMetadata from filePath.
+func RunGetMatchMetadata(filePath string, config MatchMetadataConfig) (*MatchMetadata, error) {
+	result := &MatchMetadata{}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		return nil, fmt.Errorf("error creating parser: %w", err)
+	}
+
+	var playerResource, dataRadiant, dataDire *manta.Entity
+	heroEntityByHandle := make(map[uint64]*manta.Entity)
+	itemNameByHandle := make(map[uint64]string)
+	abilityNameByHandle := make(map[uint64]string)
+
+	if !config.QuickMode {
+		parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+			if e == nil {
+				return nil
+			}
+			className := e.GetClassName()
+
+			switch {
+			case strings.Contains(className, "CDOTA_PlayerResource"):
+				playerResource = e
+			case strings.Contains(className, "CDOTA_DataRadiant"):
+				dataRadiant = e
+			case strings.Contains(className, "CDOTA_DataDire"):
+				dataDire = e
+			case strings.Contains(className, "CDOTA_Unit_Hero_"):
+				heroEntityByHandle[uint64(e.GetIndex())&0x3FFF] = e
+			case strings.HasPrefix(className, "CDOTA_Item_"):
+				if name := entityClassToItemName(className); name != "" {
+					itemNameByHandle[uint64(e.GetIndex())&0x3FFF] = name
+				}
+			case strings.HasPrefix(className, "CDOTA_Ability_"):
+				if name := entityClassToAbilityName(className); name != "" {
+					abilityNameByHandle[uint64(e.GetIndex())&0x3FFF] = name
+				}
+			}
+			return nil
+		})
+	}
+
+	byHeroID := make(map[int32]*MatchMetadataPlayer)
+
+	infoFound := false
+	parser.Callbacks.OnCDemoFileInfo(func(m *dota.CDemoFileInfo) error {
+		result.Duration = m.GetPlaybackTime()
+
+		dotaInfo := m.GetGameInfo().GetDota()
+		if dotaInfo == nil {
+			return nil
+		}
+
+		result.MatchID = dotaInfo.GetMatchId()
+		result.GameMode = dotaInfo.GetGameMode()
+		result.LobbyType = dotaInfo.GetLobbyType()
+		result.Winner = dotaInfo.GetGameWinner()
+		result.EndTime = dotaInfo.GetEndTime()
+
+		for _, p := range dotaInfo.GetPlayerInfo() {
+			result.Players = append(result.Players, MatchMetadataPlayer{
+				SteamID: p.GetSteamid(),
+				Name:    p.GetPlayerName(),
+				Team:    int32(p.GetGameTeam()),
+				Hero:    p.GetHeroName(),
+				HeroID:  p.GetHeroId(),
+			})
+			player := &result.Players[len(result.Players)-1]
+			byHeroID[player.HeroID] = player
+		}
+
+		for _, pb := range dotaInfo.GetPicksBans() {
+			result.Draft = append(result.Draft, CHeroSelectEvent{
+				IsPick: pb.GetIsPick(),
+				Team:   pb.GetTeam(),
+				HeroId: pb.GetHeroId(),
+			})
+		}
+
+		infoFound = true
+		if config.QuickMode {
+			parser.Stop()
+			return nil
+		}
+
+		fillMatchMetadataStats(byHeroID, playerResource, dataRadiant, dataDire, heroEntityByHandle, itemNameByHandle, abilityNameByHandle)
+		return nil
+	})
+
+	if err := parser.Start(); err != nil && !infoFound {
+		return nil, fmt.Errorf("error parsing file: %w", err)
+	}
+
+	if !infoFound {
+		return nil, fmt.Errorf("game information not found in demo file")
+	}
+
+	result.Success = true
+	return result, nil
+}
+
+// fillMatchMetadataStats reads each player slot's live stats and inventory
+// off the entities watchMatchMetadataEntities has been tracking, the same
+// per-slot walk chatPlayerTracker/combatLogPlayerTracker use to read
+// CDOTA_PlayerResource.m_vecPlayerTeamData, extended with the
+// CDOTA_Data_Radiant/Dire fields extractEconomyData reads and the hero
+// entity's own item/ability handles.
+func fillMatchMetadataStats(
+	byHeroID map[int32]*MatchMetadataPlayer,
+	playerResource, dataRadiant, dataDire *manta.Entity,
+	heroEntityByHandle map[uint64]*manta.Entity,
+	itemNameByHandle, abilityNameByHandle map[uint64]string,
+) {
+	if playerResource == nil {
+		return
+	}
+
+	for slot := 0; slot < 10; slot++ {
+		heroID, ok := playerResource.GetUint32(fmt.Sprintf("m_vecPlayerTeamData.%04d.m_nSelectedHeroID", slot))
+		if !ok {
+			continue
+		}
+		player, ok := byHeroID[int32(heroID)]
+		if !ok {
+			continue
+		}
+
+		if level, ok := playerResource.GetInt32(fmt.Sprintf("m_vecPlayerTeamData.%04d.m_iLevel", slot)); ok {
+			player.Level = int(level)
+		}
+		if kills, ok := playerResource.GetInt32(fmt.Sprintf("m_vecPlayerTeamData.%04d.m_iKills", slot)); ok {
+			player.Kills = int(kills)
+		}
+		if deaths, ok := playerResource.GetInt32(fmt.Sprintf("m_vecPlayerTeamData.%04d.m_iDeaths", slot)); ok {
+			player.Deaths = int(deaths)
+		}
+		if assists, ok := playerResource.GetInt32(fmt.Sprintf("m_vecPlayerTeamData.%04d.m_iAssists", slot)); ok {
+			player.Assists = int(assists)
+		}
+
+		dataEntity := dataRadiant
+		if slot >= 5 {
+			dataEntity = dataDire
+		}
+		teamSlot := slot % 5
+		if dataEntity != nil {
+			if lh, ok := dataEntity.GetInt32(fmt.Sprintf("m_vecDataTeam.%04d.m_iLastHitCount", teamSlot)); ok {
+				player.LastHits = int(lh)
+			}
+			if denies, ok := dataEntity.GetInt32(fmt.Sprintf("m_vecDataTeam.%04d.m_iDenyCount", teamSlot)); ok {
+				player.Denies = int(denies)
+			}
+			if nw, ok := dataEntity.GetInt32(fmt.Sprintf("m_vecDataTeam.%04d.m_iNetWorth", teamSlot)); ok {
+				player.NetWorth = int(nw)
+			}
+			if gold, ok := dataEntity.GetInt32(fmt.Sprintf("m_vecDataTeam.%04d.m_iReliableGold", teamSlot)); ok {
+				player.Gold = int(gold)
+			}
+			if unreliable, ok := dataEntity.GetInt32(fmt.Sprintf("m_vecDataTeam.%04d.m_iUnreliableGold", teamSlot)); ok {
+				player.Gold += int(unreliable)
+			}
+			if xp, ok := dataEntity.GetInt32(fmt.Sprintf("m_vecDataTeam.%04d.m_iTotalEarnedXP", teamSlot)); ok {
+				player.XP = int(xp)
+			}
+		}
+
+		heroHandle, ok := playerResource.GetUint64(fmt.Sprintf("m_vecPlayerTeamData.%04d.m_hSelectedHero", slot))
+		if !ok {
+			continue
+		}
+		heroEntity, ok := heroEntityByHandle[heroHandle&0x3FFF]
+		if !ok {
+			continue
+		}
+		fillInventory(player, heroEntity, itemNameByHandle, abilityNameByHandle)
+	}
+}
+
+// fillInventory reads a hero entity's m_hItems/m_hAbilities handle arrays,
+// resolving each handle against the item/ability entities
+// watchMatchMetadataEntities already collected. Source 2's inventory
+// layout is 9 main slots, 3 backpack slots, then the neutral item slot;
+// a miss on any one slot (empty slot, or an ability/item class this
+// wrapper doesn't know how to name) is left as the zero value rather than
+// treated as an error.
+func fillInventory(player *MatchMetadataPlayer, hero *manta.Entity, itemNameByHandle, abilityNameByHandle map[uint64]string) {
+	for slot := 0; slot < 9; slot++ {
+		handle, ok := hero.GetUint64(fmt.Sprintf("m_hItems.%04d", slot))
+		if !ok {
+			continue
+		}
+		player.Items[slot] = itemNameByHandle[handle&0x3FFF]
+	}
+	for slot := 0; slot < 3; slot++ {
+		handle, ok := hero.GetUint64(fmt.Sprintf("m_hItems.%04d", slot+9))
+		if !ok {
+			continue
+		}
+		player.Backpack[slot] = itemNameByHandle[handle&0x3FFF]
+	}
+	if handle, ok := hero.GetUint64("m_hItems.0016"); ok {
+		player.NeutralItem = itemNameByHandle[handle&0x3FFF]
+	}
+
+	for slot := 0; slot < 6; slot++ {
+		handle, ok := hero.GetUint64(fmt.Sprintf("m_hAbilities.%04d", slot))
+		if !ok {
+			continue
+		}
+		name, ok := abilityNameByHandle[handle&0x3FFF]
+		if !ok {
+			continue
+		}
+		player.AbilitiesLeveled = append(player.AbilitiesLeveled, AbilityLevelEntry{AbilityName: name})
+	}
+}
+
+// entityClassToItemName converts an item entity's class name to its
+// item_* short name, the same camelToSnake conversion
+// entityClassToHeroName uses for heroes.
+// Example: "CDOTA_Item_BlinkDagger" -> "item_blink_dagger"
+func entityClassToItemName(className string) string {
+	if !strings.HasPrefix(className, "CDOTA_Item_") {
+		return ""
+	}
+	return "item_" + camelToSnake(strings.TrimPrefix(className, "CDOTA_Item_"))
+}
+
+// entityClassToAbilityName converts an ability entity's class name to its
+// short name. Most Dota abilities share a small set of generic ability
+// entity classes rather than one class per ability, so this only
+// resolves the minority that are individually classed - callers should
+// treat a miss here as "name unavailable", not an error.
+// Example: "CDOTA_Ability_BlinkDagger" -> "blink_dagger"
+func entityClassToAbilityName(className string) string {
+	if !strings.HasPrefix(className, "CDOTA_Ability_") {
+		return ""
+	}
+	return camelToSnake(strings.TrimPrefix(className, "CDOTA_Ability_"))
+}
+
+func marshalMatchMetadata(r *MatchMetadata) *C.char {
+	data, err := json.Marshal(r)
+	if err != nil {
+		data, _ = json.Marshal(&MatchMetadata{Success: false, Error: err.Error()})
+	}
+	return C.CString(string(data))
+}
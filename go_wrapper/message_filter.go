@@ -0,0 +1,105 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/dotabuff/manta"
+
+	"manta_wrapper/callbackreg"
+)
+
+// MessageFilter is a set of message type names (the same strings
+// callbackreg derives from each manta.Callbacks On<Name> method, e.g.
+// "CDOTAUserMsg_ChatMessage") to register callbacks for. ApplyFilter only
+// calls callbackreg.RegisterByName for names Allows reports true for,
+// instead of setupDOTAUserCallbacks's approach of registering every
+// message kind and letting addFilteredMessage discard most of them by
+// name after they've already been decoded.
+//
+// This is the closest thing to the manta.MessageFilter this request
+// actually asked for - a map[int32]bool keyed by the numeric
+// EDotaUserMessages/EBaseUserMessages/NET_Messages/SVC_Messages/
+// EDemoCommands enum ID, consulted by manta's own packet dispatch loop
+// before it reads the proto varint, so an excluded type is never
+// allocated or unmarshalled at all - that this tree can build without
+// vendoring and modifying github.com/dotabuff/manta's decode loop itself.
+// OnAnyMessage's doc comment (message_dispatch.go) already covers why: a
+// numeric MessageKind dispatch table has to live inside manta, which owns
+// both the callback struct such a table would replace and the .proto
+// enums it would be generated from.
+//
+// What MessageFilter/ApplyFilter get for free instead: manta.Parser only
+// allocates and decodes a message type when something has called the
+// matching Callbacks.OnXxx setter (that's the whole reason setupMissing-
+// style files exist - an unregistered type is invisible to the parser,
+// not just undelivered). So not registering a callback for an excluded
+// name skips its decode exactly the way a pre-decode filter would,
+// without needing manta itself to grow a new API. The one thing this
+// can't do that a true numeric ID table could is filter by raw wire ID
+// before a type even has a Go name resolved - irrelevant in practice
+// since every message manta can decode already has a Callbacks setter.
+type MessageFilter struct {
+	names map[string]bool
+}
+
+// NewFilterFromNames builds a MessageFilter allowing exactly the given
+// message type names (e.g. []string{"CDOTAUserMsg_ChatMessage",
+// "CDOTAUserMsg_LocationPing"}).
+func NewFilterFromNames(names []string) *MessageFilter {
+	f := &MessageFilter{names: make(map[string]bool, len(names))}
+	for _, n := range names {
+		f.names[n] = true
+	}
+	return f
+}
+
+// AllUserMessages returns a MessageFilter allowing every CDOTAUserMsg_*
+// type manta.Callbacks exposes an On<Name> setter for - the same method
+// walk cmd/gencallbacks' discoverDOTAUserMessageArms does, but without
+// resolving each one's wire enum ID, since Allows only needs the name.
+func AllUserMessages() *MessageFilter {
+	t := reflect.TypeOf((*manta.Callbacks)(nil))
+	names := make([]string, 0, t.NumMethod())
+	for i := 0; i < t.NumMethod(); i++ {
+		methodName := t.Method(i).Name
+		if strings.HasPrefix(methodName, "OnCDOTAUserMsg_") {
+			names = append(names, strings.TrimPrefix(methodName, "On"))
+		}
+	}
+	return NewFilterFromNames(names)
+}
+
+// Allows reports whether name passes the filter.
+func (f *MessageFilter) Allows(name string) bool {
+	if f == nil {
+		return false
+	}
+	return f.names[name]
+}
+
+// Union returns a new MessageFilter allowing every name either f or other
+// allows, leaving both inputs unmodified.
+func (f *MessageFilter) Union(other *MessageFilter) *MessageFilter {
+	merged := &MessageFilter{names: make(map[string]bool, len(f.names)+len(other.names))}
+	for n := range f.names {
+		merged.names[n] = true
+	}
+	for n := range other.names {
+		merged.names[n] = true
+	}
+	return merged
+}
+
+// ApplyFilter registers fn, via callbackreg.RegisterByName, only for the
+// message names filter allows - the "only want chat and pings" case this
+// request was written for, without paying setupDOTAUserCallbacks's
+// register-everything-then-discard-by-name cost.
+func ApplyFilter(parser *manta.Parser, filter *MessageFilter, fn func(name string, tick, netTick uint32, m interface{}) error) {
+	for name := range filter.names {
+		name := name
+		callbackreg.RegisterByName(parser.Callbacks, name, func(m interface{}) error {
+			return fn(name, parser.Tick, parser.NetTick, m)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/dotabuff/manta/dota"
+	"google.golang.org/protobuf/proto"
+)
+
+// messageConstructors maps a hot message's Go type name (matching the
+// addFilteredMessage/MessageEvent naming this wrapper already uses, e.g.
+// hotPooledMessageTypes in message_pooling.go) to a constructor returning a
+// fresh zero-valued instance. This is the constructor-table half of this
+// request's "two maps keyed by the integer wire IDs" design - it's keyed
+// by type name rather than wire ID because dota doesn't expose a generated
+// wire-ID constant for every family this covers (NET_Messages/SVC_Messages
+// in particular, see message_dispatch.go's netMessageEnumID for why that
+// lookup is already best-effort elsewhere in this tree), and guessing one
+// per type here risks silently wiring the wrong pool to the wrong message.
+var messageConstructors = map[string]func() proto.Message{
+	"CDOTAUserMsg_TE_Projectile":             func() proto.Message { return &dota.CDOTAUserMsg_TE_Projectile{} },
+	"CDOTAUserMsg_TE_ProjectileLoc":          func() proto.Message { return &dota.CDOTAUserMsg_TE_ProjectileLoc{} },
+	"CDOTAUserMsg_TE_UnitAnimation":          func() proto.Message { return &dota.CDOTAUserMsg_TE_UnitAnimation{} },
+	"CDOTAUserMsg_TE_UnitAnimationEnd":       func() proto.Message { return &dota.CDOTAUserMsg_TE_UnitAnimationEnd{} },
+	"CDOTAUserMsg_ParticleManager":           func() proto.Message { return &dota.CDOTAUserMsg_ParticleManager{} },
+	"CDOTAUserMsg_SpectatorPlayerUnitOrders": func() proto.Message { return &dota.CDOTAUserMsg_SpectatorPlayerUnitOrders{} },
+	"CMsgDOTACombatLogEntry":                 func() proto.Message { return &dota.CMsgDOTACombatLogEntry{} },
+	"CNETMsg_Tick":                           func() proto.Message { return &dota.CNETMsg_Tick{} },
+	"CSVCMsg_PacketEntities":                 func() proto.Message { return &dota.CSVCMsg_PacketEntities{} },
+}
+
+// messagePools holds one sync.Pool per messageConstructors entry, built
+// lazily on first use so a type that's never requested never allocates a
+// pool for itself.
+var (
+	messagePoolsMu sync.Mutex
+	messagePools   = make(map[string]*sync.Pool)
+)
+
+func poolFor(typeName string) (*sync.Pool, error) {
+	newMsg, ok := messageConstructors[typeName]
+	if !ok {
+		return nil, fmt.Errorf("message_pool_dispatch: no pooled constructor registered for %q", typeName)
+	}
+
+	messagePoolsMu.Lock()
+	defer messagePoolsMu.Unlock()
+	pool, ok := messagePools[typeName]
+	if !ok {
+		pool = &sync.Pool{New: func() interface{} { return newMsg() }}
+		messagePools[typeName] = pool
+	}
+	return pool, nil
+}
+
+// AcquirePooledMessage returns a reset, ready-to-decode-into instance of
+// typeName from its sync.Pool (allocating a fresh one if the pool is
+// empty), plus a release func the caller must invoke once it's done with
+// the message - release resets it via proto.Reset and returns it to the
+// pool. Calling the returned release func more than once is a no-op.
+//
+// This is real, usable pooling - proto.Reset/sync.Pool.Get/Put all work
+// exactly as described in the request - but it is NOT wired into any
+// parsing path in this package. Doing that would mean recording a raw
+// entity as {t entityType, size uint32, body []byte}, looking up its
+// constructor here, and calling pbuf.Unmarshal(body) into the pooled
+// instance before handing it to addFilteredMessage, the way hyperstone's
+// messageFactory does - and that requires a decode loop that hands this
+// wrapper the raw bytes before protobuf decoding happens.
+// github.com/dotabuff/manta's public API (the only surface available -
+// manta isn't vendored into this tree, see message_pooling.go's
+// SetMessagePooling/ReturnMessage for the identical gap recorded against
+// the simpler single-pool request that preceded this one) decodes every
+// message internally and hands callbacks an already-allocated,
+// manta-owned *dota.X; there is no hook to supply a pooled instance into
+// that decode, or to intercept the raw bytes first. So
+// AcquirePooledMessage exists as the building block a future manta
+// decode-loop hook could plug into, not as an active hot path -
+// addFilteredMessage's callers continue to receive manta-allocated
+// messages exactly as before, and no benchmark is included here since
+// there's nothing on this wrapper's own call path yet for one to measure
+// a before/after against (and this sandbox has no Go toolchain to run one
+// regardless).
+func AcquirePooledMessage(typeName string) (proto.Message, func(), error) {
+	pool, err := poolFor(typeName)
+	if err != nil {
+		return nil, nil, err
+	}
+	msg := pool.Get().(proto.Message)
+	released := false
+	release := func() {
+		if released {
+			return
+		}
+		released = true
+		proto.Reset(msg)
+		pool.Put(msg)
+	}
+	return msg, release, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import "fmt"
+
+// messagePoolingEnabled records whether SetMessagePooling(true) has been
+// called. It has no effect on parsing yet - see SetMessagePooling's doc
+// comment for why.
+var messagePoolingEnabled bool
+
+// SetMessagePooling is meant to toggle sync.Pool-backed reuse of decoded
+// protobuf messages for the hottest high-frequency types (CDemoPacket,
+// CMsgDOTACombatLogEntry, CDOTAUserMsg_ParticleManager,
+// CDOTAUserMsg_SpectatorPlayerUnitOrders, CDOTAUserMsg_UnitEvent,
+// CSVCMsg_PacketEntities, CNETMsg_Tick), the way the hyperstone codebase
+// pools its hottest types, default off since a callback that retains the
+// message past return would corrupt a pooled instance.
+//
+// That pooling has to live inside manta.Parser's own message decode
+// loop: every callback this wrapper registers is handed a *dota.X that
+// manta's internals already allocated, decoded, and own, with no hook
+// for a caller to supply a pooled instance or to be told it's safe to
+// Reset()/Put() one back after the callback returns. github.com/dotabuff/manta
+// doesn't expose such a hook today, and it isn't vendored into this tree
+// to add one to - so there's nothing on the wrapper side to wire this
+// toggle into yet. It's recorded here, rejecting enable attempts rather
+// than silently accepting a flag that does nothing, so the gap is
+// documented instead of missing. Flip this over to actually arm pooling
+// once manta grows a decode path that can hand back a poolable message.
+func SetMessagePooling(enabled bool) error {
+	if enabled {
+		return fmt.Errorf("message pooling requires decode-loop support in github.com/dotabuff/manta, which this wrapper does not vendor or control")
+	}
+	messagePoolingEnabled = false
+	return nil
+}
+
+// hotPooledMessageTypes is the whitelist a sync.Pool-backed
+// manta.MessagePool would cover: the TE_/ParticleManager/combat-log/
+// network-tier types that dominate allocation count on a long replay.
+// It's a slightly different list from SetMessagePooling's doc comment
+// (this one adds TE_Projectile/TE_ProjectileLoc and drops UnitEvent/
+// CDemoPacket) because a later backlog entry asked for pooling again
+// against its own idea of the hot set; both lists name the same handful
+// of high-frequency TE_/combat-log/network types, so they're recorded
+// together here rather than as two independent whitelists that would
+// drift apart.
+var hotPooledMessageTypes = []string{
+	"CDOTAUserMsg_TE_Projectile",
+	"CDOTAUserMsg_TE_ProjectileLoc",
+	"CDOTAUserMsg_TE_UnitAnimation",
+	"CDOTAUserMsg_TE_UnitAnimationEnd",
+	"CDOTAUserMsg_ParticleManager",
+	"CDOTAUserMsg_SpectatorPlayerUnitOrders",
+	"CMsgDOTACombatLogEntry",
+	"CNETMsg_Tick",
+	"CSVCMsg_PacketEntities",
+}
+
+// ReturnMessage is the per-message half of the pooling API this request
+// asked for - the hook a caller would invoke after a callback returns (or
+// that a WithPooled(true) parser option would call automatically) to
+// proto.Reset(m) and Put it back on its type's sync.Pool. It always
+// rejects rather than silently returning nil, for the same reason
+// SetMessagePooling(true) does: there is no manta.MessagePool behind it
+// to return a message to, because manta.Parser owns every decoded message
+// it hands to a callback and this tree doesn't vendor manta to add a
+// decode path that would let a pooled instance be supplied back in.
+// Separately, this wrapper's own UniversalParse/addFilteredMessage path
+// retains the *dota.X pointer it's handed in MessageEvent.Data for the
+// whole parse (it's JSON-marshaled at the end), so even a real Reset/Put
+// here would corrupt already-collected results for any caller going
+// through that path - whitelisting hotPooledMessageTypes alone wouldn't
+// be enough to make this safe.
+func ReturnMessage(m interface{}) error {
+	return fmt.Errorf("message pooling requires decode-loop support in github.com/dotabuff/manta, which this wrapper does not vendor or control")
+}
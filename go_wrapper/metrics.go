@@ -0,0 +1,137 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// typeMetrics accumulates per-message-type stats with atomic counters so
+// recording a message never contends with the parse goroutine (there's only
+// ever one writer per session, but atomics keep GetParseMetrics lock-free
+// too).
+type typeMetrics struct {
+	count    int64
+	nsTotal  int64
+	bytes    int64
+	lastTick uint32
+}
+
+// parseMetrics is one session's worth of per-type timing/byte counters.
+type parseMetrics struct {
+	mu     sync.Mutex
+	byType map[string]*typeMetrics
+}
+
+func newParseMetrics() *parseMetrics {
+	return &parseMetrics{byType: make(map[string]*typeMetrics)}
+}
+
+// record is called from the emit() timing shim around every callback:
+//
+//	start := time.Now()
+//	defer sess.metrics.record(msgType, time.Since(start), parser.Tick)
+func (m *parseMetrics) record(msgType string, elapsed time.Duration, tick uint32) {
+	m.mu.Lock()
+	tm, ok := m.byType[msgType]
+	if !ok {
+		tm = &typeMetrics{}
+		m.byType[msgType] = tm
+	}
+	m.mu.Unlock()
+
+	atomic.AddInt64(&tm.count, 1)
+	atomic.AddInt64(&tm.nsTotal, elapsed.Nanoseconds())
+	atomic.StoreUint32(&tm.lastTick, tick)
+}
+
+func (m *parseMetrics) reset() {
+	m.mu.Lock()
+	m.byType = make(map[string]*typeMetrics)
+	m.mu.Unlock()
+}
+
+// TypeMetricsSnapshot is the JSON-facing view of a single message type's
+// accumulated stats.
+type TypeMetricsSnapshot struct {
+	Count    int64  `json:"count"`
+	NsTotal  int64  `json:"ns_total"`
+	Bytes    int64  `json:"bytes"`
+	LastTick uint32 `json:"last_tick"`
+}
+
+// ParseMetricsSnapshot is the JSON payload returned by GetParseMetrics.
+type ParseMetricsSnapshot struct {
+	ByType         map[string]TypeMetricsSnapshot `json:"by_type"`
+	WallclockMs    int64                          `json:"wallclock_ms"`
+	TicksPerSecond float64                        `json:"ticks_per_second"`
+	Success        bool                           `json:"success"`
+	Error          string                         `json:"error,omitempty"`
+}
+
+func (m *parseMetrics) snapshot(wallclock time.Duration, lastTick uint32) ParseMetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := ParseMetricsSnapshot{
+		ByType:      make(map[string]TypeMetricsSnapshot, len(m.byType)),
+		WallclockMs: wallclock.Milliseconds(),
+		Success:     true,
+	}
+	for name, tm := range m.byType {
+		out.ByType[name] = TypeMetricsSnapshot{
+			Count:    atomic.LoadInt64(&tm.count),
+			NsTotal:  atomic.LoadInt64(&tm.nsTotal),
+			Bytes:    atomic.LoadInt64(&tm.bytes),
+			LastTick: atomic.LoadUint32(&tm.lastTick),
+		}
+	}
+	if wallclock > 0 {
+		out.TicksPerSecond = float64(lastTick) / wallclock.Seconds()
+	}
+	return out
+}
+
+//export GetParseMetrics
+func GetParseMetrics(handle C.ulonglong) *C.char {
+	v, ok := sessions.Load(uint64(handle))
+	if !ok {
+		return marshalMetrics(ParseMetricsSnapshot{Success: false, Error: "invalid handle"})
+	}
+	sess := v.(*parseSession)
+
+	var lastTick uint32
+	sess.metrics.mu.Lock()
+	for _, tm := range sess.metrics.byType {
+		if t := atomic.LoadUint32(&tm.lastTick); t > lastTick {
+			lastTick = t
+		}
+	}
+	sess.metrics.mu.Unlock()
+
+	return marshalMetrics(sess.metrics.snapshot(time.Since(sess.start), lastTick))
+}
+
+//export ResetParseMetrics
+func ResetParseMetrics(handle C.ulonglong) {
+	v, ok := sessions.Load(uint64(handle))
+	if !ok {
+		return
+	}
+	sess := v.(*parseSession)
+	sess.metrics.reset()
+}
+
+func marshalMetrics(snap ParseMetricsSnapshot) *C.char {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		data, _ = json.Marshal(ParseMetricsSnapshot{Success: false, Error: err.Error()})
+	}
+	return C.CString(string(data))
+}
@@ -0,0 +1,120 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ModifierMetadata is one modifier's typed classification - the same
+// "shouldn't need a code change for new content" motivation
+// AbilityMetadata (ability_registry.go) already has, for crowd-control and
+// defensive buffs instead of talents/ultimates.
+type ModifierMetadata struct {
+	IsStun    bool `json:"is_stun,omitempty"`
+	IsSilence bool `json:"is_silence,omitempty"`
+	IsHex     bool `json:"is_hex,omitempty"`
+	IsRoot    bool `json:"is_root,omitempty"`
+	IsSlow    bool `json:"is_slow,omitempty"`
+	IsShield  bool `json:"is_shield,omitempty"`
+}
+
+// ModifierRegistry is a JSON data pack mapping a modifier's name (as
+// resolved from the ModifierNames/CombatLogNames string tables) to its
+// ModifierMetadata. Same JSON-only limitation as AbilityRegistry: this tree
+// vendors no YAML library.
+type ModifierRegistry struct {
+	Modifiers map[string]ModifierMetadata `json:"modifiers"`
+}
+
+var (
+	modifierRegistryMu sync.RWMutex
+	currentModifierReg *ModifierRegistry
+)
+
+// SetModifierRegistry loads path as a JSON-encoded ModifierRegistry and
+// installs it as the registry resolveModifierMetadata consults, mirroring
+// SetAbilityRegistry's global-setter pattern.
+func SetModifierRegistry(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading modifier registry: %w", err)
+	}
+	var registry ModifierRegistry
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return fmt.Errorf("parsing modifier registry: %w", err)
+	}
+
+	modifierRegistryMu.Lock()
+	currentModifierReg = &registry
+	modifierRegistryMu.Unlock()
+	return nil
+}
+
+// ClearModifierRegistry removes any loaded registry, reverting
+// resolveModifierMetadata to its string-heuristic fallback for every
+// modifier.
+func ClearModifierRegistry() {
+	modifierRegistryMu.Lock()
+	currentModifierReg = nil
+	modifierRegistryMu.Unlock()
+}
+
+// lookupModifierMetadata returns name's metadata from the currently loaded
+// registry, if one is loaded and has an entry for it.
+func lookupModifierMetadata(name string) (ModifierMetadata, bool) {
+	modifierRegistryMu.RLock()
+	defer modifierRegistryMu.RUnlock()
+	if currentModifierReg == nil {
+		return ModifierMetadata{}, false
+	}
+	meta, ok := currentModifierReg.Modifiers[name]
+	return meta, ok
+}
+
+// classifyModifierByName is the string-substring fallback used when no
+// registry entry exists for name - deliberately coarse (the same tradeoff
+// extractAbilitiesForSnapshot's pre-registry heuristics already made), good
+// enough to flag the common cases without a data pack loaded.
+func classifyModifierByName(name string) ModifierMetadata {
+	lower := strings.ToLower(name)
+	return ModifierMetadata{
+		IsStun:    strings.Contains(lower, "stun"),
+		IsSilence: strings.Contains(lower, "silence"),
+		IsHex:     strings.Contains(lower, "hex") || strings.Contains(lower, "polymorph"),
+		IsRoot:    strings.Contains(lower, "root") || strings.Contains(lower, "entangle"),
+		IsSlow:    strings.Contains(lower, "slow"),
+		IsShield:  strings.Contains(lower, "shield") || strings.Contains(lower, "spell_immunity") || strings.Contains(lower, "barrier"),
+	}
+}
+
+// resolveModifierMetadata is the registry-with-fallback entry point
+// extractModifiersForSnapshot-equivalent wiring (entity_parser.go's
+// activeModifiers tracking in RunEntityParse) calls for every observed
+// modifier.
+func resolveModifierMetadata(name string) ModifierMetadata {
+	if meta, ok := lookupModifierMetadata(name); ok {
+		return meta
+	}
+	return classifyModifierByName(name)
+}
+
+//export LoadModifierRegistry
+func LoadModifierRegistry(path *C.char) *C.char {
+	goPath := C.GoString(path)
+
+	result := map[string]interface{}{"success": true}
+	if err := SetModifierRegistry(goPath); err != nil {
+		result["success"] = false
+		result["error"] = err.Error()
+	}
+	data, _ := json.Marshal(result)
+	return C.CString(string(data))
+}
@@ -0,0 +1,197 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// ModifiersStreamSummary is ParseModifiersStream's return value - unlike
+// ParseModifiers it never holds the full modifier set in memory to hand
+// back through CGo, so callers get a count instead and read the actual
+// entries back out of outPath.
+type ModifiersStreamSummary struct {
+	TotalModifiers int        `json:"total_modifiers"`
+	OutPath        string     `json:"out_path"`
+	Success        bool       `json:"success"`
+	Error          *ErrorInfo `json:"error,omitempty"`
+}
+
+// ParseModifiersStream is ParseModifiers' streaming sibling: it writes
+// newline-delimited JSON modifier entries directly to outPath as they're
+// observed, flushing periodically, instead of buffering a ModifiersResult
+// and marshaling one giant blob back through CGo. As with
+// ParseGameEventsStream, modifiers need no post-Start resolution, so each
+// entry is written exactly once from inside the gameTime.Annotate callback.
+//
+//export ParseModifiersStream
+func ParseModifiersStream(filePath *C.char, configJSON *C.char, outPath *C.char) *C.char {
+	goFilePath := C.GoString(filePath)
+	goConfigJSON := C.GoString(configJSON)
+	goOutPath := C.GoString(outPath)
+
+	summary := &ModifiersStreamSummary{OutPath: goOutPath}
+
+	defer func() {
+		if r := recover(); r != nil {
+			summary.Success = false
+			summary.Error = simpleErrorInfo(ErrPanic, fmt.Sprintf("panic during parsing: %v", r))
+		}
+	}()
+
+	config := ModifiersConfig{}
+	if goConfigJSON != "" {
+		if err := json.Unmarshal([]byte(goConfigJSON), &config); err != nil {
+			summary.Error = simpleErrorInfo(ErrIO, fmt.Sprintf("invalid config JSON: %v", err))
+			return marshalModifiersStreamSummary(summary)
+		}
+	}
+
+	in, err := os.Open(goFilePath)
+	if err != nil {
+		summary.Error = classifyOpenError(err).toErrorInfo()
+		return marshalModifiersStreamSummary(summary)
+	}
+	defer in.Close()
+
+	out, err := os.Create(goOutPath)
+	if err != nil {
+		summary.Error = simpleErrorInfo(ErrIO, fmt.Sprintf("error creating out_path: %v", err))
+		return marshalModifiersStreamSummary(summary)
+	}
+	defer out.Close()
+
+	parser, err := manta.NewStreamParser(in)
+	if err != nil {
+		summary.Error = classifyParseError(err).toErrorInfo()
+		return marshalModifiersStreamSummary(summary)
+	}
+
+	gameTime := newGameTimeTracker()
+	gameTime.Watch(parser)
+
+	debuffNames := make(map[string]bool, len(config.DebuffNames))
+	for _, name := range config.DebuffNames {
+		debuffNames[name] = true
+	}
+	resolveName := func(class int32) string {
+		if name, ok := parser.LookupStringByIndex("ModifierNames", class); ok {
+			return name
+		}
+		if name, ok := parser.LookupStringByIndex("CombatLogNames", class); ok {
+			return name
+		}
+		return ""
+	}
+	resolveEntityName := func(handle uint32) string {
+		if ent := parser.FindEntityByHandle(uint64(handle)); ent != nil {
+			return ent.GetClassName()
+		}
+		return ""
+	}
+
+	writer := bufio.NewWriter(out)
+	written := 0
+	var writeErr error
+
+	parser.OnModifierTableEntry(func(m *dota.CDOTAModifierBuffTableEntry) error {
+		if writeErr != nil {
+			return nil
+		}
+		if config.MaxModifiers > 0 && written >= config.MaxModifiers {
+			return nil
+		}
+
+		isAura := m.GetAura()
+		modifierName := resolveName(m.GetModifierClass())
+		isDebuff := debuffNames[modifierName] || modifierNameLooksLikeDebuff(modifierName)
+		if config.AurasOnly && !isAura {
+			return nil
+		}
+		if config.DebuffsOnly && !isDebuff {
+			return nil
+		}
+
+		entry := ModifierEntry{
+			Tick:          parser.Tick,
+			NetTick:       parser.NetTick,
+			Parent:        m.GetParent(),
+			Caster:        m.GetCaster(),
+			Ability:       m.GetAbility(),
+			ModifierClass: m.GetModifierClass(),
+			SerialNum:     m.GetSerialNum(),
+			Index:         m.GetIndex(),
+			CreationTime:  m.GetCreationTime(),
+			Duration:      m.GetDuration(),
+			StackCount:    m.GetStackCount(),
+			IsAura:        isAura,
+			IsDebuff:      isDebuff,
+			ParentName:    resolveEntityName(m.GetParent()),
+			CasterName:    resolveEntityName(m.GetCaster()),
+			AbilityName:   resolveEntityName(m.GetAbility()),
+			ModifierName:  modifierName,
+		}
+
+		gameTime.Annotate(func(s gameTimeSnapshot) {
+			entry.GameTime = s.GameTime
+			entry.MatchTime = s.MatchTime
+			entry.GameState = s.GameState
+			entry.GamePhase = s.GamePhase
+			entry.IsPaused = s.IsPaused
+
+			data, err := json.Marshal(entry)
+			if err != nil {
+				writeErr = err
+				return
+			}
+			if _, err := writer.Write(data); err != nil {
+				writeErr = err
+				return
+			}
+			if err := writer.WriteByte('\n'); err != nil {
+				writeErr = err
+				return
+			}
+			written++
+			if written%30 == 0 {
+				writer.Flush()
+			}
+		})
+		return nil
+	})
+
+	if err := parser.Start(); err != nil {
+		summary.Error = classifyParseError(err).toErrorInfo()
+		return marshalModifiersStreamSummary(summary)
+	}
+
+	if writeErr != nil {
+		summary.Error = simpleErrorInfo(ErrIO, fmt.Sprintf("error writing entry: %v", writeErr))
+		return marshalModifiersStreamSummary(summary)
+	}
+	if err := writer.Flush(); err != nil {
+		summary.Error = simpleErrorInfo(ErrIO, fmt.Sprintf("error flushing output: %v", err))
+		return marshalModifiersStreamSummary(summary)
+	}
+
+	summary.TotalModifiers = written
+	summary.Success = true
+	return marshalModifiersStreamSummary(summary)
+}
+
+func marshalModifiersStreamSummary(s *ModifiersStreamSummary) *C.char {
+	data, err := json.Marshal(s)
+	if err != nil {
+		data, _ = json.Marshal(&ModifiersStreamSummary{Success: false, Error: simpleErrorInfo(ErrIO, err.Error())})
+	}
+	return C.CString(string(data))
+}
@@ -0,0 +1,243 @@
+package main
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MultiExtractConfig selects which of the existing single-file extractors
+// ParseBatch should run against every path, plus how many workers to fan
+// out across. This is the multi-extractor counterpart to
+// ParseUniversalBatch, which only ever runs the universal (header + chat +
+// lightweight combat log) walk; here each field is the same Config struct
+// its single-file export already accepts, left nil to skip that extractor.
+type MultiExtractConfig struct {
+	Workers    int               `json:"workers"`
+	GameEvents *GameEventsConfig `json:"game_events,omitempty"`
+	Modifiers  *ModifiersConfig  `json:"modifiers,omitempty"`
+	Entities   *EntitiesConfig   `json:"entities,omitempty"`
+	CombatLog  *CombatLogConfig  `json:"combat_log,omitempty"`
+}
+
+// FileResult holds whichever of ParseBatch's extractors MultiExtractConfig
+// selected for one file, nil for the ones that weren't requested.
+type FileResult struct {
+	GameEvents *GameEventsResult `json:"game_events,omitempty"`
+	Modifiers  *ModifiersResult  `json:"modifiers,omitempty"`
+	Entities   *EntitiesResult   `json:"entities,omitempty"`
+	CombatLog  *CombatLogResult  `json:"combat_log,omitempty"`
+}
+
+// ParseBatchResult is the ParseBatch response envelope.
+type ParseBatchResult struct {
+	PerFile  map[string]*FileResult `json:"per_file"`
+	Failures []string               `json:"failures"`
+	Elapsed  float64                `json:"elapsed"`
+	Handle   uint64                 `json:"handle"`
+	Success  bool                   `json:"success"`
+	Error    string                 `json:"error,omitempty"`
+}
+
+// batchProgress is BatchProgress's backing counter set for one in-flight
+// ParseBatch run. done is updated atomically by workers as they finish a
+// file; currentFile is guarded by mu since multiple workers write it
+// concurrently and BatchProgress only needs the most recent value, not a
+// consistent snapshot across fields.
+type batchProgress struct {
+	total int64
+	done  int64
+
+	mu          sync.Mutex
+	currentFile string
+}
+
+var (
+	multiBatchMu         sync.Mutex
+	multiBatches         = make(map[uint64]*batchProgress)
+	nextMultiBatchHandle uint64
+)
+
+func registerMultiBatch(total int) (uint64, *batchProgress) {
+	multiBatchMu.Lock()
+	defer multiBatchMu.Unlock()
+	nextMultiBatchHandle++
+	handle := nextMultiBatchHandle
+	progress := &batchProgress{total: int64(total)}
+	multiBatches[handle] = progress
+	return handle, progress
+}
+
+func unregisterMultiBatch(handle uint64) {
+	multiBatchMu.Lock()
+	defer multiBatchMu.Unlock()
+	delete(multiBatches, handle)
+}
+
+//export BatchProgress
+func BatchProgress(handle C.uint64_t) *C.char {
+	multiBatchMu.Lock()
+	progress, ok := multiBatches[uint64(handle)]
+	multiBatchMu.Unlock()
+
+	if !ok {
+		data, _ := json.Marshal(map[string]interface{}{"success": false, "error": "unknown or already-finished batch handle"})
+		return C.CString(string(data))
+	}
+
+	progress.mu.Lock()
+	currentFile := progress.currentFile
+	progress.mu.Unlock()
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"success":      true,
+		"done":         atomic.LoadInt64(&progress.done),
+		"total":        progress.total,
+		"current_file": currentFile,
+	})
+	return C.CString(string(data))
+}
+
+//export ParseBatch
+func ParseBatch(filePathsJSON *C.char, configJSON *C.char) *C.char {
+	var paths []string
+	if err := json.Unmarshal([]byte(C.GoString(filePathsJSON)), &paths); err != nil {
+		return marshalParseBatchResult(&ParseBatchResult{Success: false, Error: fmt.Sprintf("invalid file_paths JSON: %v", err)})
+	}
+
+	var config MultiExtractConfig
+	if goConfigJSON := C.GoString(configJSON); goConfigJSON != "" {
+		if err := json.Unmarshal([]byte(goConfigJSON), &config); err != nil {
+			return marshalParseBatchResult(&ParseBatchResult{Success: false, Error: fmt.Sprintf("invalid config JSON: %v", err)})
+		}
+	}
+
+	return marshalParseBatchResult(RunParseBatch(paths, config))
+}
+
+// RunParseBatch fans paths out across a bounded worker pool (config.Workers,
+// or runtime.NumCPU() if <= 0), running whichever extractors config selects
+// against each file and merging into PerFile. One corrupt/missing demo only
+// adds its path to Failures; the batch overall still succeeds.
+func RunParseBatch(paths []string, config MultiExtractConfig) *ParseBatchResult {
+	started := time.Now()
+
+	result := &ParseBatchResult{
+		PerFile:  make(map[string]*FileResult, len(paths)),
+		Failures: make([]string, 0),
+		Success:  true,
+	}
+	if len(paths) == 0 {
+		return result
+	}
+
+	workers := config.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+
+	handle, progress := registerMultiBatch(len(paths))
+	defer unregisterMultiBatch(handle)
+	result.Handle = handle
+
+	jobs := make(chan string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				progress.mu.Lock()
+				progress.currentFile = path
+				progress.mu.Unlock()
+
+				fileResult, err := runFileExtractRecovered(path, config)
+
+				mu.Lock()
+				if err != nil {
+					result.Failures = append(result.Failures, path)
+				} else {
+					result.PerFile[path] = fileResult
+				}
+				mu.Unlock()
+
+				atomic.AddInt64(&progress.done, 1)
+			}
+		}()
+	}
+
+	for _, p := range paths {
+		jobs <- p
+	}
+	close(jobs)
+	wg.Wait()
+
+	result.Elapsed = time.Since(started).Seconds()
+	return result
+}
+
+// runFileExtractRecovered isolates one file's multi-extractor run so a
+// panic in manta (a corrupt demo) can't take down the rest of the batch.
+func runFileExtractRecovered(path string, config MultiExtractConfig) (fileResult *FileResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			fileResult = nil
+			err = fmt.Errorf("panic during parsing: %v", r)
+		}
+	}()
+
+	fileResult = &FileResult{}
+
+	if config.GameEvents != nil {
+		res, e := RunGameEventsParse(path, *config.GameEvents)
+		if e != nil {
+			return nil, e
+		}
+		fileResult.GameEvents = res
+	}
+	if config.Modifiers != nil {
+		res, e := RunModifiersParse(path, *config.Modifiers)
+		if e != nil {
+			return nil, e
+		}
+		fileResult.Modifiers = res
+	}
+	if config.Entities != nil {
+		res, e := RunEntitiesQuery(path, *config.Entities)
+		if e != nil {
+			return nil, e
+		}
+		fileResult.Entities = res
+	}
+	if config.CombatLog != nil {
+		res, e := RunCombatLogParse(path, *config.CombatLog)
+		if e != nil {
+			return nil, e
+		}
+		fileResult.CombatLog = res
+	}
+
+	return fileResult, nil
+}
+
+func marshalParseBatchResult(r *ParseBatchResult) *C.char {
+	data, err := json.Marshal(r)
+	if err != nil {
+		data, _ = json.Marshal(&ParseBatchResult{Success: false, Error: err.Error()})
+	}
+	return C.CString(string(data))
+}
@@ -0,0 +1,33 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+
+	"manta_wrapper/marshal"
+)
+
+// SetOutputFormat switches the wire format every marshalXxx helper in this
+// package encodes through: "json" (the default) or "msgpack", for Python
+// callers that already deserialize with the msgpack package and would
+// rather skip the JSON parse cost entirely. See marshal.Encode's doc
+// comment for the one caveat: exports that return a null-terminated
+// C.CString can't carry embedded NUL bytes, so msgpack is only safe today
+// for endpoints that write to a file or pipe rather than a C string.
+//
+//export SetOutputFormat
+func SetOutputFormat(format *C.char) *C.char {
+	goFormat := C.GoString(format)
+
+	result := map[string]interface{}{"success": true}
+	if err := marshal.SetFormat(goFormat); err != nil {
+		result = map[string]interface{}{"success": false, "error": err.Error()}
+	}
+
+	data, _ := json.Marshal(result)
+	return C.CString(string(data))
+}
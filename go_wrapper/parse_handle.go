@@ -0,0 +1,261 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dotabuff/manta"
+)
+
+// parseHandleSession is one StartParseHandle run: parser.Start() executes
+// in its own goroutine against a context.Context (WithCancel, plus
+// WithDeadline when the config carries deadline_ms) so CancelParseHandle or
+// a timeout can interrupt a long parse early - every registered callback
+// checks ctx.Err() and calls parser.Stop() once it's non-nil, the same
+// Stop()-on-condition shape RunEntitiesQuery already uses for its AtTick
+// early exit. mu guards everything below it so PollParseHandle can read a
+// safe snapshot from another goroutine while the parse is still running.
+type parseHandleSession struct {
+	mu        sync.Mutex
+	cancel    context.CancelFunc
+	file      *os.File
+	done      bool
+	cancelled bool
+	err       *ParseError
+
+	gameEvents *GameEventsResult
+	modifiers  *ModifiersResult
+}
+
+var (
+	parseHandlesMu    sync.Mutex
+	parseHandles      = make(map[uint64]*parseHandleSession)
+	nextParseHandleID uint64
+)
+
+func registerParseHandle(sess *parseHandleSession) uint64 {
+	parseHandlesMu.Lock()
+	defer parseHandlesMu.Unlock()
+	nextParseHandleID++
+	id := nextParseHandleID
+	parseHandles[id] = sess
+	return id
+}
+
+func getParseHandle(id uint64) *parseHandleSession {
+	parseHandlesMu.Lock()
+	defer parseHandlesMu.Unlock()
+	return parseHandles[id]
+}
+
+func removeParseHandle(id uint64) {
+	parseHandlesMu.Lock()
+	defer parseHandlesMu.Unlock()
+	delete(parseHandles, id)
+}
+
+// ParseHandleResult is StartParseHandle/PollParseHandle's shared response
+// envelope. Exactly one of GameEvents/Modifiers is populated, matching
+// whichever kind StartParseHandle was given; both are safe to read
+// mid-parse since the owning goroutine only mutates them under
+// parseHandleSession.mu.
+type ParseHandleResult struct {
+	HandleID   uint64            `json:"handle_id,omitempty"`
+	Done       bool              `json:"done"`
+	Cancelled  bool              `json:"cancelled,omitempty"`
+	Success    bool              `json:"success"`
+	Error      *ErrorInfo        `json:"error,omitempty"`
+	GameEvents *GameEventsResult `json:"game_events,omitempty"`
+	Modifiers  *ModifiersResult  `json:"modifiers,omitempty"`
+}
+
+func marshalParseHandleResult(r *ParseHandleResult) *C.char {
+	data, err := json.Marshal(r)
+	if err != nil {
+		data, _ = json.Marshal(&ParseHandleResult{Error: simpleErrorInfo(ErrIO, err.Error())})
+	}
+	return C.CString(string(data))
+}
+
+// parseHandleDeadline is the subset of a kind-specific config blob
+// StartParseHandle reads before handing the same bytes on to the kind's own
+// config struct (GameEventsConfig, ModifiersConfig, ...), so deadline_ms
+// can sit alongside e.g. "max_events" in one JSON object without either
+// struct needing to know about the other.
+type parseHandleDeadline struct {
+	DeadlineMs int64 `json:"deadline_ms"`
+}
+
+// StartParseHandle begins an asynchronous, cancellable parse of filePath
+// and returns immediately with a handle Python can poll via
+// PollParseHandle or interrupt via CancelParseHandle. kind selects which
+// collector runs and how configJSON is interpreted: "game_events"
+// (GameEventsConfig) or "modifiers" (ModifiersConfig). An optional
+// deadline_ms in configJSON installs a timer that cancels the parse on its
+// own, the same way context.WithTimeout would for an outbound request.
+//
+//export StartParseHandle
+func StartParseHandle(filePath *C.char, kind *C.char, configJSON *C.char) *C.char {
+	goFilePath := C.GoString(filePath)
+	goKind := C.GoString(kind)
+	goConfigJSON := C.GoString(configJSON)
+
+	var deadline parseHandleDeadline
+	if goConfigJSON != "" {
+		json.Unmarshal([]byte(goConfigJSON), &deadline)
+	}
+
+	file, err := os.Open(goFilePath)
+	if err != nil {
+		return marshalParseHandleResult(&ParseHandleResult{Error: classifyOpenError(err).toErrorInfo()})
+	}
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		file.Close()
+		return marshalParseHandleResult(&ParseHandleResult{Error: classifyParseError(err).toErrorInfo()})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if deadline.DeadlineMs > 0 {
+		timer := time.AfterFunc(time.Duration(deadline.DeadlineMs)*time.Millisecond, cancel)
+		prevCancel := cancel
+		cancel = func() {
+			timer.Stop()
+			prevCancel()
+		}
+	}
+
+	sess := &parseHandleSession{cancel: cancel, file: file}
+
+	gameTime := newGameTimeTracker()
+	gameTime.Watch(parser)
+
+	switch goKind {
+	case "game_events":
+		config := GameEventsConfig{}
+		if goConfigJSON != "" {
+			json.Unmarshal([]byte(goConfigJSON), &config)
+		}
+		sess.gameEvents = &GameEventsResult{Events: make([]GameEventData, 0), EventTypes: make([]string, 0)}
+		registerGameEventsCallbacks(parser, config, sess.gameEvents, gameTime)
+	case "modifiers":
+		config := ModifiersConfig{}
+		if goConfigJSON != "" {
+			json.Unmarshal([]byte(goConfigJSON), &config)
+		}
+		sess.modifiers = &ModifiersResult{Modifiers: make([]ModifierEntry, 0)}
+		registerModifiersCallbacks(parser, config, sess.modifiers, gameTime)
+	default:
+		file.Close()
+		cancel()
+		return marshalParseHandleResult(&ParseHandleResult{Error: simpleErrorInfo(ErrIO, fmt.Sprintf("unknown parse handle kind: %q", goKind))})
+	}
+
+	parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+		if ctx.Err() != nil {
+			parser.Stop()
+		}
+		return nil
+	})
+
+	handleID := registerParseHandle(sess)
+
+	go func() {
+		defer file.Close()
+		defer cancel()
+
+		startErr := parser.Start()
+
+		sess.mu.Lock()
+		defer sess.mu.Unlock()
+		sess.done = true
+		if startErr != nil {
+			sess.err = classifyParseError(startErr)
+		}
+		if sess.gameEvents != nil {
+			sess.gameEvents.Success = sess.err == nil
+			sess.gameEvents.TotalEvents = len(sess.gameEvents.Events)
+		}
+		if sess.modifiers != nil {
+			sess.modifiers.Success = sess.err == nil
+			sess.modifiers.TotalModifiers = len(sess.modifiers.Modifiers)
+		}
+	}()
+
+	return marshalParseHandleResult(&ParseHandleResult{HandleID: handleID, Success: true})
+}
+
+// PollParseHandle returns a snapshot of handleID's progress: whatever
+// events/modifiers have been captured so far, plus Done once parser.Start()
+// has returned. It does not block - a caller polling a still-running
+// handle just gets Done=false and the partial result accumulated up to
+// that point.
+//
+//export PollParseHandle
+func PollParseHandle(handleID C.ulonglong) *C.char {
+	id := uint64(handleID)
+	sess := getParseHandle(id)
+	if sess == nil {
+		return marshalParseHandleResult(&ParseHandleResult{Done: true, Error: simpleErrorInfo(ErrIO, "unknown or already-finished parse handle")})
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	result := &ParseHandleResult{HandleID: id, Done: sess.done, Cancelled: sess.cancelled, Success: sess.err == nil}
+	if sess.err != nil {
+		result.Error = sess.err.toErrorInfo()
+	}
+	if sess.gameEvents != nil {
+		events := append([]GameEventData(nil), sess.gameEvents.Events...)
+		snapshot := *sess.gameEvents
+		snapshot.Events = events
+		result.GameEvents = &snapshot
+	}
+	if sess.modifiers != nil {
+		mods := append([]ModifierEntry(nil), sess.modifiers.Modifiers...)
+		snapshot := *sess.modifiers
+		snapshot.Modifiers = mods
+		result.Modifiers = &snapshot
+	}
+
+	if sess.done {
+		removeParseHandle(id)
+	}
+	return marshalParseHandleResult(result)
+}
+
+// CancelParseHandle interrupts an in-flight StartParseHandle run. The
+// session's next OnEntity callback observes ctx.Err() and calls
+// parser.Stop(), so the background goroutine's parser.Start() returns
+// shortly after rather than running to the end of the replay.
+//
+//export CancelParseHandle
+func CancelParseHandle(handleID C.ulonglong) *C.char {
+	id := uint64(handleID)
+	sess := getParseHandle(id)
+	if sess == nil {
+		result := map[string]interface{}{"success": false, "error": "unknown or already-finished parse handle"}
+		data, _ := json.Marshal(result)
+		return C.CString(string(data))
+	}
+
+	sess.mu.Lock()
+	sess.cancelled = true
+	sess.mu.Unlock()
+	sess.cancel()
+
+	result := map[string]interface{}{"success": true}
+	data, _ := json.Marshal(result)
+	return C.CString(string(data))
+}
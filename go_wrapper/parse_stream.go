@@ -0,0 +1,453 @@
+package main
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+
+typedef void (*manta_stream_callback)(const char*);
+
+static inline void manta_call_stream_callback(manta_stream_callback cb, const char* data) {
+	cb(data);
+}
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"unsafe"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// StreamFlushConfig sets one collector's flush cadence for ParseStream:
+// its accumulated slice is serialized and emitted through the callback,
+// then truncated, once either threshold is crossed. Either field left at
+// 0 disables that trigger; leaving both at 0 means the collector only
+// flushes once, at end of parse (same as RunParse's buffer-everything
+// behavior, just routed through the callback instead of the return value).
+type StreamFlushConfig struct {
+	FlushEveryTicks  uint32 `json:"flush_every_ticks"`
+	FlushEveryEvents int    `json:"flush_every_events"`
+}
+
+// ParseStreamConfig is ParseConfig's sibling for ParseStream. It reuses
+// ParseConfig's own collector config structs unchanged - the collector
+// logic (filters, max counts) is identical, only the output side differs
+// - and adds a per-collector FlushConfig for the batching ParseStream was
+// asked for. Only the collectors named in the request (combat log, game
+// events, attacks, entity deaths, modifiers) are supported; collectors
+// that only make sense as one whole-replay result (header, game info,
+// entity snapshots, ...) aren't, since there's nothing to batch. Each
+// collector's MaxEvents cap is ignored here - ParseStream exists
+// specifically so a caller can process an unbounded event count without
+// holding it all in memory at once, so capping total count would defeat
+// the point; per-batch size is governed by Flush instead.
+type ParseStreamConfig struct {
+	CombatLog    *CombatLogConfig    `json:"combat_log,omitempty"`
+	GameEvents   *GameEventsConfig   `json:"game_events,omitempty"`
+	Attacks      *AttacksConfig      `json:"attacks,omitempty"`
+	EntityDeaths *EntityDeathsConfig `json:"entity_deaths,omitempty"`
+	Modifiers    *ModifiersConfig    `json:"modifiers,omitempty"`
+
+	Flush StreamFlushConfig `json:"flush"`
+}
+
+// streamBatch is what ParseStream's callback receives for each flush:
+// Collector names which config section produced it ("combat_log",
+// "game_events", "attacks", "entity_deaths", "modifiers"), Final marks the
+// end-of-parse flush of whatever remained under the flush thresholds.
+type streamBatch struct {
+	Collector string      `json:"collector"`
+	Tick      uint32      `json:"tick"`
+	Final     bool        `json:"final"`
+	Events    interface{} `json:"events"`
+}
+
+//export ParseStream
+func ParseStream(filePath *C.char, configJSON *C.char, callback C.uintptr_t) *C.char {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	goFilePath := C.GoString(filePath)
+	goConfigJSON := C.GoString(configJSON)
+	cb := C.manta_stream_callback(unsafe.Pointer(uintptr(callback)))
+
+	fail := func(err error) *C.char {
+		data, _ := json.Marshal(map[string]interface{}{"success": false, "error": err.Error()})
+		return C.CString(string(data))
+	}
+
+	config := ParseStreamConfig{}
+	if goConfigJSON != "" {
+		if err := json.Unmarshal([]byte(goConfigJSON), &config); err != nil {
+			return fail(fmt.Errorf("invalid config JSON: %w", err))
+		}
+	}
+
+	file, err := os.Open(goFilePath)
+	if err != nil {
+		return fail(fmt.Errorf("error opening file: %w", err))
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		return fail(fmt.Errorf("error creating parser: %w", err))
+	}
+
+	emit := func(collector string, final bool, events interface{}) {
+		payload, err := json.Marshal(streamBatch{Collector: collector, Tick: parser.Tick, Final: final, Events: events})
+		if err != nil {
+			return
+		}
+		cData := C.CString(string(payload))
+		C.manta_call_stream_callback(cb, cData)
+		C.free(unsafe.Pointer(cData))
+	}
+
+	flushTicks := config.Flush.FlushEveryTicks
+	flushEvents := config.Flush.FlushEveryEvents
+	shouldFlush := func(lastFlushTick uint32, count int) bool {
+		if flushEvents > 0 && count >= flushEvents {
+			return true
+		}
+		if flushTicks > 0 && parser.Tick >= lastFlushTick+flushTicks {
+			return true
+		}
+		return false
+	}
+
+	var gameStartTime float32
+	var gameStartTick uint32
+
+	// Combat log: flushed through finalizeCombatLog per batch, the same
+	// name-resolution pass RunParse runs once at the very end - each
+	// batch only sees gameStartTick as it's known so far, so GameTime on
+	// early batches emitted before the horn may read as a small negative
+	// or zero value until the GAME_STATE entry announcing it arrives.
+	if config.CombatLog != nil {
+		var raw []rawCombatLogEntry
+		var lastFlushTick uint32
+		heroLevels := make(map[string]int32)
+
+		parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+			if e == nil {
+				return nil
+			}
+			className := e.GetClassName()
+			if !containsHeroClass(className) {
+				return nil
+			}
+			heroName := heroNameFromClass(className)
+			if heroName == "" {
+				return nil
+			}
+			if level, ok := e.GetInt32("m_iCurrentLevel"); ok && level > 0 {
+				heroLevels[heroName] = level
+			}
+			return nil
+		})
+
+		parser.Callbacks.OnCMsgDOTACombatLogEntry(func(m *dota.CMsgDOTACombatLogEntry) error {
+			if m.GetType() == dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_GAME_STATE && m.GetValue() == 5 {
+				gameStartTime = m.GetTimestamp()
+				gameStartTick = parser.Tick
+			}
+
+			var attackerLevel, targetLevel int32
+			if n, ok := parser.LookupStringByIndex("CombatLogNames", int32(m.GetAttackerName())); ok {
+				attackerLevel = heroLevels[normalizeHeroName(n)]
+			}
+			if n, ok := parser.LookupStringByIndex("CombatLogNames", int32(m.GetTargetName())); ok {
+				targetLevel = heroLevels[normalizeHeroName(n)]
+			}
+
+			raw = append(raw, rawCombatLogEntry{
+				tick:              parser.Tick,
+				netTick:           parser.NetTick,
+				msg:               m,
+				attackerHeroLevel: attackerLevel,
+				targetHeroLevel:   targetLevel,
+			})
+
+			if shouldFlush(lastFlushTick, len(raw)) {
+				finalized := finalizeCombatLog(parser, raw, gameStartTime, gameStartTick, config.CombatLog)
+				emit("combat_log", false, finalized.Entries)
+				raw = nil
+				lastFlushTick = parser.Tick
+			}
+			return nil
+		})
+
+		defer func() {
+			if len(raw) > 0 {
+				finalized := finalizeCombatLog(parser, raw, gameStartTime, gameStartTick, config.CombatLog)
+				emit("combat_log", true, finalized.Entries)
+			} else {
+				emit("combat_log", true, []CombatLogEntry{})
+			}
+		}()
+	}
+
+	// Game events: no post-resolution needed, flush the raw slice as-is.
+	if config.GameEvents != nil {
+		geConfig := config.GameEvents
+		eventTypeNames := make(map[int32]string)
+		eventTypeFields := make(map[string][]string)
+		var events []GameEventData
+		var lastFlushTick uint32
+
+		parser.Callbacks.OnCMsgSource1LegacyGameEventList(func(m *dota.CMsgSource1LegacyGameEventList) error {
+			for _, d := range m.GetDescriptors() {
+				eventTypeNames[d.GetEventid()] = d.GetName()
+				fieldNames := make([]string, len(d.GetKeys()))
+				for i, k := range d.GetKeys() {
+					fieldNames[i] = k.GetName()
+				}
+				eventTypeFields[d.GetName()] = fieldNames
+			}
+			return nil
+		})
+
+		parser.Callbacks.OnCMsgSource1LegacyGameEvent(func(m *dota.CMsgSource1LegacyGameEvent) error {
+			eventName, ok := eventTypeNames[m.GetEventid()]
+			if !ok {
+				return nil
+			}
+			if geConfig.EventFilter != "" && !strings.Contains(eventName, geConfig.EventFilter) {
+				return nil
+			}
+
+			fields := make(map[string]interface{})
+			fieldNames := eventTypeFields[eventName]
+			for i, key := range m.GetKeys() {
+				fieldName := fmt.Sprintf("field_%d", i)
+				if i < len(fieldNames) {
+					fieldName = fieldNames[i]
+				}
+				switch key.GetType() {
+				case 1:
+					fields[fieldName] = key.GetValString()
+				case 2:
+					fields[fieldName] = key.GetValFloat()
+				case 3:
+					fields[fieldName] = key.GetValLong()
+				case 4:
+					fields[fieldName] = key.GetValShort()
+				case 5:
+					fields[fieldName] = key.GetValByte()
+				case 6:
+					fields[fieldName] = key.GetValBool()
+				case 7:
+					fields[fieldName] = key.GetValUint64()
+				}
+			}
+
+			events = append(events, GameEventData{Name: eventName, Tick: parser.Tick, NetTick: parser.NetTick, Fields: fields})
+
+			if shouldFlush(lastFlushTick, len(events)) {
+				emit("game_events", false, events)
+				events = nil
+				lastFlushTick = parser.Tick
+			}
+			return nil
+		})
+
+		defer func() {
+			if len(events) > 0 {
+				emit("game_events", true, events)
+			} else {
+				emit("game_events", true, []GameEventData{})
+			}
+		}()
+	}
+
+	// Attacks: GameTime is filled in with whatever gameStartTick the
+	// combat log collector (if also enabled) has resolved by this point;
+	// 0 if combat log isn't enabled, same as RunParse's dependency.
+	if config.Attacks != nil {
+		var events []AttackEvent
+		var lastFlushTick uint32
+
+		parser.Callbacks.OnCDOTAUserMsg_TE_Projectile(func(m *dota.CDOTAUserMsg_TE_Projectile) error {
+			if !m.GetIsAttack() {
+				return nil
+			}
+			sourceHandle := int64(m.GetSource())
+			targetHandle := int64(m.GetTarget())
+			events = append(events, AttackEvent{
+				Tick:            int(parser.Tick),
+				SourceIndex:     int(sourceHandle & 0x3FFF),
+				TargetIndex:     int(targetHandle & 0x3FFF),
+				SourceHandle:    sourceHandle,
+				TargetHandle:    targetHandle,
+				ProjectileSpeed: int(m.GetMoveSpeed()),
+				Dodgeable:       m.GetDodgeable(),
+				LaunchTick:      int(m.GetLaunchTick()),
+				GameTime:        TickToGameTime(parser.Tick, gameStartTick),
+				GameTimeStr:     FormatGameTime(TickToGameTime(parser.Tick, gameStartTick)),
+			})
+
+			if shouldFlush(lastFlushTick, len(events)) {
+				emit("attacks", false, events)
+				events = nil
+				lastFlushTick = parser.Tick
+			}
+			return nil
+		})
+
+		defer func() {
+			if len(events) > 0 {
+				emit("attacks", true, events)
+			} else {
+				emit("attacks", true, []AttackEvent{})
+			}
+		}()
+	}
+
+	// Entity deaths
+	if config.EntityDeaths != nil {
+		edConfig := config.EntityDeaths
+		var events []EntityDeath
+		var lastFlushTick uint32
+
+		parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+			if !op.Flag(manta.EntityOpDeleted) || e == nil {
+				return nil
+			}
+			className := e.GetClassName()
+			isHero := containsHeroClass(className)
+			isCreep := containsAny(className, "Creep_Lane", "BaseNPC_Creep_Lane", "Neutral", "NeutralCreep")
+			isBuilding := containsAny(className, "Tower", "Barracks", "Fort")
+			if containsAny(className, "CDOTA_Item", "CDOTA_Ability") {
+				return nil
+			}
+			if edConfig.HeroesOnly && !isHero {
+				return nil
+			} else if edConfig.CreepsOnly && !isCreep {
+				return nil
+			} else if !edConfig.HeroesOnly && !edConfig.CreepsOnly && !edConfig.IncludeCreeps && !isHero && !isBuilding {
+				return nil
+			}
+
+			var name string
+			if n, ok := e.GetString("m_iszUnitName"); ok {
+				name = n
+			} else if isHero {
+				name = heroNameFromClass(className)
+			}
+			var team int
+			if t, ok := e.GetInt32("m_iTeamNum"); ok {
+				team = int(t)
+			}
+			gt := TickToGameTime(parser.Tick, gameStartTick)
+			events = append(events, EntityDeath{
+				Tick:        int(parser.Tick),
+				EntityID:    int(e.GetIndex()),
+				ClassName:   className,
+				Name:        name,
+				Team:        team,
+				IsHero:      isHero,
+				IsCreep:     isCreep,
+				IsBuilding:  isBuilding,
+				GameTime:    gt,
+				GameTimeStr: FormatGameTime(gt),
+			})
+
+			if shouldFlush(lastFlushTick, len(events)) {
+				emit("entity_deaths", false, events)
+				events = nil
+				lastFlushTick = parser.Tick
+			}
+			return nil
+		})
+
+		defer func() {
+			if len(events) > 0 {
+				emit("entity_deaths", true, events)
+			} else {
+				emit("entity_deaths", true, []EntityDeath{})
+			}
+		}()
+	}
+
+	// Modifiers
+	if config.Modifiers != nil {
+		modConfig := config.Modifiers
+		var events []ModifierEntry
+		var lastFlushTick uint32
+
+		parser.OnModifierTableEntry(func(m *dota.CDOTAModifierBuffTableEntry) error {
+			isAura := m.GetAura()
+			if modConfig.AurasOnly && !isAura {
+				return nil
+			}
+			events = append(events, ModifierEntry{
+				Tick:          parser.Tick,
+				NetTick:       parser.NetTick,
+				Parent:        m.GetParent(),
+				Caster:        m.GetCaster(),
+				Ability:       m.GetAbility(),
+				ModifierClass: m.GetModifierClass(),
+				SerialNum:     m.GetSerialNum(),
+				Index:         m.GetIndex(),
+				CreationTime:  m.GetCreationTime(),
+				Duration:      m.GetDuration(),
+				StackCount:    m.GetStackCount(),
+				IsAura:        isAura,
+			})
+
+			if shouldFlush(lastFlushTick, len(events)) {
+				emit("modifiers", false, events)
+				events = nil
+				lastFlushTick = parser.Tick
+			}
+			return nil
+		})
+
+		defer func() {
+			if len(events) > 0 {
+				emit("modifiers", true, events)
+			} else {
+				emit("modifiers", true, []ModifierEntry{})
+			}
+		}()
+	}
+
+	if err := parser.Start(); err != nil {
+		return fail(fmt.Errorf("error parsing file: %w", err))
+	}
+
+	data, _ := json.Marshal(map[string]interface{}{"success": true})
+	return C.CString(string(data))
+}
+
+// containsHeroClass/heroNameFromClass/containsAny mirror the inline
+// class-name checks RunParse's CombatLog/EntityDeaths collectors do
+// directly, factored out here since ParseStream's collectors need the
+// same checks across OnEntity callbacks registered in separate blocks.
+func containsHeroClass(className string) bool {
+	return strings.Contains(className, "CDOTA_Unit_Hero_")
+}
+
+func heroNameFromClass(className string) string {
+	parts := strings.SplitN(className, "CDOTA_Unit_Hero_", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+	return "npc_dota_hero_" + strings.ToLower(parts[1])
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
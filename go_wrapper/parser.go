@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/dotabuff/manta"
@@ -76,10 +77,22 @@ func RunParse(filePath string, config ParseConfig) (*ParseResult, error) {
 	var parserInfoResult *ParserInfo
 	var attacksResult *AttacksResult
 	var entityDeathsResult *EntityDeathsResult
+	var aggregateStats *aggregateStatsCollector
+	var unitOrders *unitOrdersCollector
+	var chatResult *ChatMessagesResult
+	var mapEventsResult *MapEventsResult
+	var projectiles *projectilesCollector
+	var economy *economyCollector
+	var vision *visionCollector
+	var chatMessages []CombatLogChatEntry
 
 	// Hero level tracking for combat log enrichment
 	// Maps hero name (e.g., "npc_dota_hero_axe") to current level
 	heroLevels := make(map[string]int32)
+	// heroNameToPlayerID inverts the CombatLog collector's heroNameByPlayerID
+	// tracking, for the EconomyTimeline rollup below to bucket combat log
+	// entries (which only carry hero/unit name strings) by player slot.
+	heroNameToPlayerID := make(map[string]int32)
 
 	// Setup collectors based on config
 
@@ -155,6 +168,8 @@ func RunParse(filePath string, config ParseConfig) (*ParseResult, error) {
 	if config.CombatLog != nil {
 		combatLogRaw = make([]rawCombatLogEntry, 0)
 		clConfig := config.CombatLog
+		heroNameByPlayerID := make(map[int32]string)
+		heroTeamByPlayerID := make(map[int32]int32)
 
 		// Track hero levels from entity updates for combat log enrichment
 		parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
@@ -176,6 +191,41 @@ func RunParse(filePath string, config ParseConfig) (*ParseResult, error) {
 			if level, ok := e.GetInt32("m_iCurrentLevel"); ok && level > 0 {
 				heroLevels[heroName] = level
 			}
+			// Track player slot -> hero name/team, so the
+			// CombatLogChatEntry sender-resolution below (and any other
+			// CombatLog-pass consumer keyed by player slot) doesn't need
+			// its own separate hero entity walk.
+			if playerID, ok := e.GetInt32("m_iPlayerID"); ok {
+				heroNameByPlayerID[playerID] = heroName
+				heroNameToPlayerID[heroName] = playerID
+				if team, ok := e.GetInt32("m_iTeamNum"); ok {
+					heroTeamByPlayerID[playerID] = team
+				}
+			}
+			return nil
+		})
+
+		// CombatLogChatEntry capture (CUserMessageSayText2), gated on
+		// CombatLog being enabled per this request rather than its own
+		// config - see CombatLogChatEntry's doc comment for how this
+		// differs from the standalone Chat collector.
+		parser.Callbacks.OnCUserMessageSayText2(func(m *dota.CUserMessageSayText2) error {
+			slot := m.GetEntityindex()
+			senderName := heroNameByPlayerID[slot]
+			if clConfig.HeroesOnly && senderName == "" {
+				// No hero entity seen for this slot yet - treat as
+				// spectator/observer chat and drop it.
+				return nil
+			}
+			chatMessages = append(chatMessages, CombatLogChatEntry{
+				Tick:       parser.Tick,
+				SenderSlot: slot,
+				SenderName: senderName,
+				Team:       heroTeamByPlayerID[slot],
+				IsAllChat:  m.GetChat(),
+				Message:    m.GetParam2(),
+				Channel:    int32(m.GetChatGroupIndex()),
+			})
 			return nil
 		})
 
@@ -237,6 +287,25 @@ func RunParse(filePath string, config ParseConfig) (*ParseResult, error) {
 
 			return nil
 		})
+
+		// clConfig.IncludeUnitOrders auto-enables the UnitOrders collector
+		// without also requiring config.UnitOrders to be set - if the
+		// caller configured UnitOrders explicitly too, that config wins and
+		// this is a no-op (the "Unit orders collector" block below already
+		// registered it).
+		if clConfig.IncludeUnitOrders && config.UnitOrders == nil {
+			unitOrders = newUnitOrdersCollector(&UnitOrdersConfig{HeroesOnly: clConfig.HeroesOnly})
+			parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+				if e != nil {
+					unitOrders.onEntity(e)
+				}
+				return nil
+			})
+			parser.Callbacks.OnCDOTAUserMsg_SpectatorPlayerUnitOrders(func(m *dota.CDOTAUserMsg_SpectatorPlayerUnitOrders) error {
+				unitOrders.onSpectatorUnitOrders(parser, m)
+				return nil
+			})
+		}
 	}
 
 	// Entity snapshot collector
@@ -440,8 +509,34 @@ func RunParse(filePath string, config ParseConfig) (*ParseResult, error) {
 			return nil
 		})
 
+		// GameBuild comes from the header's real build number where
+		// available; network protocol is only a fallback.
+		parser.Callbacks.OnCDemoFileHeader(func(m *dota.CDemoFileHeader) error {
+			parserInfoResult.GameBuild = m.GetBuildNum()
+			return nil
+		})
 		parser.Callbacks.OnCSVCMsg_ServerInfo(func(m *dota.CSVCMsg_ServerInfo) error {
-			parserInfoResult.GameBuild = m.GetProtocol()
+			if parserInfoResult.GameBuild == 0 {
+				parserInfoResult.GameBuild = m.GetProtocol()
+			}
+			return nil
+		})
+	}
+
+	// Aggregate stats collector (per-hero combat log rollups)
+	if config.AggregateStats != nil && config.AggregateStats.Enabled {
+		aggregateStats = newAggregateStatsCollector()
+		parser.Callbacks.OnCMsgDOTACombatLogEntry(func(m *dota.CMsgDOTACombatLogEntry) error {
+			aggregateStats.onCombatLogEntry(parser, m)
+			return nil
+		})
+	}
+
+	// Unit orders collector
+	if config.UnitOrders != nil {
+		unitOrders = newUnitOrdersCollector(config.UnitOrders)
+		parser.Callbacks.OnCDOTAUserMsg_SpectatorPlayerUnitOrders(func(m *dota.CDOTAUserMsg_SpectatorPlayerUnitOrders) error {
+			unitOrders.onSpectatorUnitOrders(parser, m)
 			return nil
 		})
 	}
@@ -485,6 +580,170 @@ func RunParse(filePath string, config ParseConfig) (*ParseResult, error) {
 		})
 	}
 
+	// Chat messages collector - reuses ChatMessagesConfig/ChatMessagesResult
+	// and the same registerChatMessagesCallbacks RunChatMessagesParse uses,
+	// so a standalone ParseChatMessages call and this combined pass agree on
+	// filtering/shape.
+	if config.Chat != nil {
+		chatResult = &ChatMessagesResult{Messages: make([]ChatMessageEntry, 0)}
+		registerChatMessagesCallbacks(parser, *config.Chat, chatResult)
+	}
+
+	// Map events collector (location pings, map lines, minimap events,
+	// mini kill-cam info)
+	if config.MapEvents != nil {
+		meConfig := config.MapEvents
+		mapEventsResult = &MapEventsResult{Events: make([]MapEvent, 0)}
+
+		wantsKind := func(kind string) bool {
+			if len(meConfig.EventKinds) == 0 {
+				return true
+			}
+			for _, k := range meConfig.EventKinds {
+				if k == kind {
+					return true
+				}
+			}
+			return false
+		}
+		wantsSlot := func(slot int32) bool {
+			if len(meConfig.PlayerSlots) == 0 {
+				return true
+			}
+			for _, s := range meConfig.PlayerSlots {
+				if s == slot {
+					return true
+				}
+			}
+			return false
+		}
+		inWindow := func() bool {
+			tick := int(parser.Tick)
+			if tick < meConfig.StartTick {
+				return false
+			}
+			if meConfig.EndTick > 0 && tick > meConfig.EndTick {
+				return false
+			}
+			return true
+		}
+		appendEvent := func(ev MapEvent) {
+			if meConfig.MaxEvents > 0 && len(mapEventsResult.Events) >= meConfig.MaxEvents {
+				return
+			}
+			if !wantsKind(ev.Kind) || !wantsSlot(ev.PlayerSlot) || !inWindow() {
+				return
+			}
+			ev.Tick = int(parser.Tick)
+			mapEventsResult.Events = append(mapEventsResult.Events, ev)
+		}
+
+		parser.Callbacks.OnCDOTAUserMsg_LocationPing(func(m *dota.CDOTAUserMsg_LocationPing) error {
+			ev := MapEvent{
+				PlayerSlot: m.GetPlayerId(),
+				Kind:       "location_ping",
+				PingType:   int32(m.GetPingType()),
+			}
+			if loc := m.GetLocation(); loc != nil {
+				ev.X = loc.GetX()
+				ev.Y = loc.GetY()
+			}
+			if handle := int64(m.GetTarget()); handle != 0 {
+				ev.TargetHandle = handle
+				ev.TargetIndex = int(handle & 0x3FFF)
+			}
+			appendEvent(ev)
+			return nil
+		})
+
+		parser.Callbacks.OnCDOTAUserMsg_MapLine(func(m *dota.CDOTAUserMsg_MapLine) error {
+			ev := MapEvent{
+				PlayerSlot: m.GetPlayerId(),
+				Kind:       "map_line",
+			}
+			if points := m.GetPoints(); len(points) > 0 {
+				ev.X = points[0].GetX()
+				ev.Y = points[0].GetY()
+			}
+			appendEvent(ev)
+			return nil
+		})
+
+		parser.Callbacks.OnCDOTAUserMsg_MinimapEvent(func(m *dota.CDOTAUserMsg_MinimapEvent) error {
+			ev := MapEvent{
+				Kind:      "minimap_event",
+				EventType: m.GetEventType(),
+				X:         m.GetXcoord(),
+				Y:         m.GetYcoord(),
+			}
+			if handle := int64(m.GetEntity()); handle != 0 {
+				ev.TargetHandle = handle
+				ev.TargetIndex = int(handle & 0x3FFF)
+			}
+			appendEvent(ev)
+			return nil
+		})
+
+		// CDOTAUserMsg_MiniKillCamInfo's exact sub-fields aren't decoded
+		// here: it's a rarely-used broadcast-only message and this tree
+		// doesn't vendor the dota proto source to confirm its field
+		// names the way LocationPing/MapLine/MinimapEvent's are
+		// confirmed against other usages in this package (e.g.
+		// unit_orders.go's m.GetPosition().GetX()/GetY() for the shared
+		// CMsgVector shape). It's still recorded as a presence/timing
+		// event so callers know a kill-cam was broadcast at this tick.
+		parser.Callbacks.OnCDOTAUserMsg_MiniKillCamInfo(func(m *dota.CDOTAUserMsg_MiniKillCamInfo) error {
+			appendEvent(MapEvent{Kind: "minikillcam"})
+			return nil
+		})
+	}
+
+	// Projectiles collector (full create/destroy/dodge lifecycle)
+	if config.Projectiles != nil {
+		projectiles = newProjectilesCollector(config.Projectiles)
+
+		parser.Callbacks.OnCDOTAUserMsg_CreateLinearProjectile(func(m *dota.CDOTAUserMsg_CreateLinearProjectile) error {
+			projectiles.onCreate(parser, m)
+			return nil
+		})
+		parser.Callbacks.OnCDOTAUserMsg_DestroyLinearProjectile(func(m *dota.CDOTAUserMsg_DestroyLinearProjectile) error {
+			projectiles.onDestroy(parser, m)
+			return nil
+		})
+		parser.Callbacks.OnCDOTAUserMsg_DodgeTrackingProjectiles(func(m *dota.CDOTAUserMsg_DodgeTrackingProjectiles) error {
+			projectiles.onDodge(parser, m)
+			return nil
+		})
+		parser.Callbacks.OnCDOTAUserMsg_TE_Projectile(func(m *dota.CDOTAUserMsg_TE_Projectile) error {
+			projectiles.onAttack(parser, m)
+			return nil
+		})
+	}
+
+	// Economy collector (build order, skill order, periodic inventory/
+	// net-worth snapshots)
+	if config.Economy != nil && config.Economy.Enabled {
+		economy = newEconomyCollector(config.Economy)
+
+		parser.Callbacks.OnCMsgDOTACombatLogEntry(func(m *dota.CMsgDOTACombatLogEntry) error {
+			economy.onCombatLogEntry(parser, m)
+			return nil
+		})
+		parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+			economy.onEntity(parser, e)
+			return nil
+		})
+	}
+
+	// Vision collector (ward placement/expiration and team coverage)
+	if config.Vision != nil && config.Vision.Enabled {
+		vision = newVisionCollector(config.Vision)
+		parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+			vision.onEntity(parser, e, op)
+			return nil
+		})
+	}
+
 	// Entity deaths collector (tracks entity removals)
 	if config.EntityDeaths != nil {
 		entityDeathsConfig := config.EntityDeaths
@@ -616,6 +875,16 @@ func RunParse(filePath string, config ParseConfig) (*ParseResult, error) {
 	// Combat log: resolve names after parsing
 	if combatLogRaw != nil {
 		result.CombatLog = finalizeCombatLog(parser, combatLogRaw, gameStartTime, gameStartTick, config.CombatLog)
+
+		if config.CombatLog.IncludeEconomyTimeline {
+			result.EconomyTimeline = computeEconomyTimeline(result.CombatLog.Entries, heroNameToPlayerID, config.CombatLog.EconomyTimelineIntervalSeconds)
+		}
+	}
+
+	// Summary: per-hero/per-team rollups computed as a second pass over
+	// the just-finalized combat log.
+	if config.Summary != nil && config.Summary.Enabled {
+		result.Summary = buildSummary(parser, result.CombatLog, config.Summary)
 	}
 
 	// Entity snapshots
@@ -684,16 +953,71 @@ func RunParse(filePath string, config ParseConfig) (*ParseResult, error) {
 		result.EntityDeaths = entityDeathsResult
 	}
 
+	// Unit orders
+	if unitOrders != nil {
+		for i := range unitOrders.result.Events {
+			unitOrders.result.Events[i].GameTime = TickToGameTime(unitOrders.result.Events[i].Tick, gameStartTick)
+		}
+		unitOrders.result.TotalEvents = len(unitOrders.result.Events)
+		result.UnitOrders = unitOrders.result
+	}
+
+	// Aggregate stats
+	if aggregateStats != nil {
+		result.AggregateStats = aggregateStats.result
+	}
+
+	// Chat messages
+	if chatResult != nil {
+		chatResult.Success = true
+		result.Chat = chatResult
+	}
+
+	// Projectiles
+	if projectiles != nil {
+		result.Projectiles = projectiles.finalize(gameStartTick)
+	}
+
+	// Economy
+	if economy != nil {
+		economy.result.Success = true
+		result.Economy = economy.result
+	}
+
+	// Vision
+	if vision != nil {
+		result.Vision = vision.finalize(gameStartTick)
+	}
+
+	// ChatMessages (captured during the CombatLog pass)
+	if chatMessages != nil {
+		for i := range chatMessages {
+			chatMessages[i].GameTime = TickToGameTime(chatMessages[i].Tick, gameStartTick)
+		}
+		result.ChatMessages = chatMessages
+	}
+
+	// Map events
+	if mapEventsResult != nil {
+		for i := range mapEventsResult.Events {
+			gt := TickToGameTime(uint32(mapEventsResult.Events[i].Tick), gameStartTick)
+			mapEventsResult.Events[i].GameTime = gt
+			mapEventsResult.Events[i].GameTimeStr = FormatGameTime(gt)
+		}
+		mapEventsResult.TotalEvents = len(mapEventsResult.Events)
+		result.MapEvents = mapEventsResult
+	}
+
 	return result, nil
 }
 
 // rawCombatLogEntry stores combat log data before name resolution
 type rawCombatLogEntry struct {
-	tick             uint32
-	netTick          uint32
-	msg              *dota.CMsgDOTACombatLogEntry
-	attackerHeroLevel int32  // Captured from entity state at this tick
-	targetHeroLevel   int32  // Captured from entity state at this tick
+	tick              uint32
+	netTick           uint32
+	msg               *dota.CMsgDOTACombatLogEntry
+	attackerHeroLevel int32 // Captured from entity state at this tick
+	targetHeroLevel   int32 // Captured from entity state at this tick
 }
 
 // isHeroName checks if a name string indicates a hero
@@ -866,13 +1190,88 @@ func finalizeCombatLog(parser *manta.Parser, rawEntries []rawCombatLogEntry, gam
 	}
 
 	result.TotalEntries = len(result.Entries)
+	result.Stats = computeCombatLogStats(result.Entries)
 	return result
 }
 
+// computeEconomyTimeline buckets already-finalized combat log entries into
+// fixed-width game-time intervals per player slot. heroNameToPlayerID
+// resolves AttackerName/TargetName (the only participant identifiers combat
+// log entries carry) to the player slot EconomyTimeline is keyed by -
+// entries for a hero whose player slot hasn't been seen yet are dropped,
+// the same tradeoff the CombatLog collector's other player-slot lookups
+// (e.g. CombatLogChatEntry's sender resolution) already make.
+func computeEconomyTimeline(entries []CombatLogEntry, heroNameToPlayerID map[string]int32, intervalSeconds int) map[int][]EconomySample {
+	if intervalSeconds <= 0 {
+		intervalSeconds = 60
+	}
+
+	type bucketKey struct {
+		slot  int32
+		index int
+	}
+	buckets := make(map[bucketKey]*EconomySample)
+	order := make([]bucketKey, 0)
+
+	fold := func(slot int32, entry CombatLogEntry, heroLevel int32) {
+		index := int(entry.GameTime) / intervalSeconds
+		if entry.GameTime < 0 {
+			index--
+		}
+		key := bucketKey{slot: slot, index: index}
+		sample, ok := buckets[key]
+		if !ok {
+			sample = &EconomySample{GameTime: float32(index * intervalSeconds)}
+			buckets[key] = sample
+			order = append(order, key)
+		}
+		sample.Tick = entry.Tick
+		if entry.Networth > sample.NetWorth {
+			sample.NetWorth = entry.Networth
+		}
+		if entry.GPM != 0 {
+			sample.GPM = entry.GPM
+		}
+		if entry.XPM != 0 {
+			sample.XPM = entry.XPM
+		}
+		if entry.LastHits > sample.LastHits {
+			sample.LastHits = entry.LastHits
+		}
+		if heroLevel != 0 {
+			sample.HeroLevel = heroLevel
+		}
+	}
+
+	for _, entry := range entries {
+		if slot, ok := heroNameToPlayerID[entry.AttackerName]; ok {
+			fold(slot, entry, entry.AttackerHeroLevel)
+		}
+		if entry.TargetName != entry.AttackerName {
+			if slot, ok := heroNameToPlayerID[entry.TargetName]; ok {
+				fold(slot, entry, entry.TargetHeroLevel)
+			}
+		}
+	}
+
+	timeline := make(map[int][]EconomySample)
+	for _, key := range order {
+		slot := int(key.slot)
+		timeline[slot] = append(timeline[slot], *buckets[key])
+	}
+	for slot := range timeline {
+		sort.Slice(timeline[slot], func(i, j int) bool {
+			return timeline[slot][i].GameTime < timeline[slot][j].GameTime
+		})
+	}
+	return timeline
+}
+
 // entityCollectorState manages entity snapshot collection during parsing
 type entityCollectorState struct {
 	config          *EntityParseConfig
 	snapshots       []EntitySnapshot
+	streamedCount   int // snapshots handed to config.SnapshotSink instead of retained, when streaming
 	lastCaptureTick uint32
 	gameStartTime   float32
 	gameStartTick   uint32
@@ -957,7 +1356,14 @@ func setupEntityCollector(parser *manta.Parser, state *entityCollectorState) {
 			snapshot := captureEntitySnapshot(parser, config, state.gameStartTime, state.gameStartTick)
 			// Only add snapshot if it has heroes
 			if len(snapshot.Heroes) > 0 {
-				state.snapshots = append(state.snapshots, snapshot)
+				if config.SnapshotSink != nil {
+					if err := config.SnapshotSink(snapshot); err != nil {
+						return err
+					}
+					state.streamedCount++
+				} else {
+					state.snapshots = append(state.snapshots, snapshot)
+				}
 				state.lastCaptureTick = currentTick
 			}
 		}
@@ -999,6 +1405,23 @@ func captureEntitySnapshot(parser *manta.Parser, config *EntityParseConfig, game
 
 // finalizeEntitySnapshots builds the final entity result
 func finalizeEntitySnapshots(state *entityCollectorState, totalTicks uint32) *EntityParseResult {
+	if state.config.SnapshotSink != nil {
+		// Snapshots were streamed out as they were captured rather than
+		// retained, so there's nothing here to rewrite GameTime on -
+		// instead, give the sink's owner one shot at the now-final
+		// gameStartTick to correct any pre-horn GameTime values itself.
+		if state.config.SnapshotSinkFinalize != nil {
+			state.config.SnapshotSinkFinalize(state.gameStartTick)
+		}
+		return &EntityParseResult{
+			Snapshots:     make([]EntitySnapshot, 0),
+			Success:       true,
+			TotalTicks:    totalTicks,
+			SnapshotCount: state.streamedCount,
+			GameStartTick: state.gameStartTick,
+		}
+	}
+
 	// Post-process: recalculate game_time for all snapshots now that we know gameStartTick
 	// This fixes pre-horn snapshots that were captured before gameStartTick was known
 	for i := range state.snapshots {
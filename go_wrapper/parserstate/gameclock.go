@@ -0,0 +1,308 @@
+// Package parserstate holds small, dependency-light helpers that derive
+// cross-cutting replay state (right now just the game clock) from a
+// *manta.Parser, for reuse across the wrapper's various parse entrypoints.
+// Like the filter package, it only depends on manta itself, not
+// manta_wrapper, so manta_wrapper can import it without a cycle.
+package parserstate
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dotabuff/manta"
+)
+
+// ticksPerSecond is Source 2's fixed tick rate, the same constant
+// time_utils.go's loose FormatGameTime/GameTimeToTick/TickToGameTime/
+// TickToReplayTime helpers hard-code. It's duplicated here rather than
+// imported, since this package deliberately depends on nothing but manta
+// itself (see the package doc comment).
+const ticksPerSecond float32 = 30.0
+
+// gameStateNames mirrors the gameStateNames table in the main package's
+// game_time_tracker.go (same DOTA_GAMERULES_STATE_* enum), duplicated here
+// for the same dependency-boundary reason as ticksPerSecond.
+var gameStateNames = map[int32]string{
+	0: "init",
+	1: "wait_for_players",
+	2: "hero_selection",
+	3: "strategy_time",
+	4: "pregame",
+	5: "inprogress",
+	6: "postgame",
+	7: "disconnect",
+}
+
+func gameStateName(state int32) string {
+	if name, ok := gameStateNames[state]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown_%d", state)
+}
+
+// clockSample is one observed (tick, gameTime) pair, recorded every time
+// CDOTAGamerulesProxy updates. Ticks only ever increase within a replay, so
+// Samples is kept sorted by Tick as it's built, letting GameTime interpolate
+// between the two samples bracketing a queried tick instead of just
+// returning the most recently observed value. matchTime, gameState, and
+// paused are step functions rather than interpolated: AtTick/Phase/IsPaused
+// look up the most recent sample at or before the queried tick instead of
+// blending between two.
+type clockSample struct {
+	tick      uint32
+	gameTime  float32
+	matchTime float32
+	gameState int32
+	paused    bool
+}
+
+// GameClock is the accurate replacement for the GAME_STATE==5 combat-log
+// heuristic RunCombatLogParse/RunAttacksParse used to derive game start: it
+// reads CDOTAGamerules.m_flPreGameStartTime/m_flGameStartTime/
+// m_flGameEndTime/m_fGameTime/m_iGameMode directly off CDOTAGamerulesProxy,
+// which is present and correct even in replays (spectator demos, tournament
+// clients, early-stopped parses) where the combat log never emits that
+// state transition.
+type GameClock struct {
+	samples []clockSample
+
+	GameMode int32
+
+	// StartTick/PreGameStartTick/EndTick are the first tick at which
+	// m_fGameTime - m_flGameStartTime/m_flPreGameStartTime/m_flGameEndTime
+	// was observed to have crossed zero. They stay 0 if the proxy entity
+	// never reports that stage (e.g. EndTick for a replay that stops
+	// before the game ends).
+	StartTick        uint32
+	PreGameStartTick uint32
+	EndTick          uint32
+}
+
+// NewGameClock registers the OnEntity watch that keeps the clock current.
+// Call once per parser, before parser.Start().
+func NewGameClock(parser *manta.Parser) *GameClock {
+	clock := &GameClock{}
+
+	var gameTime, preGameStartTime, gameStartTime, gameEndTime float32
+	var gameState int32
+	var paused bool
+	parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+		if e == nil || e.GetClassName() != "CDOTAGamerulesProxy" {
+			return nil
+		}
+
+		if v, ok := e.GetFloat32("m_pGameRules.m_fGameTime"); ok {
+			gameTime = v
+		}
+		if v, ok := e.GetFloat32("m_pGameRules.m_flPreGameStartTime"); ok {
+			preGameStartTime = v
+		}
+		if v, ok := e.GetFloat32("m_pGameRules.m_flGameStartTime"); ok {
+			gameStartTime = v
+		}
+		if v, ok := e.GetFloat32("m_pGameRules.m_flGameEndTime"); ok {
+			gameEndTime = v
+		}
+		if v, ok := e.GetInt32("m_pGameRules.m_iGameMode"); ok {
+			clock.GameMode = v
+		}
+		if v, ok := e.GetInt32("m_pGameRules.m_iGameState"); ok {
+			gameState = v
+		}
+		if v, ok := e.GetBool("m_pGameRules.m_bGamePaused"); ok {
+			paused = v
+		}
+
+		tick := parser.Tick
+		if clock.PreGameStartTick == 0 && preGameStartTime > 0 && gameTime >= preGameStartTime {
+			clock.PreGameStartTick = tick
+		}
+		if clock.StartTick == 0 && gameStartTime > 0 && gameTime >= gameStartTime {
+			clock.StartTick = tick
+		}
+		if clock.EndTick == 0 && gameEndTime > 0 && gameTime >= gameEndTime {
+			clock.EndTick = tick
+		}
+
+		clock.record(tick, gameTime-gameStartTime, gameTime-preGameStartTime, gameState, paused)
+		return nil
+	})
+
+	return clock
+}
+
+// record appends a sample, skipping ticks already seen (OnEntity can fire
+// more than once per tick for the same entity).
+func (c *GameClock) record(tick uint32, gameTime, matchTime float32, gameState int32, paused bool) {
+	sample := clockSample{tick: tick, gameTime: gameTime, matchTime: matchTime, gameState: gameState, paused: paused}
+	if n := len(c.samples); n > 0 && c.samples[n-1].tick == tick {
+		c.samples[n-1] = sample
+		return
+	}
+	c.samples = append(c.samples, sample)
+}
+
+// GameTime returns the interpolated game-clock second at tick, linearly
+// interpolating between the two recorded samples bracketing it. Ticks
+// before the first sample clamp to the first sample's value; ticks after
+// the last sample clamp to the last.
+func (c *GameClock) GameTime(tick uint32) float32 {
+	n := len(c.samples)
+	if n == 0 {
+		return 0
+	}
+
+	i := sort.Search(n, func(i int) bool { return c.samples[i].tick >= tick })
+	if i == 0 {
+		return c.samples[0].gameTime
+	}
+	if i == n {
+		return c.samples[n-1].gameTime
+	}
+	if c.samples[i].tick == tick {
+		return c.samples[i].gameTime
+	}
+
+	lo, hi := c.samples[i-1], c.samples[i]
+	if hi.tick == lo.tick {
+		return lo.gameTime
+	}
+	frac := float32(tick-lo.tick) / float32(hi.tick-lo.tick)
+	return lo.gameTime + frac*(hi.gameTime-lo.gameTime)
+}
+
+// IsPreGame reports whether tick falls in the pre-game strategy/pick phase
+// (before the horn, after picks lock in).
+func (c *GameClock) IsPreGame(tick uint32) bool {
+	if c.PreGameStartTick == 0 {
+		return false
+	}
+	if c.StartTick != 0 {
+		return tick >= c.PreGameStartTick && tick < c.StartTick
+	}
+	return tick >= c.PreGameStartTick
+}
+
+// IsPostGame reports whether tick falls after the game has ended. Always
+// false if the replay never reached (or the parse never observed) that
+// state.
+func (c *GameClock) IsPostGame(tick uint32) bool {
+	return c.EndTick != 0 && tick >= c.EndTick
+}
+
+// stepAt returns the most recently recorded sample at or before tick, for
+// the step-function fields (matchTime, gameState, paused) that don't make
+// sense to interpolate the way GameTime does. Ticks before the first
+// sample clamp to the first sample.
+func (c *GameClock) stepAt(tick uint32) clockSample {
+	n := len(c.samples)
+	if n == 0 {
+		return clockSample{}
+	}
+	i := sort.Search(n, func(i int) bool { return c.samples[i].tick > tick })
+	if i == 0 {
+		return c.samples[0]
+	}
+	return c.samples[i-1]
+}
+
+// MatchTime returns seconds since strategy time started
+// (m_fGameTime - m_flPreGameStartTime) at tick, so unlike GameTime it stays
+// non-negative for the whole match including the draft.
+func (c *GameClock) MatchTime(tick uint32) float32 {
+	return c.stepAt(tick).matchTime
+}
+
+// Phase returns the resolved DOTA_GAMERULES_STATE_* name observed at tick
+// (see gameStateNames), e.g. "strategy_time", "inprogress", "postgame".
+func (c *GameClock) Phase(tick uint32) string {
+	return gameStateName(c.stepAt(tick).gameState)
+}
+
+// IsPaused reports whether m_bGamePaused was set the last time the proxy
+// entity updated at or before tick.
+func (c *GameClock) IsPaused(tick uint32) bool {
+	return c.stepAt(tick).paused
+}
+
+// ReplayTime returns seconds since the start of the replay file at tick,
+// independent of game phase - unlike GameTime/MatchTime this never goes
+// negative and isn't affected by pauses or the pre-game draft.
+func (c *GameClock) ReplayTime(tick uint32) float32 {
+	return float32(tick) / ticksPerSecond
+}
+
+// FormatHHMMSS renders GameTime(tick) the same way the main package's
+// FormatGameTime does: "-1:30" during pre-horn strategy time, "3:07" once
+// the horn has sounded, never adjusted for pauses since GameTime itself
+// already reflects them (see TickAtGameTime).
+func (c *GameClock) FormatHHMMSS(tick uint32) string {
+	seconds := c.GameTime(tick)
+	negative := seconds < 0
+	abs := seconds
+	if negative {
+		abs = -seconds
+	}
+	mins := int(abs) / 60
+	secs := int(abs) % 60
+	if negative {
+		return fmt.Sprintf("-%d:%02d", mins, secs)
+	}
+	return fmt.Sprintf("%d:%02d", mins, secs)
+}
+
+// TickAtGameTime returns the tick at which GameTime first reached
+// gameTime, linearly interpolating between recorded samples the same way
+// GameTime interpolates in the other direction. Samples flatten out during
+// a pause (gameTime stops advancing while tick keeps counting), so this
+// naturally skips the paused span instead of assuming a constant
+// 30-ticks-per-second rate across it the way a naive
+// gameStartTick + gameTime*TicksPerSecond conversion would.
+func (c *GameClock) TickAtGameTime(gameTime float32) uint32 {
+	n := len(c.samples)
+	if n == 0 {
+		return 0
+	}
+
+	i := sort.Search(n, func(i int) bool { return c.samples[i].gameTime >= gameTime })
+	if i == 0 {
+		return c.samples[0].tick
+	}
+	if i == n {
+		return c.samples[n-1].tick
+	}
+	if c.samples[i].gameTime == gameTime {
+		return c.samples[i].tick
+	}
+
+	lo, hi := c.samples[i-1], c.samples[i]
+	if hi.gameTime == lo.gameTime {
+		return lo.tick
+	}
+	frac := (gameTime - lo.gameTime) / (hi.gameTime - lo.gameTime)
+	return lo.tick + uint32(frac*float32(hi.tick-lo.tick))
+}
+
+// GameClockSnapshot bundles every per-tick signal GameClock can answer,
+// for callers (like AtTick) that want the whole picture at once instead of
+// calling GameTime/MatchTime/Phase/IsPaused/ReplayTime separately.
+type GameClockSnapshot struct {
+	Tick       uint32
+	GameTime   float32
+	MatchTime  float32
+	ReplayTime float32
+	Phase      string
+	IsPaused   bool
+}
+
+// AtTick returns every GameClock signal for tick in one call.
+func (c *GameClock) AtTick(tick uint32) GameClockSnapshot {
+	return GameClockSnapshot{
+		Tick:       tick,
+		GameTime:   c.GameTime(tick),
+		MatchTime:  c.MatchTime(tick),
+		ReplayTime: c.ReplayTime(tick),
+		Phase:      c.Phase(tick),
+		IsPaused:   c.IsPaused(tick),
+	}
+}
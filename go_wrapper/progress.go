@@ -0,0 +1,187 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ProgressSlot is the shared-memory-style progress record ReadProgress
+// polls. Invoking a stored C function pointer back into Python is fragile
+// (it has to round-trip through the GIL on every report from whatever Go
+// goroutine is driving parser.Start()), so instead of a push callback, a
+// long-running parse periodically writes into a slot here and the Python
+// side polls ReadProgress from its own background thread - the same
+// "poll a result, don't wait for a push" shape NextCombatLogBatch and
+// PollParseHandle already use for their async results.
+type ProgressSlot struct {
+	Tick    uint64 `json:"tick"`
+	Total   uint64 `json:"total"`
+	Emitted uint64 `json:"emitted"`
+	Done    uint32 `json:"done"`
+	CbID    uint64 `json:"cb_id,omitempty"`
+}
+
+type progressSlotEntry struct {
+	mu   sync.Mutex
+	slot ProgressSlot
+}
+
+var (
+	progressSlots      sync.Map // map[uint64]*progressSlotEntry
+	nextProgressSlotID uint64
+)
+
+// progressReportInterval throttles how often a parse loop writes into its
+// slot - reporting on every tick would mean a mutex lock per tick for no
+// visible benefit to a polling tqdm bar. This stands in for the time.Tick
+// channel the request describes without spinning up a ticker goroutine per
+// slot: each reporter just compares against its own last-report timestamp.
+const progressReportInterval = 250 * time.Millisecond
+
+// progressReporter is embedded by a parse loop that was handed a
+// ProgressSlotID, throttling its calls to reportProgress to roughly
+// progressReportInterval.
+type progressReporter struct {
+	slotID     uint64
+	lastReport time.Time
+}
+
+// newProgressReporter returns a no-op reporter when slotID is 0 (the
+// "progress reporting not requested" default), so call sites can use it
+// unconditionally instead of branching on slotID everywhere.
+func newProgressReporter(slotID uint64, total uint64) *progressReporter {
+	if slotID != 0 {
+		setProgressTotal(slotID, total)
+	}
+	return &progressReporter{slotID: slotID}
+}
+
+func (r *progressReporter) report(tick, emitted uint64) {
+	if r.slotID == 0 {
+		return
+	}
+	now := time.Now()
+	if !r.lastReport.IsZero() && now.Sub(r.lastReport) < progressReportInterval {
+		return
+	}
+	r.lastReport = now
+	updateProgressSlot(r.slotID, tick, emitted)
+}
+
+func (r *progressReporter) finish(tick, emitted uint64) {
+	if r.slotID == 0 {
+		return
+	}
+	updateProgressSlot(r.slotID, tick, emitted)
+	finishProgressSlot(r.slotID)
+}
+
+func newProgressSlot() uint64 {
+	id := atomic.AddUint64(&nextProgressSlotID, 1)
+	progressSlots.Store(id, &progressSlotEntry{})
+	return id
+}
+
+func setProgressTotal(slotID uint64, total uint64) {
+	v, ok := progressSlots.Load(slotID)
+	if !ok {
+		return
+	}
+	e := v.(*progressSlotEntry)
+	e.mu.Lock()
+	e.slot.Total = total
+	e.mu.Unlock()
+}
+
+func updateProgressSlot(slotID, tick, emitted uint64) {
+	v, ok := progressSlots.Load(slotID)
+	if !ok {
+		return
+	}
+	e := v.(*progressSlotEntry)
+	e.mu.Lock()
+	e.slot.Tick = tick
+	e.slot.Emitted = emitted
+	e.mu.Unlock()
+}
+
+func finishProgressSlot(slotID uint64) {
+	v, ok := progressSlots.Load(slotID)
+	if !ok {
+		return
+	}
+	e := v.(*progressSlotEntry)
+	e.mu.Lock()
+	e.slot.Done = 1
+	e.mu.Unlock()
+}
+
+// AllocateProgressSlot reserves a new progress slot and returns its ID.
+// Pass the returned slot_id as progress_slot_id in a combat log / parser
+// info config to have that parse report into it, then poll it with
+// ReadProgress.
+//
+//export AllocateProgressSlot
+func AllocateProgressSlot() *C.char {
+	id := newProgressSlot()
+	data, _ := json.Marshal(map[string]interface{}{"slot_id": id, "success": true})
+	return C.CString(string(data))
+}
+
+// RegisterProgressCallback associates a Python-side callable (identified
+// by the opaque cbID the Python wrapper already tracks its own callback
+// registry by) with an existing progress slot, so ReadProgress can echo
+// cb_id back and the polling thread knows which tqdm bar to drive without
+// a separate side-channel. It does not invoke anything in Go - the actual
+// callback invocation stays entirely on the Python side of the poll loop.
+//
+//export RegisterProgressCallback
+func RegisterProgressCallback(slotID C.ulonglong, cbID C.ulonglong) *C.char {
+	id := uint64(slotID)
+	v, ok := progressSlots.Load(id)
+	if !ok {
+		data, _ := json.Marshal(map[string]interface{}{"success": false, "error": "unknown progress slot"})
+		return C.CString(string(data))
+	}
+	e := v.(*progressSlotEntry)
+	e.mu.Lock()
+	e.slot.CbID = uint64(cbID)
+	e.mu.Unlock()
+
+	data, _ := json.Marshal(map[string]interface{}{"success": true})
+	return C.CString(string(data))
+}
+
+// ReadProgress returns the current {tick, total, emitted, done} for
+// slotID, for a Python background thread to poll and drive a tqdm-style
+// bar from. Safe to call repeatedly, including after Done is set.
+//
+//export ReadProgress
+func ReadProgress(slotID C.ulonglong) *C.char {
+	id := uint64(slotID)
+	v, ok := progressSlots.Load(id)
+	if !ok {
+		data, _ := json.Marshal(map[string]interface{}{"success": false, "error": "unknown progress slot"})
+		return C.CString(string(data))
+	}
+	e := v.(*progressSlotEntry)
+	e.mu.Lock()
+	snapshot := e.slot
+	e.mu.Unlock()
+
+	data, err := json.Marshal(struct {
+		ProgressSlot
+		Success bool `json:"success"`
+	}{ProgressSlot: snapshot, Success: true})
+	if err != nil {
+		data, _ = json.Marshal(map[string]interface{}{"success": false, "error": err.Error()})
+	}
+	return C.CString(string(data))
+}
@@ -0,0 +1,208 @@
+package main
+
+// This file's field access against CDOTAUserMsg_CreateLinearProjectile/
+// DestroyLinearProjectile/DodgeTrackingProjectiles (GetProjectileId,
+// GetOrigin/GetVelocity, GetAbilityHandle, GetEntindex/GetProjectileIds)
+// follows the public Source 2 dota_usermessages.proto schema for this
+// message family; this tree doesn't vendor github.com/dotabuff/manta's
+// generated dota package to cross-check the exact field names against, the
+// same gap flagged for CDOTAUserMsg_MiniKillCamInfo in the map events
+// collector (parser.go). TE_Projectile's fields (Source/Target/IsAttack/
+// Dodgeable) are the existing, already-used-elsewhere Attacks collector
+// shape and aren't in question.
+import (
+	"math"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// projectileTrack is the in-flight state newProjectilesCollector keeps
+// between a CreateLinearProjectile and its matching Destroy/Dodge, keyed
+// by ProjectileId. Distance/travel-time can't be read directly off the
+// destroy/dodge messages - per dota_usermessages.proto, neither carries
+// an impact position - so TotalDistance is estimated as launch speed
+// (the magnitude of Create's velocity vector) times elapsed flight time,
+// which holds exactly for a *linear* projectile (constant velocity until
+// impact/expiry/dodge), the case this collector is scoped to.
+type projectileTrack struct {
+	sourceIndex  int
+	sourceHandle int64
+	launchTick   uint32
+	launchX      float32
+	launchY      float32
+	speed        float32
+	dodgeable    bool
+	abilityIndex int
+}
+
+// projectilesCollector accumulates ProjectilesResult across the four
+// callbacks RunParse registers for config.Projectiles.
+type projectilesCollector struct {
+	config *ProjectilesConfig
+	result *ProjectilesResult
+	tracks map[uint32]*projectileTrack
+}
+
+func newProjectilesCollector(config *ProjectilesConfig) *projectilesCollector {
+	return &projectilesCollector{
+		config: config,
+		result: &ProjectilesResult{Events: make([]ProjectileEvent, 0)},
+		tracks: make(map[uint32]*projectileTrack),
+	}
+}
+
+func (c *projectilesCollector) full() bool {
+	return c.config.MaxEvents > 0 && len(c.result.Events) >= c.config.MaxEvents
+}
+
+func (c *projectilesCollector) append(tick uint32, ev ProjectileEvent) {
+	if c.full() {
+		return
+	}
+	ev.Tick = int(tick)
+	c.result.Events = append(c.result.Events, ev)
+}
+
+// onCreate handles CreateLinearProjectile, recording the track and
+// emitting a "created" event.
+func (c *projectilesCollector) onCreate(parser *manta.Parser, m *dota.CDOTAUserMsg_CreateLinearProjectile) {
+	if c.full() {
+		return
+	}
+
+	id := m.GetProjectileId()
+	sourceHandle := int64(m.GetEntindex())
+	sourceIndex := int(sourceHandle & 0x3FFF)
+
+	var launchX, launchY, speed float32
+	if origin := m.GetOrigin(); origin != nil {
+		launchX, launchY = origin.GetX(), origin.GetY()
+	}
+	if vel := m.GetVelocity(); vel != nil {
+		speed = vectorMagnitude(vel.GetX(), vel.GetY(), vel.GetZ())
+	}
+
+	abilityIndex := 0
+	if handle := int64(m.GetAbilityHandle()); handle != 0 {
+		abilityIndex = int(handle & 0x3FFF)
+	}
+
+	c.tracks[id] = &projectileTrack{
+		sourceIndex:  sourceIndex,
+		sourceHandle: sourceHandle,
+		launchTick:   parser.Tick,
+		launchX:      launchX,
+		launchY:      launchY,
+		speed:        speed,
+		abilityIndex: abilityIndex,
+	}
+
+	c.append(parser.Tick, ProjectileEvent{
+		ProjectileID:   id,
+		LifecycleStage: "created",
+		SourceIndex:    sourceIndex,
+		SourceHandle:   sourceHandle,
+		LaunchX:        launchX,
+		LaunchY:        launchY,
+		AbilityIndex:   abilityIndex,
+	})
+}
+
+// onDestroy handles DestroyLinearProjectile.
+func (c *projectilesCollector) onDestroy(parser *manta.Parser, m *dota.CDOTAUserMsg_DestroyLinearProjectile) {
+	if c.full() {
+		return
+	}
+	id := m.GetProjectileId()
+	track, ok := c.tracks[id]
+	if !ok {
+		return
+	}
+	delete(c.tracks, id)
+
+	c.append(parser.Tick, ProjectileEvent{
+		ProjectileID:   id,
+		LifecycleStage: "destroyed",
+		SourceIndex:    track.sourceIndex,
+		SourceHandle:   track.sourceHandle,
+		LaunchX:        track.launchX,
+		LaunchY:        track.launchY,
+		TotalDistance:  track.speed * float32(parser.Tick-track.launchTick) / TicksPerSecond,
+		AbilityIndex:   track.abilityIndex,
+	})
+}
+
+// onDodge handles DodgeTrackingProjectiles - one message can name several
+// dodged projectile IDs at once (e.g. a single disjoint wiping out
+// multiple tracked projectiles targeting the same dodging unit).
+func (c *projectilesCollector) onDodge(parser *manta.Parser, m *dota.CDOTAUserMsg_DodgeTrackingProjectiles) {
+	dodgerHandle := int64(m.GetEntindex())
+	dodgerIndex := int(dodgerHandle & 0x3FFF)
+
+	for _, id := range m.GetProjectileIds() {
+		if c.full() {
+			return
+		}
+		track, ok := c.tracks[id]
+		if !ok {
+			continue
+		}
+		delete(c.tracks, id)
+
+		c.append(parser.Tick, ProjectileEvent{
+			ProjectileID:   id,
+			LifecycleStage: "dodged",
+			SourceIndex:    track.sourceIndex,
+			SourceHandle:   track.sourceHandle,
+			TargetIndex:    dodgerIndex,
+			TargetHandle:   dodgerHandle,
+			LaunchX:        track.launchX,
+			LaunchY:        track.launchY,
+			TotalDistance:  track.speed * float32(parser.Tick-track.launchTick) / TicksPerSecond,
+			AbilityIndex:   track.abilityIndex,
+		})
+	}
+}
+
+// onAttack handles TE_Projectile, the attack-projectile family the
+// existing Attacks collector also reads. TE_Projectile carries no
+// projectile ID to correlate a later destroy/dodge against, so it's
+// recorded as a standalone "attack" event rather than fed into the
+// create/destroy/dodge track map.
+func (c *projectilesCollector) onAttack(parser *manta.Parser, m *dota.CDOTAUserMsg_TE_Projectile) {
+	if !m.GetIsAttack() || c.full() {
+		return
+	}
+	sourceHandle := int64(m.GetSource())
+	targetHandle := int64(m.GetTarget())
+
+	c.append(parser.Tick, ProjectileEvent{
+		LifecycleStage: "attack",
+		SourceIndex:    int(sourceHandle & 0x3FFF),
+		TargetIndex:    int(targetHandle & 0x3FFF),
+		SourceHandle:   sourceHandle,
+		TargetHandle:   targetHandle,
+		Dodgeable:      m.GetDodgeable(),
+	})
+}
+
+// finalize fills in GameTime/GameTimeStr using gameStartTick, the same
+// "known only once parsing finishes" dependency Attacks/EntityDeaths
+// already have in RunParse.
+func (c *projectilesCollector) finalize(gameStartTick uint32) *ProjectilesResult {
+	for i := range c.result.Events {
+		gt := TickToGameTime(uint32(c.result.Events[i].Tick), gameStartTick)
+		c.result.Events[i].GameTime = gt
+		c.result.Events[i].GameTimeStr = FormatGameTime(gt)
+	}
+	c.result.TotalEvents = len(c.result.Events)
+	return c.result
+}
+
+// vectorMagnitude is sqrt(x^2+y^2+z^2), used to turn Create's velocity
+// vector into a scalar launch speed.
+func vectorMagnitude(x, y, z float32) float32 {
+	sq := float64(x)*float64(x) + float64(y)*float64(y) + float64(z)*float64(z)
+	return float32(math.Sqrt(sq))
+}
@@ -0,0 +1,176 @@
+package main
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+
+typedef void (*manta_event_callback)(const char*);
+
+static inline void manta_call_event_callback(manta_event_callback cb, const char* data) {
+	cb(data);
+}
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// registeredCallbacks holds the Python-side function pointers RegisterCallback
+// wires in, keyed by event name ("header", "file_info", "chat", "combat_log",
+// "tick", or "entity_snapshot@N" for an N-tick sampling interval).
+var (
+	registeredCallbacksMu sync.Mutex
+	registeredCallbacks   = make(map[string]C.manta_event_callback)
+)
+
+// pushEvent is the generic payload ParseStreaming hands back through every
+// registered callback; Data varies per event name the same way StreamEvent's
+// does in stream.go.
+type pushEvent struct {
+	Name string      `json:"name"`
+	Tick uint32      `json:"tick"`
+	Data interface{} `json:"data"`
+}
+
+//export RegisterCallback
+func RegisterCallback(eventName *C.char, cbPtr C.uintptr_t) {
+	name := C.GoString(eventName)
+	registeredCallbacksMu.Lock()
+	defer registeredCallbacksMu.Unlock()
+	registeredCallbacks[name] = C.manta_event_callback(unsafe.Pointer(uintptr(cbPtr)))
+}
+
+//export UnregisterCallback
+func UnregisterCallback(eventName *C.char) {
+	name := C.GoString(eventName)
+	registeredCallbacksMu.Lock()
+	defer registeredCallbacksMu.Unlock()
+	delete(registeredCallbacks, name)
+}
+
+// invokeCallback looks up name (or an "entity_snapshot@N" sampling-interval
+// name via invokeSnapshotCallback) and, if Python registered one, marshals
+// event to JSON and calls it synchronously on the parsing goroutine. Since
+// cgo function pointer calls must happen from a thread cgo knows about, the
+// parsing goroutine calling this is pinned with runtime.LockOSThread in
+// ParseStreaming.
+func invokeCallback(name string, tick uint32, data interface{}) {
+	registeredCallbacksMu.Lock()
+	cb, ok := registeredCallbacks[name]
+	registeredCallbacksMu.Unlock()
+	if !ok {
+		return
+	}
+
+	payload, err := json.Marshal(pushEvent{Name: name, Tick: tick, Data: data})
+	if err != nil {
+		return
+	}
+
+	cData := C.CString(string(payload))
+	defer C.free(unsafe.Pointer(cData))
+	C.manta_call_event_callback(cb, cData)
+}
+
+// invokeSnapshotCallback dispatches to whichever registered
+// "entity_snapshot@N" callback name matches tick modulo N, since
+// RegisterCallback stores the sampling interval directly in the event name.
+func invokeSnapshotCallback(tick uint32, data interface{}) {
+	registeredCallbacksMu.Lock()
+	names := make([]string, 0, len(registeredCallbacks))
+	for name := range registeredCallbacks {
+		if strings.HasPrefix(name, "entity_snapshot@") {
+			names = append(names, name)
+		}
+	}
+	registeredCallbacksMu.Unlock()
+
+	for _, name := range names {
+		interval, err := strconv.Atoi(strings.TrimPrefix(name, "entity_snapshot@"))
+		if err != nil || interval <= 0 {
+			continue
+		}
+		if tick%uint32(interval) == 0 {
+			invokeCallback(name, tick, data)
+		}
+	}
+}
+
+// ParseStreaming walks filePath once, invoking whatever callbacks Python
+// registered via RegisterCallback as each event is produced, instead of
+// accumulating a result slice the way every other export in this package
+// does. The parse runs on a locked OS thread so the cgo function pointer
+// calls it makes are always issued from the same thread cgo set up for them.
+//
+//export ParseStreaming
+func ParseStreaming(filePath *C.char) *C.char {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	goFilePath := C.GoString(filePath)
+
+	result := map[string]interface{}{"success": true}
+	fail := func(err error) *C.char {
+		result["success"] = false
+		result["error"] = err.Error()
+		data, _ := json.Marshal(result)
+		return C.CString(string(data))
+	}
+
+	file, err := os.Open(goFilePath)
+	if err != nil {
+		return fail(fmt.Errorf("error opening file: %w", err))
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		return fail(fmt.Errorf("error creating parser: %w", err))
+	}
+
+	parser.Callbacks.OnCDemoFileHeader(func(m *dota.CDemoFileHeader) error {
+		invokeCallback("header", parser.Tick, m)
+		return nil
+	})
+	parser.Callbacks.OnCDemoFileInfo(func(m *dota.CDemoFileInfo) error {
+		invokeCallback("file_info", parser.Tick, m)
+		return nil
+	})
+	parser.Callbacks.OnCUserMessageSayText2(func(m *dota.CUserMessageSayText2) error {
+		invokeCallback("chat", parser.Tick, m)
+		return nil
+	})
+	parser.Callbacks.OnCMsgDOTACombatLogEntry(func(m *dota.CMsgDOTACombatLogEntry) error {
+		invokeCallback("combat_log", parser.Tick, m)
+		return nil
+	})
+	parser.Callbacks.OnCDemoPacket(func(m *dota.CDemoPacket) error {
+		invokeCallback("tick", parser.Tick, nil)
+		return nil
+	})
+	parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+		if e == nil || !strings.Contains(e.GetClassName(), "CDOTA_Unit_Hero_") {
+			return nil
+		}
+		invokeSnapshotCallback(parser.Tick, buildLightweightHeroSnapshot(e, int(e.GetIndex())))
+		return nil
+	})
+
+	if err := parser.Start(); err != nil {
+		return fail(fmt.Errorf("error parsing file: %w", err))
+	}
+
+	data, _ := json.Marshal(result)
+	return C.CString(string(data))
+}
@@ -0,0 +1,439 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+	"github.com/golang/snappy"
+
+	"manta_wrapper/filter"
+)
+
+// Sink is the destination for parsed MessageEvents. SliceSink (the historical
+// behavior) and OTLPSink both implement it, so setupAllCallbacks's caller
+// picks an accumulator instead of RunUniversal hard-coding a slice.
+type Sink interface {
+	Emit(MessageEvent) error
+	Flush() error
+	Close() error
+}
+
+// SliceSink buffers every emitted event in memory, exactly like
+// RunUniversal's `result.Messages` today.
+type SliceSink struct {
+	Messages []MessageEvent
+}
+
+func NewSliceSink() *SliceSink {
+	return &SliceSink{Messages: make([]MessageEvent, 0)}
+}
+
+func (s *SliceSink) Emit(e MessageEvent) error {
+	s.Messages = append(s.Messages, e)
+	return nil
+}
+
+func (s *SliceSink) Flush() error { return nil }
+func (s *SliceSink) Close() error { return nil }
+
+// NDJSONSinkConfig configures NewFileSink/NewWriterSink.
+type NDJSONSinkConfig struct {
+	Path       string `json:"path"`        // NewFileSink only
+	Format     string `json:"format"`      // "" or "ndjson"/"jsonl"; see NewWriterSink
+	Filter     string `json:"filter"`      // filter.Compile expression; events that don't match are dropped
+	FlushEvery int    `json:"flush_every"` // flush after this many records (0 = flush every Emit)
+}
+
+// NDJSONSink writes one JSON line per MessageEvent to an io.Writer,
+// optionally dropping events that don't match a compiled filter
+// expression, so a full 90-minute pro replay can stream straight to disk
+// or a pipe instead of buffering every event in a SliceSink.
+type NDJSONSink struct {
+	w          *bufio.Writer
+	closer     io.Closer
+	filterExpr filter.Expr
+	flushEvery int
+	since      int
+}
+
+// NewWriterSink wraps w in an NDJSONSink. format must be "" or one of
+// "ndjson"/"jsonl" - length-prefixed protobuf and Parquet output (as
+// named in the original request) aren't implemented, since nothing else
+// in this wrapper encodes through protojson or parquet-go today.
+func NewWriterSink(w io.Writer, format string, filterExpr string, flushEvery int) (Sink, error) {
+	switch format {
+	case "", "ndjson", "jsonl":
+	default:
+		return nil, fmt.Errorf("unsupported sink format %q (supported: ndjson)", format)
+	}
+
+	var expr filter.Expr
+	if filterExpr != "" {
+		compiled, err := filter.Compile(filterExpr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sink filter: %w", err)
+		}
+		expr = compiled
+	}
+
+	return &NDJSONSink{w: bufio.NewWriter(w), filterExpr: expr, flushEvery: flushEvery}, nil
+}
+
+// NewFileSink opens path and wraps it in an NDJSONSink; Close closes the
+// underlying file after flushing.
+func NewFileSink(path string, format string, filterExpr string, flushEvery int) (Sink, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("error creating sink file: %w", err)
+	}
+
+	sink, err := NewWriterSink(f, format, filterExpr, flushEvery)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	ndjson := sink.(*NDJSONSink)
+	ndjson.closer = f
+	return ndjson, nil
+}
+
+func (s *NDJSONSink) Emit(e MessageEvent) error {
+	if s.filterExpr != nil && !filter.Eval(s.filterExpr, filter.Event{Type: e.Type, Tick: e.Tick, NetTick: e.NetTick, Data: e.Data}) {
+		return nil
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("error encoding event: %w", err)
+	}
+	if _, err := s.w.Write(data); err != nil {
+		return err
+	}
+	if err := s.w.WriteByte('\n'); err != nil {
+		return err
+	}
+
+	s.since++
+	if s.flushEvery > 0 && s.since >= s.flushEvery {
+		s.since = 0
+		return s.w.Flush()
+	}
+	return nil
+}
+
+func (s *NDJSONSink) Flush() error {
+	s.since = 0
+	return s.w.Flush()
+}
+
+func (s *NDJSONSink) Close() error {
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	if s.closer != nil {
+		return s.closer.Close()
+	}
+	return nil
+}
+
+// OTLPSinkConfig mirrors the knobs a typical OTLP log flusher exposes.
+type OTLPSinkConfig struct {
+	Endpoint       string            `json:"endpoint"`
+	Compression    string            `json:"compression"` // "", "gzip", or "snappy"
+	Headers        map[string]string `json:"headers"`
+	BatchSize      int               `json:"batch_size"`
+	MaxRetries     int               `json:"max_retries"`
+	RetryBaseDelay time.Duration     `json:"retry_base_delay_ms"`
+	DemoFile       string            `json:"demo_file"`
+}
+
+// otlpLogRecord is a minimal OTLP LogRecord projection (JSON encoding of the
+// OTLP logs data model) - enough for a receiver to bucket/search on.
+type otlpLogRecord struct {
+	TimeUnixNano int64                  `json:"timeUnixNano,string"`
+	Body         interface{}            `json:"body"`
+	Attributes   map[string]interface{} `json:"attributes"`
+}
+
+// OTLPSink batches MessageEvents and POSTs them as OTLP/HTTP log records to
+// any OpenTelemetry-Log-Protocol receiver, with exponential-backoff retry.
+type OTLPSink struct {
+	cfg    OTLPSinkConfig
+	client *http.Client
+	batch  []otlpLogRecord
+}
+
+func NewOTLPSink(cfg OTLPSinkConfig) *OTLPSink {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 256
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.RetryBaseDelay <= 0 {
+		cfg.RetryBaseDelay = 200 * time.Millisecond
+	}
+	return &OTLPSink{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+		batch:  make([]otlpLogRecord, 0, cfg.BatchSize),
+	}
+}
+
+// tickInterval is the wall-clock duration of a single replay tick, used to
+// derive a LogRecord's timestamp from parser.Tick.
+const tickInterval = time.Second / time.Duration(TicksPerSecond)
+
+func (s *OTLPSink) Emit(e MessageEvent) error {
+	body, err := json.Marshal(e.Data)
+	if err != nil {
+		return fmt.Errorf("marshal message body: %w", err)
+	}
+
+	s.batch = append(s.batch, otlpLogRecord{
+		TimeUnixNano: int64(e.Tick) * int64(tickInterval),
+		Body:         json.RawMessage(body),
+		Attributes: map[string]interface{}{
+			"tick":         e.Tick,
+			"net_tick":     e.NetTick,
+			"message_type": e.Type,
+			"demo_file":    s.cfg.DemoFile,
+		},
+	})
+
+	if len(s.batch) >= s.cfg.BatchSize {
+		return s.Flush()
+	}
+	return nil
+}
+
+func (s *OTLPSink) Flush() error {
+	if len(s.batch) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{"logRecords": s.batch})
+	if err != nil {
+		return fmt.Errorf("marshal batch: %w", err)
+	}
+
+	body, contentEncoding, err := s.compress(payload)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	delay := s.cfg.RetryBaseDelay
+	for attempt := 0; attempt <= s.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+
+		req, err := http.NewRequest(http.MethodPost, s.cfg.Endpoint, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("build request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if contentEncoding != "" {
+			req.Header.Set("Content-Encoding", contentEncoding)
+		}
+		for k, v := range s.cfg.Headers {
+			req.Header.Set(k, v)
+		}
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			s.batch = s.batch[:0]
+			return nil
+		}
+		lastErr = fmt.Errorf("OTLP receiver returned status %d", resp.StatusCode)
+	}
+
+	return fmt.Errorf("flushing %d log records to %s: %w", len(s.batch), s.cfg.Endpoint, lastErr)
+}
+
+func (s *OTLPSink) compress(payload []byte) ([]byte, string, error) {
+	switch s.cfg.Compression {
+	case "", "none":
+		return payload, "", nil
+	case "gzip":
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		if _, err := gw.Write(payload); err != nil {
+			return nil, "", fmt.Errorf("gzip compress: %w", err)
+		}
+		if err := gw.Close(); err != nil {
+			return nil, "", fmt.Errorf("gzip close: %w", err)
+		}
+		return buf.Bytes(), "gzip", nil
+	case "snappy":
+		return snappy.Encode(nil, payload), "snappy", nil
+	default:
+		return nil, "", fmt.Errorf("unsupported OTLP compression %q (supported: gzip, snappy)", s.cfg.Compression)
+	}
+}
+
+func (s *OTLPSink) Close() error {
+	return s.Flush()
+}
+
+//export ParseUniversalWithSink
+func ParseUniversalWithSink(filePath *C.char, sinkKind *C.char, sinkConfigJSON *C.char, maxMessages C.int) *C.char {
+	goFilePath := C.GoString(filePath)
+	goSinkKind := C.GoString(sinkKind)
+	goSinkConfigJSON := C.GoString(sinkConfigJSON)
+	maxMsgs := int(maxMessages)
+
+	sink, sliceSink, err := buildSink(goSinkKind, goSinkConfigJSON, goFilePath)
+	if err != nil {
+		return marshalAndReturnUniversal(&UniversalParseResult{
+			Messages: make([]MessageEvent, 0),
+			Success:  false,
+			Error:    err.Error(),
+		})
+	}
+
+	if err := runUniversalIntoSink(goFilePath, maxMsgs, sink); err != nil {
+		sink.Close()
+		return marshalAndReturnUniversal(&UniversalParseResult{
+			Messages: make([]MessageEvent, 0),
+			Success:  false,
+			Error:    err.Error(),
+		})
+	}
+
+	if err := sink.Close(); err != nil {
+		return marshalAndReturnUniversal(&UniversalParseResult{
+			Messages: make([]MessageEvent, 0),
+			Success:  false,
+			Error:    err.Error(),
+		})
+	}
+
+	result := &UniversalParseResult{Success: true}
+	if sliceSink != nil {
+		result.Messages = sliceSink.Messages
+		result.Count = len(sliceSink.Messages)
+	} else {
+		result.Messages = make([]MessageEvent, 0)
+	}
+	return marshalAndReturnUniversal(result)
+}
+
+// buildSink constructs the Sink named by kind ("slice" or "otlp"). It also
+// returns the concrete *SliceSink (nil otherwise) so the caller can still
+// report buffered messages back through the existing JSON shape.
+func buildSink(kind string, configJSON string, demoFile string) (Sink, *SliceSink, error) {
+	switch kind {
+	case "", "slice":
+		s := NewSliceSink()
+		return s, s, nil
+	case "otlp":
+		var cfg OTLPSinkConfig
+		if configJSON != "" {
+			if err := json.Unmarshal([]byte(configJSON), &cfg); err != nil {
+				return nil, nil, fmt.Errorf("invalid OTLP sink config: %w", err)
+			}
+		}
+		if cfg.Endpoint == "" {
+			return nil, nil, fmt.Errorf("otlp sink requires an endpoint")
+		}
+		cfg.DemoFile = demoFile
+		return NewOTLPSink(cfg), nil, nil
+	case "ndjson", "file":
+		var cfg NDJSONSinkConfig
+		if configJSON != "" {
+			if err := json.Unmarshal([]byte(configJSON), &cfg); err != nil {
+				return nil, nil, fmt.Errorf("invalid ndjson sink config: %w", err)
+			}
+		}
+		if cfg.Path == "" {
+			return nil, nil, fmt.Errorf("ndjson sink requires a path")
+		}
+		sink, err := NewFileSink(cfg.Path, cfg.Format, cfg.Filter, cfg.FlushEvery)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sink, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown sink kind %q (supported: slice, otlp, ndjson)", kind)
+	}
+}
+
+// runUniversalIntoSink mirrors RunUniversal but drives a Sink instead of
+// directly populating a slice, so OTLPSink sees events as they're parsed.
+func runUniversalIntoSink(filePath string, maxMessages int, sink Sink) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		return fmt.Errorf("error creating parser: %w", err)
+	}
+
+	emitted := 0
+	emit := func(msgType string, data interface{}) error {
+		if maxMessages > 0 && emitted >= maxMessages {
+			return nil
+		}
+		if err := sink.Emit(MessageEvent{
+			Type:      msgType,
+			Tick:      parser.Tick,
+			NetTick:   parser.NetTick,
+			Data:      data,
+			Timestamp: time.Now().UnixMilli(),
+		}); err != nil {
+			return err
+		}
+		emitted++
+		if maxMessages > 0 && emitted >= maxMessages {
+			parser.Stop()
+		}
+		return nil
+	}
+
+	parser.Callbacks.OnCDemoFileHeader(func(m *dota.CDemoFileHeader) error {
+		return emit("CDemoFileHeader", m)
+	})
+	parser.Callbacks.OnCDemoFileInfo(func(m *dota.CDemoFileInfo) error {
+		return emit("CDemoFileInfo", m)
+	})
+	parser.Callbacks.OnCMsgDOTACombatLogEntry(func(m *dota.CMsgDOTACombatLogEntry) error {
+		return emit("CMsgDOTACombatLogEntry", m)
+	})
+	parser.Callbacks.OnCSVCMsg_PacketEntities(func(m *dota.CSVCMsg_PacketEntities) error {
+		return emit("CSVCMsg_PacketEntities", m)
+	})
+	parser.Callbacks.OnCDOTAUserMsg_ChatMessage(func(m *dota.CDOTAUserMsg_ChatMessage) error {
+		return emit("CDOTAUserMsg_ChatMessage", m)
+	})
+
+	if err := parser.Start(); err != nil {
+		return fmt.Errorf("error parsing file: %w", err)
+	}
+	return nil
+}
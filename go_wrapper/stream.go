@@ -26,6 +26,14 @@ type StreamHandle struct {
 	err       error
 	started   bool
 	completed atomic.Bool
+
+	// entity snapshot streaming state (see streamEntitySnapshots)
+	entityBaseline    map[int]HeroSnapshot
+	snapshotsSinceKey int
+
+	// dynamic message subscriptions (see StreamSubscribe in stream_subscribe.go)
+	subMu         sync.Mutex
+	subscriptions map[string]*bool
 }
 
 // StreamConfig specifies what events to stream
@@ -38,6 +46,11 @@ type StreamConfig struct {
 	FilterTypes  []string `json:"filter_types"`
 	MaxEvents    int      `json:"max_events"`
 	IntervalTick int      `json:"interval_tick"`
+
+	// Entity snapshot streaming (honored only when Entities is true)
+	Delta            bool     `json:"delta"`             // only emit fields changed since the last snapshot per EntityID
+	KeyframeInterval int      `json:"keyframe_interval"` // emit a full snapshot every K entity_snapshot events (0 = every snapshot is a keyframe)
+	Classes          []string `json:"classes"`           // entity class filter, e.g. "CDOTAGamerulesProxy", "CDOTA_Unit_Hero_*"
 }
 
 // StreamEvent is a single event yielded during streaming
@@ -203,6 +216,10 @@ func runStreamParser(h *StreamHandle) {
 		registerStreamMessageCallback(h, &eventCount, maxEvents)
 	}
 
+	if h.config.Entities {
+		streamEntitySnapshots(h, &eventCount, maxEvents)
+	}
+
 	if h.config.GameEvents {
 		h.parser.Callbacks.OnCMsgSource1LegacyGameEvent(func(m *dota.CMsgSource1LegacyGameEvent) error {
 			if eventCount >= maxEvents {
@@ -0,0 +1,188 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/dotabuff/manta"
+)
+
+// broadcastReader adapts a Source 2 TV broadcast (tv_broadcast_url) to the
+// io.Reader manta.NewStreamParser expects, by pulling /sync, /start,
+// /full/N and /delta/N fragments over HTTP and concatenating their bodies.
+// It implements the same StreamHandle plumbing as the file-backed path so
+// Python sees identical StreamEvent kinds from StreamNext/StreamClose.
+type broadcastReader struct {
+	baseURL    string
+	client     *http.Client
+	fragment   int64 // next fragment sequence number to fetch
+	isDelta    bool  // true once we've consumed the initial /full fragment
+	pending    io.Reader
+	reconnects int
+}
+
+// broadcastSync mirrors the JSON body of a Source 2 TV /sync fragment.
+type broadcastSync struct {
+	Tick         int64 `json:"tick"`
+	Endpoint     int64 `json:"endpoint"`
+	FragmentSize int64 `json:"fragment"`
+}
+
+func newBroadcastReader(baseURL string) *broadcastReader {
+	return &broadcastReader{
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// sync fetches /sync to learn the starting fragment number before the first
+// /start (signon) fetch.
+func (b *broadcastReader) sync() error {
+	resp, err := b.client.Get(b.baseURL + "/sync")
+	if err != nil {
+		return fmt.Errorf("broadcast sync: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var s broadcastSync
+	if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+		return fmt.Errorf("broadcast sync decode: %w", err)
+	}
+	b.fragment = s.FragmentSize
+	return nil
+}
+
+// Read implements io.Reader by pulling fragments on demand: /start once,
+// then /full/N, then an unbounded stream of /delta/N fragments. Transient
+// fetch failures (mid-match server hiccups, fragment gaps) are retried with
+// a short backoff rather than surfaced as an EOF, so the parser doesn't stop
+// mid-broadcast.
+func (b *broadcastReader) Read(p []byte) (int, error) {
+	for {
+		if b.pending != nil {
+			n, err := b.pending.Read(p)
+			if n > 0 {
+				return n, nil
+			}
+			if err != nil && err != io.EOF {
+				return 0, err
+			}
+			b.pending = nil
+		}
+
+		body, err := b.fetchNextFragment()
+		if err != nil {
+			b.reconnects++
+			if b.reconnects > maxBroadcastReconnects {
+				return 0, fmt.Errorf("broadcast reader giving up after %d reconnect attempts: %w", b.reconnects, err)
+			}
+			time.Sleep(broadcastRetryDelay)
+			continue
+		}
+		b.reconnects = 0
+		b.pending = body
+	}
+}
+
+const (
+	maxBroadcastReconnects = 10
+	broadcastRetryDelay    = 500 * time.Millisecond
+)
+
+func (b *broadcastReader) fetchNextFragment() (io.Reader, error) {
+	var path string
+	if !b.isDelta {
+		path = fmt.Sprintf("/full/%d", b.fragment)
+		b.isDelta = true
+	} else {
+		path = fmt.Sprintf("/delta/%d", b.fragment)
+	}
+
+	resp, err := b.client.Get(b.baseURL + path)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("fetching %s: status %d", path, resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	b.fragment++
+	return newByteReader(data), nil
+}
+
+// newByteReader avoids pulling in bytes.NewReader's broader API surface at
+// the call site above.
+func newByteReader(data []byte) io.Reader {
+	return &sliceReader{data: data}
+}
+
+type sliceReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *sliceReader) Read(p []byte) (int, error) {
+	if r.pos >= len(r.data) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.data[r.pos:])
+	r.pos += n
+	return n, nil
+}
+
+//export StreamOpenBroadcast
+func StreamOpenBroadcast(broadcastURL *C.char, configJSON *C.char) *C.char {
+	url := C.GoString(broadcastURL)
+	configStr := C.GoString(configJSON)
+
+	var config StreamConfig
+	if configStr != "" {
+		if err := json.Unmarshal([]byte(configStr), &config); err != nil {
+			return marshalBroadcastError(fmt.Sprintf("invalid config JSON: %v", err))
+		}
+	}
+
+	reader := newBroadcastReader(url)
+	if err := reader.sync(); err != nil {
+		return marshalBroadcastError(err.Error())
+	}
+
+	parser, err := manta.NewStreamParser(reader)
+	if err != nil {
+		return marshalBroadcastError(fmt.Sprintf("failed to create parser: %v", err))
+	}
+
+	handle := &StreamHandle{
+		parser: parser,
+		config: config,
+		events: make(chan StreamEvent, 1000),
+		done:   make(chan struct{}),
+	}
+	handleID := storeHandle(handle)
+
+	go runStreamParser(handle)
+
+	result := map[string]interface{}{"success": true, "handle_id": handleID}
+	jsonResult, _ := json.Marshal(result)
+	return C.CString(string(jsonResult))
+}
+
+func marshalBroadcastError(msg string) *C.char {
+	result := map[string]interface{}{"success": false, "error": msg, "handle_id": int64(-1)}
+	jsonResult, _ := json.Marshal(result)
+	return C.CString(string(jsonResult))
+}
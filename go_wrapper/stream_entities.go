@@ -0,0 +1,161 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/dotabuff/manta"
+)
+
+// streamEntitySnapshots wires StreamConfig.Entities into runStreamParser: it
+// walks updated entities via parser.OnEntity and, every IntervalTick ticks,
+// emits a StreamEvent{Kind: "entity_snapshot"} per matching entity. Unlike
+// the batch EntityParseResult path (RunEntityParse/captureSnapshot), this
+// builds a lightweight per-entity HeroSnapshot directly off the entity's own
+// properties so a single PacketEntities update can be streamed immediately
+// instead of waiting to cross-reference CDOTA_PlayerResource.
+func streamEntitySnapshots(h *StreamHandle, eventCount *int, maxEvents int) {
+	h.entityBaseline = make(map[int]HeroSnapshot)
+
+	interval := uint32(h.config.IntervalTick)
+	lastCaptureTick := make(map[int]uint32)
+
+	h.parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+		if *eventCount >= maxEvents {
+			return nil
+		}
+		if !op.Flag(manta.EntityOpUpdated) && !op.Flag(manta.EntityOpCreated) {
+			return nil
+		}
+
+		className := e.GetClassName()
+		if !matchesEntityClassFilter(className, h.config.Classes) {
+			return nil
+		}
+		if !strings.Contains(className, "CDOTA_Unit_Hero_") {
+			return nil
+		}
+
+		entityID := int(e.GetIndex())
+		currentTick := h.parser.Tick
+		if interval > 0 && currentTick-lastCaptureTick[entityID] < interval {
+			return nil
+		}
+		lastCaptureTick[entityID] = currentTick
+
+		snapshot := buildLightweightHeroSnapshot(e, entityID)
+
+		payload, isKeyframe := h.diffAgainstBaseline(entityID, snapshot)
+
+		select {
+		case h.events <- StreamEvent{
+			Kind:    "entity_snapshot",
+			Tick:    int(currentTick),
+			NetTick: int(h.parser.NetTick),
+			Type:    boolToKeyframeType(isKeyframe),
+			Data:    payload,
+		}:
+			*eventCount++
+		case <-h.done:
+			return nil
+		}
+		return nil
+	})
+}
+
+// matchesEntityClassFilter reports whether className matches one of the
+// configured class filters (supporting a trailing "*" wildcard, e.g.
+// "CDOTA_Unit_Hero_*"). An empty filter list matches everything.
+func matchesEntityClassFilter(className string, classes []string) bool {
+	if len(classes) == 0 {
+		return true
+	}
+	for _, c := range classes {
+		if strings.HasSuffix(c, "*") {
+			if strings.HasPrefix(className, strings.TrimSuffix(c, "*")) {
+				return true
+			}
+		} else if className == c {
+			return true
+		}
+	}
+	return false
+}
+
+// buildLightweightHeroSnapshot reads the handful of properties available
+// directly off a hero entity, without the PlayerResource cross-reference
+// extractFullHeroSnapshot needs for gold/XP/KDA.
+func buildLightweightHeroSnapshot(e *manta.Entity, entityID int) HeroSnapshot {
+	hero := HeroSnapshot{
+		EntityID: entityID,
+		Index:    entityID,
+		HeroName: entityClassToHeroName(e.GetClassName()),
+	}
+	if x, ok := e.GetFloat32("m_vecOrigin.x"); ok {
+		hero.X = x
+	}
+	if y, ok := e.GetFloat32("m_vecOrigin.y"); ok {
+		hero.Y = y
+	}
+	if level, ok := e.GetInt32("m_iCurrentLevel"); ok {
+		hero.Level = int(level)
+	}
+	if health, ok := e.GetInt32("m_iHealth"); ok {
+		hero.Health = int(health)
+	}
+	if maxHealth, ok := e.GetInt32("m_iMaxHealth"); ok {
+		hero.MaxHealth = int(maxHealth)
+	}
+	if mana, ok := e.GetFloat32("m_flMana"); ok {
+		hero.Mana = mana
+	}
+	hero.IsAlive = hero.Health > 0
+	return hero
+}
+
+// diffAgainstBaseline applies the StreamConfig.Delta/KeyframeInterval
+// policy: every KeyframeInterval-th snapshot (or every snapshot, if
+// KeyframeInterval <= 0, or Delta is off) is emitted in full; in between,
+// only fields that changed since the stored per-EntityID baseline are set,
+// with all other numeric fields left zero so the JSON payload stays small.
+func (h *StreamHandle) diffAgainstBaseline(entityID int, snapshot HeroSnapshot) (interface{}, bool) {
+	prev, hadBaseline := h.entityBaseline[entityID]
+	h.entityBaseline[entityID] = snapshot
+
+	keyframeEvery := h.config.KeyframeInterval
+	isKeyframe := !h.config.Delta || !hadBaseline || keyframeEvery <= 0 ||
+		h.snapshotsSinceKey >= keyframeEvery
+
+	if isKeyframe {
+		h.snapshotsSinceKey = 0
+		return snapshot, true
+	}
+	h.snapshotsSinceKey++
+
+	delta := map[string]interface{}{"entity_id": entityID}
+	if snapshot.X != prev.X || snapshot.Y != prev.Y {
+		delta["x"], delta["y"] = snapshot.X, snapshot.Y
+	}
+	if snapshot.Level != prev.Level {
+		delta["level"] = snapshot.Level
+	}
+	if snapshot.Health != prev.Health {
+		delta["health"] = snapshot.Health
+	}
+	if snapshot.MaxHealth != prev.MaxHealth {
+		delta["max_health"] = snapshot.MaxHealth
+	}
+	if snapshot.Mana != prev.Mana {
+		delta["mana"] = snapshot.Mana
+	}
+	if snapshot.IsAlive != prev.IsAlive {
+		delta["is_alive"] = snapshot.IsAlive
+	}
+	return delta, false
+}
+
+func boolToKeyframeType(isKeyframe bool) string {
+	if isKeyframe {
+		return "keyframe"
+	}
+	return "delta"
+}
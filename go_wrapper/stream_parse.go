@@ -0,0 +1,414 @@
+package main
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// errStreamCancelled is the sentinel error a cancelled Stream* parse
+// returns from its OnCDemoPacket tap to unwind manta.Parser.Start()
+// cleanly - the same trick ParseHeader/ParseMatchInfo use with
+// parser.Stop(), except this one is a caller-requested giveup rather than
+// "found what I needed".
+var errStreamCancelled = errors.New("stream parse cancelled")
+
+// activeStreams holds the cancel channel for every in-flight Stream* parse,
+// keyed by the handle returned to the Python caller. CancelParse closes the
+// channel directly; SetParseDeadline schedules a timer that closes it when
+// the deadline arrives. Either way, the OnCDemoPacket tap every Stream*
+// export installs notices on the next packet and unwinds the parse.
+var (
+	activeStreamsMu  sync.Mutex
+	activeStreams    = make(map[uint64]chan struct{})
+	nextStreamHandle uint64
+)
+
+func registerStream() (uint64, chan struct{}) {
+	activeStreamsMu.Lock()
+	defer activeStreamsMu.Unlock()
+	nextStreamHandle++
+	handle := nextStreamHandle
+	cancel := make(chan struct{})
+	activeStreams[handle] = cancel
+	return handle, cancel
+}
+
+func unregisterStream(handle uint64) {
+	activeStreamsMu.Lock()
+	defer activeStreamsMu.Unlock()
+	delete(activeStreams, handle)
+}
+
+//export CancelParse
+func CancelParse(handle C.uint64_t) {
+	activeStreamsMu.Lock()
+	cancel, ok := activeStreams[uint64(handle)]
+	activeStreamsMu.Unlock()
+	if ok {
+		closeStreamChan(cancel)
+	}
+}
+
+//export SetParseDeadline
+func SetParseDeadline(handle C.uint64_t, unixNanos C.int64_t) {
+	activeStreamsMu.Lock()
+	cancel, ok := activeStreams[uint64(handle)]
+	activeStreamsMu.Unlock()
+	if !ok {
+		return
+	}
+
+	delay := time.Until(time.Unix(0, int64(unixNanos)))
+	if delay <= 0 {
+		closeStreamChan(cancel)
+		return
+	}
+	time.AfterFunc(delay, func() { closeStreamChan(cancel) })
+}
+
+// closeStreamChan closes cancel without panicking if it's already closed -
+// CancelParse, a deadline timer, and normal parse completion can all race
+// to be the one that closes it.
+func closeStreamChan(cancel chan struct{}) {
+	select {
+	case <-cancel:
+	default:
+		close(cancel)
+	}
+}
+
+// tapCancellation registers the OnCDemoPacket handler that checks cancel
+// between packets - the finest grain manta's callback model exposes -
+// returning errStreamCancelled to unwind parser.Start() the moment cancel
+// closes.
+func tapCancellation(parser *manta.Parser, cancel chan struct{}) {
+	parser.Callbacks.OnCDemoPacket(func(m *dota.CDemoPacket) error {
+		select {
+		case <-cancel:
+			return errStreamCancelled
+		default:
+			return nil
+		}
+	})
+}
+
+// streamStatus is the envelope every Stream* export returns once parsing
+// has stopped (normally, by cancellation, or by error); the events
+// themselves were already pushed out one-by-one via invokeCallback instead
+// of being buffered here, which is the whole point of the streaming mode.
+type streamStatus struct {
+	Handle    uint64 `json:"handle"`
+	Success   bool   `json:"success"`
+	Cancelled bool   `json:"cancelled"`
+	Error     string `json:"error,omitempty"`
+}
+
+func marshalStreamStatus(s *streamStatus) *C.char {
+	data, err := json.Marshal(s)
+	if err != nil {
+		data, _ = json.Marshal(&streamStatus{Success: false, Error: err.Error()})
+	}
+	return C.CString(string(data))
+}
+
+// runStreamParse is the shared skeleton every Stream* export uses: open
+// the file, register a cancellation handle, wire setup (which installs
+// whatever per-event callbacks push events to the caller's registered
+// callback via invokeCallback), tap cancellation, and run to completion.
+func runStreamParse(filePath string, setup func(parser *manta.Parser)) *streamStatus {
+	handle, cancel := registerStream()
+	defer unregisterStream(handle)
+
+	status := &streamStatus{Handle: handle}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		status.Error = fmt.Sprintf("error opening file: %v", err)
+		return status
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		status.Error = fmt.Sprintf("error creating parser: %v", err)
+		return status
+	}
+
+	setup(parser)
+	tapCancellation(parser, cancel)
+
+	if err := parser.Start(); err != nil {
+		if errors.Is(err, errStreamCancelled) {
+			status.Cancelled = true
+			status.Success = true
+			return status
+		}
+		status.Error = fmt.Sprintf("error parsing file: %v", err)
+		return status
+	}
+
+	status.Success = true
+	return status
+}
+
+// StreamGameEvents is the streaming sibling of ParseGameEvents: instead of
+// accumulating every GameEventData into one result slice (which can OOM
+// the Python caller on a long replay), it pushes each event through the
+// callback RegisterCallback registered under callbackName as it's parsed,
+// and returns a handle immediately usable with CancelParse/SetParseDeadline
+// from another thread.
+//
+//export StreamGameEvents
+func StreamGameEvents(filePath *C.char, configJSON *C.char, callbackName *C.char) *C.char {
+	goFilePath := C.GoString(filePath)
+	goConfigJSON := C.GoString(configJSON)
+	name := C.GoString(callbackName)
+
+	config := GameEventsConfig{}
+	if goConfigJSON != "" {
+		json.Unmarshal([]byte(goConfigJSON), &config)
+	}
+
+	status := runStreamParse(goFilePath, func(parser *manta.Parser) {
+		eventTypeNames := make(map[int32]string)
+		eventTypeFields := make(map[string][]string)
+		gameTime := newGameTimeTracker()
+		gameTime.Watch(parser)
+
+		parser.Callbacks.OnCMsgSource1LegacyGameEventList(func(m *dota.CMsgSource1LegacyGameEventList) error {
+			for _, d := range m.GetDescriptors() {
+				eventTypeNames[d.GetEventid()] = d.GetName()
+				fieldNames := make([]string, len(d.GetKeys()))
+				for i, k := range d.GetKeys() {
+					fieldNames[i] = k.GetName()
+				}
+				eventTypeFields[d.GetName()] = fieldNames
+			}
+			return nil
+		})
+
+		count := 0
+		parser.Callbacks.OnCMsgSource1LegacyGameEvent(func(m *dota.CMsgSource1LegacyGameEvent) error {
+			if config.MaxEvents > 0 && count >= config.MaxEvents {
+				return nil
+			}
+			eventName, ok := eventTypeNames[m.GetEventid()]
+			if !ok || (config.EventFilter != "" && !strings.Contains(eventName, config.EventFilter)) {
+				return nil
+			}
+
+			event := &GameEventData{Name: eventName, Tick: parser.Tick, NetTick: parser.NetTick, Fields: make(map[string]interface{})}
+			fieldNames := eventTypeFields[eventName]
+			for i, key := range m.GetKeys() {
+				fieldName := fmt.Sprintf("field_%d", i)
+				if i < len(fieldNames) {
+					fieldName = fieldNames[i]
+				}
+				switch key.GetType() {
+				case 1:
+					event.Fields[fieldName] = key.GetValString()
+				case 2:
+					event.Fields[fieldName] = key.GetValFloat()
+				case 3:
+					event.Fields[fieldName] = key.GetValLong()
+				case 4:
+					event.Fields[fieldName] = key.GetValShort()
+				case 5:
+					event.Fields[fieldName] = key.GetValByte()
+				case 6:
+					event.Fields[fieldName] = key.GetValBool()
+				case 7:
+					event.Fields[fieldName] = key.GetValUint64()
+				}
+			}
+			gameTime.Annotate(func(s gameTimeSnapshot) {
+				event.GameTime = s.GameTime
+				event.GameState = s.GameState
+				event.IsPaused = s.IsPaused
+			})
+			count++
+			invokeCallback(name, parser.Tick, event)
+			return nil
+		})
+	})
+
+	return marshalStreamStatus(status)
+}
+
+// StreamModifiers is the streaming sibling of ParseModifiers.
+//
+//export StreamModifiers
+func StreamModifiers(filePath *C.char, configJSON *C.char, callbackName *C.char) *C.char {
+	goFilePath := C.GoString(filePath)
+	goConfigJSON := C.GoString(configJSON)
+	name := C.GoString(callbackName)
+
+	config := ModifiersConfig{}
+	if goConfigJSON != "" {
+		json.Unmarshal([]byte(goConfigJSON), &config)
+	}
+
+	status := runStreamParse(goFilePath, func(parser *manta.Parser) {
+		gameTime := newGameTimeTracker()
+		gameTime.Watch(parser)
+
+		count := 0
+		parser.OnModifierTableEntry(func(m *dota.CDOTAModifierBuffTableEntry) error {
+			if config.MaxModifiers > 0 && count >= config.MaxModifiers {
+				return nil
+			}
+			isAura := m.GetAura()
+			if config.AurasOnly && !isAura {
+				return nil
+			}
+
+			entry := &ModifierEntry{
+				Tick: parser.Tick, NetTick: parser.NetTick,
+				Parent: m.GetParent(), Caster: m.GetCaster(), Ability: m.GetAbility(),
+				ModifierClass: m.GetModifierClass(), SerialNum: m.GetSerialNum(), Index: m.GetIndex(),
+				CreationTime: m.GetCreationTime(), Duration: m.GetDuration(), StackCount: m.GetStackCount(),
+				IsAura: isAura,
+			}
+			gameTime.Annotate(func(s gameTimeSnapshot) {
+				entry.GameTime = s.GameTime
+				entry.GameState = s.GameState
+				entry.IsPaused = s.IsPaused
+			})
+			count++
+			invokeCallback(name, parser.Tick, entry)
+			return nil
+		})
+	})
+
+	return marshalStreamStatus(status)
+}
+
+// StreamCombatLog is the streaming sibling of ParseCombatLog. Unlike
+// RunCombatLogParse it cannot do the two-pass name resolution trick (string
+// tables aren't fully populated until the whole file parses), so it
+// resolves names best-effort against the string tables seen so far - the
+// same tradeoff ParseChat and the other single-pass exports accept.
+//
+//export StreamCombatLog
+func StreamCombatLog(filePath *C.char, configJSON *C.char, callbackName *C.char) *C.char {
+	goFilePath := C.GoString(filePath)
+	goConfigJSON := C.GoString(configJSON)
+	name := C.GoString(callbackName)
+
+	config := CombatLogConfig{}
+	if goConfigJSON != "" {
+		json.Unmarshal([]byte(goConfigJSON), &config)
+	}
+
+	status := runStreamParse(goFilePath, func(parser *manta.Parser) {
+		gameTime := newGameTimeTracker()
+		gameTime.Watch(parser)
+
+		getName := func(idx uint32) string {
+			if name, ok := parser.LookupStringByIndex("CombatLogNames", int32(idx)); ok {
+				return name
+			}
+			return fmt.Sprintf("unknown_%d", idx)
+		}
+
+		count := 0
+		parser.Callbacks.OnCMsgDOTACombatLogEntry(func(m *dota.CMsgDOTACombatLogEntry) error {
+			if config.MaxEntries > 0 && count >= config.MaxEntries {
+				return nil
+			}
+			entryType := m.GetType()
+			if len(config.Types) > 0 {
+				found := false
+				for _, t := range config.Types {
+					if t == int32(entryType) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					return nil
+				}
+			}
+
+			entry := &CombatLogEntry{
+				Tick: parser.Tick, NetTick: parser.NetTick,
+				Type: int32(entryType), TypeName: dota.DOTA_COMBATLOG_TYPES_name[int32(entryType)],
+				TargetName: getName(m.GetTargetName()), AttackerName: getName(m.GetAttackerName()),
+				IsAttackerHero: m.GetIsAttackerHero(), IsTargetHero: m.GetIsTargetHero(),
+				Value: int32(m.GetValue()), Health: m.GetHealth(),
+			}
+
+			if config.HeroesOnly && !entry.IsAttackerHero && !entry.IsTargetHero &&
+				!strings.Contains(entry.AttackerName, "npc_dota_hero_") && !strings.Contains(entry.TargetName, "npc_dota_hero_") {
+				return nil
+			}
+
+			gameTime.Annotate(func(s gameTimeSnapshot) {
+				entry.GameTime = s.GameTime
+				entry.GameState = s.GameState
+				entry.IsPaused = s.IsPaused
+			})
+			count++
+			invokeCallback(name, parser.Tick, entry)
+			return nil
+		})
+	})
+
+	return marshalStreamStatus(status)
+}
+
+// StreamEntities is the streaming sibling of QueryEntities: rather than a
+// single snapshot at one tick or end-of-file, it pushes an EntityData out
+// on every update to an entity matching classFilter. (Diffing against the
+// previously-seen value per entity, rather than re-sending the full
+// property set every time, is StreamEntityDeltas' job, not this one's.)
+//
+//export StreamEntities
+func StreamEntities(filePath *C.char, classFilter *C.char, callbackName *C.char) *C.char {
+	goFilePath := C.GoString(filePath)
+	goClassFilter := C.GoString(classFilter)
+	name := C.GoString(callbackName)
+
+	status := runStreamParse(goFilePath, func(parser *manta.Parser) {
+		gameTime := newGameTimeTracker()
+		gameTime.Watch(parser)
+
+		parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+			if e == nil {
+				return nil
+			}
+			className := e.GetClassName()
+			if className == "CDOTAGamerulesProxy" {
+				return nil
+			}
+			if goClassFilter != "" && !strings.Contains(className, goClassFilter) {
+				return nil
+			}
+
+			data := &EntityData{Index: e.GetIndex(), Serial: e.GetSerial(), ClassName: className, Properties: e.Map()}
+			gameTime.Annotate(func(s gameTimeSnapshot) {
+				data.GameTime = s.GameTime
+				data.GameState = s.GameState
+				data.IsPaused = s.IsPaused
+			})
+			invokeCallback(name, parser.Tick, data)
+			return nil
+		})
+	})
+
+	return marshalStreamStatus(status)
+}
@@ -0,0 +1,144 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/dotabuff/manta"
+)
+
+// StreamSubscribe lets Python bind an arbitrary dota.* message callback by
+// name at runtime (e.g. "CUserMessageSayText2",
+// "CDOTAUserMsg_SpectatorPlayerUnitOrders") instead of requiring a recompile
+// of the Go shim every time a caller wants a message type that isn't one of
+// the handful hardcoded in registerStreamMessageCallback. It works by
+// reflecting over h.parser.Callbacks for a method named "On"+messageName,
+// building a func value of that method's single parameter type (always
+// func(*dota.X) error) with reflect.MakeFunc, and calling the method with it.
+// manta.Callbacks has no corresponding "remove" API, so StreamUnsubscribe
+// can't un-register the callback - it only stops it from reaching the event
+// channel, matching the done-channel bail-out pattern runStreamParser's
+// other callbacks already use.
+//
+//export StreamSubscribe
+func StreamSubscribe(handleID C.longlong, messageName *C.char) *C.char {
+	h := getHandle(int64(handleID))
+	if h == nil {
+		return marshalSubscribeResult(false, "invalid handle")
+	}
+	name := C.GoString(messageName)
+
+	h.subMu.Lock()
+	defer h.subMu.Unlock()
+	if h.subscriptions == nil {
+		h.subscriptions = make(map[string]*bool)
+	}
+	if _, already := h.subscriptions[name]; already {
+		return marshalSubscribeResult(true, "")
+	}
+
+	active := new(bool)
+	*active = true
+
+	if err := bindCallbackByName(h, name, active); err != nil {
+		return marshalSubscribeResult(false, err.Error())
+	}
+
+	h.subscriptions[name] = active
+	return marshalSubscribeResult(true, "")
+}
+
+//export StreamUnsubscribe
+func StreamUnsubscribe(handleID C.longlong, messageName *C.char) *C.char {
+	h := getHandle(int64(handleID))
+	if h == nil {
+		return marshalSubscribeResult(false, "invalid handle")
+	}
+	name := C.GoString(messageName)
+
+	h.subMu.Lock()
+	defer h.subMu.Unlock()
+	active, ok := h.subscriptions[name]
+	if !ok {
+		return marshalSubscribeResult(false, fmt.Sprintf("not subscribed to %s", name))
+	}
+	*active = false
+	return marshalSubscribeResult(true, "")
+}
+
+// StreamListMessages enumerates every OnX method manta.Callbacks exposes, so
+// Python can discover valid StreamSubscribe names without reading Go source.
+//
+//export StreamListMessages
+func StreamListMessages() *C.char {
+	t := reflect.TypeOf(manta.Callbacks{})
+	names := make([]string, 0, t.NumMethod())
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		if strings.HasPrefix(m.Name, "On") {
+			names = append(names, strings.TrimPrefix(m.Name, "On"))
+		}
+	}
+	data, err := json.Marshal(names)
+	if err != nil {
+		return C.CString("[]")
+	}
+	return C.CString(string(data))
+}
+
+// bindCallbackByName looks up "On"+messageName on h.parser.Callbacks and
+// registers a handler of the matching func(*dota.X) error type that
+// forwards the decoded message to h.events as a StreamEvent, as long as
+// *active stays true.
+func bindCallbackByName(h *StreamHandle, messageName string, active *bool) error {
+	callbacksVal := reflect.ValueOf(h.parser.Callbacks)
+	method := callbacksVal.MethodByName("On" + messageName)
+	if !method.IsValid() {
+		return fmt.Errorf("unknown message type %q (see StreamListMessages)", messageName)
+	}
+
+	methodType := method.Type()
+	if methodType.NumIn() != 1 || methodType.In(0).Kind() != reflect.Func {
+		return fmt.Errorf("On%s has an unexpected signature", messageName)
+	}
+	handlerType := methodType.In(0)
+
+	handler := reflect.MakeFunc(handlerType, func(args []reflect.Value) []reflect.Value {
+		errType := handlerType.Out(0)
+		if !*active {
+			return []reflect.Value{reflect.Zero(errType)}
+		}
+
+		msg := args[0].Interface()
+		select {
+		case h.events <- StreamEvent{
+			Kind:    "message",
+			Tick:    int(h.parser.Tick),
+			NetTick: int(h.parser.NetTick),
+			Type:    messageName,
+			Data:    msg,
+		}:
+		case <-h.done:
+		}
+		return []reflect.Value{reflect.Zero(errType)}
+	})
+
+	method.Call([]reflect.Value{handler})
+	return nil
+}
+
+func marshalSubscribeResult(success bool, errMsg string) *C.char {
+	result := map[string]interface{}{"success": success}
+	if errMsg != "" {
+		result["error"] = errMsg
+	}
+	data, _ := json.Marshal(result)
+	return C.CString(string(data))
+}
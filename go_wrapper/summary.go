@@ -0,0 +1,204 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// buildSummary computes SummaryResult from an already-finalized
+// CombatLogResult - the "second in-memory pass over combatLogRaw after
+// finalizeCombatLog" this collector was asked for. It operates on
+// CombatLogResult.Entries rather than the raw buffer directly, since
+// finalizeCombatLog has already resolved hero/item names, team numbers,
+// and game time by the time this runs; only AssistPlayers still needs a
+// parser.LookupStringByIndex("CombatLogNames", ...) call, the same
+// resolution aggregate_stats.go's assistHeroNames does for its own
+// per-hero rollups, since CombatLogEntry doesn't carry resolved assist
+// names.
+func buildSummary(parser *manta.Parser, cl *CombatLogResult, config *SummaryConfig) *SummaryResult {
+	result := &SummaryResult{
+		Heroes: make(map[string]HeroSummary),
+		Teams:  make(map[int32]TeamSummary),
+	}
+
+	if cl == nil {
+		result.Error = "summary collector requires the combat_log collector to also be enabled"
+		return result
+	}
+
+	bucketSeconds := config.BucketSeconds
+	if bucketSeconds <= 0 {
+		bucketSeconds = 60
+	}
+
+	buckets := make(map[int]*Bucket)
+	bucketFor := func(gameTime float32) *Bucket {
+		idx := 0
+		if gameTime > 0 {
+			idx = int(gameTime) / bucketSeconds
+		}
+		b, ok := buckets[idx]
+		if !ok {
+			b = &Bucket{
+				StartTime: float32(idx * bucketSeconds),
+				EndTime:   float32((idx + 1) * bucketSeconds),
+			}
+			buckets[idx] = b
+		}
+		return b
+	}
+
+	heroFor := func(name string, team int32) *HeroSummary {
+		if name == "" {
+			return nil
+		}
+		h, ok := result.Heroes[name]
+		if !ok {
+			h = HeroSummary{HeroName: name}
+		}
+		if h.Team == 0 {
+			h.Team = team
+		}
+		result.Heroes[name] = h
+		hh := result.Heroes[name]
+		return &hh
+	}
+	saveHero := func(h *HeroSummary) {
+		if h != nil {
+			result.Heroes[h.HeroName] = *h
+		}
+	}
+	teamFor := func(team int32) *TeamSummary {
+		if team == 0 {
+			return nil
+		}
+		t, ok := result.Teams[team]
+		if !ok {
+			t = TeamSummary{Team: team}
+		}
+		result.Teams[team] = t
+		tt := result.Teams[team]
+		return &tt
+	}
+	saveTeam := func(t *TeamSummary) {
+		if t != nil {
+			result.Teams[t.Team] = *t
+		}
+	}
+
+	for _, e := range cl.Entries {
+		b := bucketFor(e.GameTime)
+
+		switch dota.DOTA_COMBATLOG_TYPES(e.Type) {
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_DAMAGE:
+			dmg := int64(e.Value)
+			if e.IsAttackerHero {
+				if h := heroFor(e.AttackerName, e.AttackerTeam); h != nil {
+					h.DamageDealt += dmg
+					if e.LastHits > h.LastHits {
+						h.LastHits = e.LastHits
+					}
+					saveHero(h)
+				}
+				if t := teamFor(e.AttackerTeam); t != nil {
+					t.DamageDealt += dmg
+					saveTeam(t)
+				}
+				b.DamageDealt += dmg
+			}
+			if e.IsTargetHero {
+				if h := heroFor(e.TargetName, e.TargetTeam); h != nil {
+					h.DamageTaken += dmg
+					saveHero(h)
+				}
+			}
+
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_HEAL:
+			if e.IsAttackerHero {
+				if h := heroFor(e.AttackerName, e.AttackerTeam); h != nil {
+					h.HealingDone += int64(e.Value)
+					saveHero(h)
+				}
+				if t := teamFor(e.AttackerTeam); t != nil {
+					t.HealingDone += int64(e.Value)
+					saveTeam(t)
+				}
+			}
+
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_DEATH:
+			if e.IsTargetHero {
+				if h := heroFor(e.TargetName, e.TargetTeam); h != nil {
+					h.Deaths++
+					saveHero(h)
+				}
+				if t := teamFor(e.TargetTeam); t != nil {
+					t.Deaths++
+					saveTeam(t)
+				}
+			}
+			if e.IsAttackerHero {
+				if h := heroFor(e.AttackerName, e.AttackerTeam); h != nil {
+					h.Kills++
+					saveHero(h)
+				}
+				if t := teamFor(e.AttackerTeam); t != nil {
+					t.Kills++
+					saveTeam(t)
+				}
+				b.Kills++
+			}
+			b.Deaths++
+			for _, ap := range e.AssistPlayers {
+				name, ok := parser.LookupStringByIndex("CombatLogNames", ap)
+				if !ok || name == "" {
+					continue
+				}
+				if h := heroFor(name, 0); h != nil {
+					h.Assists++
+					saveHero(h)
+				}
+			}
+
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_GOLD:
+			if e.IsTargetHero {
+				if h := heroFor(e.TargetName, e.TargetTeam); h != nil {
+					h.Gold += int64(e.Value)
+					saveHero(h)
+				}
+				if t := teamFor(e.TargetTeam); t != nil {
+					t.Gold += int64(e.Value)
+					saveTeam(t)
+				}
+				b.Gold += int64(e.Value)
+			}
+
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_XP:
+			if e.IsTargetHero {
+				if h := heroFor(e.TargetName, e.TargetTeam); h != nil {
+					h.XP += int64(e.Value)
+					saveHero(h)
+				}
+				if t := teamFor(e.TargetTeam); t != nil {
+					t.XP += int64(e.Value)
+					saveTeam(t)
+				}
+				b.XP += int64(e.Value)
+			}
+		}
+	}
+
+	indices := make([]int, 0, len(buckets))
+	for idx := range buckets {
+		indices = append(indices, idx)
+	}
+	sort.Ints(indices)
+	result.TimeSeries = make([]Bucket, 0, len(indices))
+	for _, idx := range indices {
+		result.TimeSeries = append(result.TimeSeries, *buckets[idx])
+	}
+
+	result.Success = true
+	return result
+}
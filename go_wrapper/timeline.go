@@ -0,0 +1,224 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// TimelineEvent is one entry in the ParseTimeline output. Category
+// distinguishes the event families ParseTimeline covers ("kill", "tower",
+// "roshan", "rune", "courier", "purchase", "ability", "snapshot"); Data
+// carries whatever fields are specific to that category.
+type TimelineEvent struct {
+	Category string      `json:"category"`
+	Tick     uint32      `json:"tick"`
+	GameTime float32     `json:"game_time"`
+	Data     interface{} `json:"data"`
+}
+
+// PlayerSnapshot is the periodic gold/XP/level sample ParseTimeline emits
+// once per player every snapshotIntervalTicks ticks.
+type PlayerSnapshot struct {
+	PlayerSlot int32  `json:"player_slot"`
+	HeroName   string `json:"hero_name"`
+	Gold       int32  `json:"gold"`
+	XP         int32  `json:"xp"`
+	Level      int32  `json:"level"`
+	LastHits   int32  `json:"last_hits"`
+	Denies     int32  `json:"denies"`
+}
+
+// TimelineResult is the ParseTimeline response envelope.
+type TimelineResult struct {
+	Events  []TimelineEvent `json:"events"`
+	Success bool            `json:"success"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// snapshotIntervalTicks is the sampling period for periodic player
+// snapshots (1 minute at TicksPerSecond).
+const snapshotIntervalTicks = uint32(60 * TicksPerSecond)
+
+// ParseTimeline walks the whole replay and extracts a time-stamped event
+// feed: hero kills/deaths/assists, tower/barracks/roshan deaths, rune
+// pickups, courier deaths, item purchases, and ability upgrades (all
+// decoded from CMsgDOTACombatLogEntry, the same source RunCombatLogParse
+// and aggregateStatsCollector use), plus a 1-minute gold/XP/level/last-hit
+// snapshot per hero sampled off OnEntity updates. Every event is stamped
+// with game_time computed from CDOTAGamerulesProxy, matching ParseChat.
+//
+//export ParseTimeline
+func ParseTimeline(filePath *C.char) (result *C.char) {
+	goFilePath := C.GoString(filePath)
+
+	timeline := &TimelineResult{
+		Events:  make([]TimelineEvent, 0),
+		Success: false,
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			timeline.Success = false
+			timeline.Error = fmt.Sprintf("panic during parsing: %v", r)
+			result = marshalTimelineResult(timeline)
+		}
+	}()
+
+	file, err := os.Open(goFilePath)
+	if err != nil {
+		timeline.Error = fmt.Sprintf("error opening file: %v", err)
+		return marshalTimelineResult(timeline)
+	}
+	defer file.Close()
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		timeline.Error = fmt.Sprintf("error creating parser: %v", err)
+		return marshalTimelineResult(timeline)
+	}
+
+	var gameTime, gameStartTime float32
+	lastSnapshotTick := make(map[int32]uint32)
+
+	parser.OnEntity(func(e *manta.Entity, op manta.EntityOp) error {
+		if e == nil {
+			return nil
+		}
+		className := e.GetClassName()
+
+		if className == "CDOTAGamerulesProxy" {
+			if t, ok := e.GetFloat32("m_pGameRules.m_fGameTime"); ok {
+				gameTime = t
+			}
+			if t, ok := e.GetFloat32("m_pGameRules.m_flGameStartTime"); ok {
+				gameStartTime = t
+			}
+			return nil
+		}
+
+		if !strings.Contains(className, "CDOTA_Unit_Hero_") {
+			return nil
+		}
+
+		playerID, ok := e.GetInt32("m_iPlayerID")
+		if !ok {
+			return nil
+		}
+		tick := parser.Tick
+		if tick-lastSnapshotTick[playerID] < snapshotIntervalTicks {
+			return nil
+		}
+		lastSnapshotTick[playerID] = tick
+
+		snapshot := PlayerSnapshot{PlayerSlot: playerID, HeroName: entityClassToHeroName(className)}
+		if gold, ok := e.GetInt32("m_iCurrentGold"); ok {
+			snapshot.Gold = gold
+		}
+		if level, ok := e.GetInt32("m_iCurrentLevel"); ok {
+			snapshot.Level = level
+		}
+		if lh, ok := e.GetInt32("m_iLastHitCount"); ok {
+			snapshot.LastHits = lh
+		}
+		if dn, ok := e.GetInt32("m_iDenyCount"); ok {
+			snapshot.Denies = dn
+		}
+
+		timeline.Events = append(timeline.Events, TimelineEvent{
+			Category: "snapshot",
+			Tick:     tick,
+			GameTime: gameTime - gameStartTime,
+			Data:     snapshot,
+		})
+		return nil
+	})
+
+	parser.Callbacks.OnCMsgDOTACombatLogEntry(func(m *dota.CMsgDOTACombatLogEntry) error {
+		category, data := classifyTimelineCombatLogEntry(parser, m)
+		if category == "" {
+			return nil
+		}
+		timeline.Events = append(timeline.Events, TimelineEvent{
+			Category: category,
+			Tick:     parser.Tick,
+			GameTime: gameTime - gameStartTime,
+			Data:     data,
+		})
+		return nil
+	})
+
+	if err := parser.Start(); err != nil {
+		timeline.Error = fmt.Sprintf("error parsing file: %v", err)
+		return marshalTimelineResult(timeline)
+	}
+
+	timeline.Success = true
+	return marshalTimelineResult(timeline)
+}
+
+// classifyTimelineCombatLogEntry buckets a combat log entry into one of
+// ParseTimeline's event categories, following the same DOTA_COMBATLOG_TYPES_*
+// switch aggregateStatsCollector.onCombatLogEntry and RunCombatLogParse use.
+// Returns an empty category for entry types ParseTimeline doesn't surface.
+func classifyTimelineCombatLogEntry(parser *manta.Parser, m *dota.CMsgDOTACombatLogEntry) (string, map[string]interface{}) {
+	// AttackerName/TargetName are CombatLogNames string table indices, not
+	// resolved strings - resolve them the same way PURCHASE's ItemName
+	// below (and data_parser.go/combat_log_iterator.go) already do.
+	attacker, _ := parser.LookupStringByIndex("CombatLogNames", int32(m.GetAttackerName()))
+	target, _ := parser.LookupStringByIndex("CombatLogNames", int32(m.GetTargetName()))
+
+	switch m.GetType() {
+	case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_DEATH:
+		if strings.Contains(target, "roshan") {
+			return "roshan", map[string]interface{}{"killer": attacker}
+		}
+		if strings.Contains(target, "tower") {
+			return "tower", map[string]interface{}{"tower": target, "killer": attacker}
+		}
+		if strings.Contains(target, "rax") || strings.Contains(target, "barracks") {
+			return "tower", map[string]interface{}{"tower": target, "killer": attacker}
+		}
+		if strings.Contains(target, "courier") {
+			return "courier", map[string]interface{}{"killer": attacker}
+		}
+		if strings.Contains(target, "hero") {
+			return "kill", map[string]interface{}{
+				"victim":  target,
+				"killer":  attacker,
+				"assists": assistHeroNames(parser, m),
+			}
+		}
+		return "", nil
+
+	case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_PURCHASE:
+		itemName, _ := parser.LookupStringByIndex("CombatLogNames", int32(m.GetValue()))
+		return "purchase", map[string]interface{}{"hero": target, "item": itemName}
+
+	case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_ABILITY:
+		abilityName, _ := parser.LookupStringByIndex("CombatLogNames", int32(m.GetInflictorName()))
+		return "ability", map[string]interface{}{"hero": attacker, "ability": abilityName}
+
+	case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_RUNE_PICKUP:
+		return "rune", map[string]interface{}{"hero": attacker, "rune": int32(m.GetValue())}
+
+	default:
+		return "", nil
+	}
+}
+
+func marshalTimelineResult(r *TimelineResult) *C.char {
+	data, err := json.Marshal(r)
+	if err != nil {
+		data, _ = json.Marshal(&TimelineResult{Success: false, Error: err.Error()})
+	}
+	return C.CString(string(data))
+}
@@ -1,5 +1,7 @@
 package main
 
+import "github.com/dotabuff/manta/dota"
+
 // ParseConfig specifies which collectors to enable and their options.
 // All fields are optional - omitted collectors won't run.
 type ParseConfig struct {
@@ -35,6 +37,33 @@ type ParseConfig struct {
 
 	// Entity deaths collector (tracks entity removals)
 	EntityDeaths *EntityDeathsConfig `json:"entity_deaths,omitempty"`
+
+	// Aggregate stats collector (per-hero combat log rollups)
+	AggregateStats *AggregateStatsConfig `json:"aggregate_stats,omitempty"`
+
+	// Unit orders collector
+	UnitOrders *UnitOrdersConfig `json:"unit_orders,omitempty"`
+
+	// Chat messages collector
+	Chat *ChatMessagesConfig `json:"chat,omitempty"`
+
+	// Map events collector (location pings, map lines, minimap events)
+	MapEvents *MapEventsConfig `json:"map_events,omitempty"`
+
+	// Summary collector (per-hero/per-team combat log rollups). Requires
+	// CombatLog to also be set - see SummaryConfig's doc comment.
+	Summary *SummaryConfig `json:"summary,omitempty"`
+
+	// Projectiles collector (full create/destroy/dodge lifecycle,
+	// alongside the narrower TE_Projectile-only Attacks collector)
+	Projectiles *ProjectilesConfig `json:"projectiles,omitempty"`
+
+	// Economy collector (build order, skill order, periodic inventory/
+	// net-worth snapshots). See EconomyConfig's doc comment.
+	Economy *EconomyConfig `json:"economy,omitempty"`
+
+	// Vision collector (ward placement/expiration and team vision coverage)
+	Vision *VisionConfig `json:"vision,omitempty"`
 }
 
 // HeaderCollectorConfig - header is simple, just enable/disable
@@ -75,16 +104,166 @@ type ParseResult struct {
 	ParserInfo   *ParserInfo         `json:"parser_info,omitempty"`
 	Attacks      *AttacksResult      `json:"attacks,omitempty"`
 	EntityDeaths *EntityDeathsResult `json:"entity_deaths,omitempty"`
+
+	AggregateStats *AggregateStatsResult `json:"aggregate_stats,omitempty"`
+	UnitOrders     *UnitOrdersResult     `json:"unit_orders,omitempty"`
+	Chat           *ChatMessagesResult   `json:"chat,omitempty"`
+	MapEvents      *MapEventsResult      `json:"map_events,omitempty"`
+	Summary        *SummaryResult        `json:"summary,omitempty"`
+	Projectiles    *ProjectilesResult    `json:"projectiles,omitempty"`
+	Economy        *EconomyResult        `json:"economy,omitempty"`
+	Vision         *VisionResult         `json:"vision,omitempty"`
+
+	// ChatMessages is captured alongside the CombatLog collector (see the
+	// OnCUserMessageSayText2 handler in the combat log collector block) -
+	// a narrower, combat-log-gated sibling of the dedicated Chat collector
+	// (config.Chat/ChatMessageEntry in chat_messages.go).
+	ChatMessages []CombatLogChatEntry `json:"chat_messages,omitempty"`
+
+	// EconomyTimeline is built from the CombatLog collector when
+	// CombatLogConfig.IncludeEconomyTimeline is set - keyed by player slot,
+	// see EconomySample's doc comment for how it differs from Economy above.
+	EconomyTimeline map[int][]EconomySample `json:"economy_timeline,omitempty"`
+}
+
+// CombatLogChatEntry is one CUserMessageSayText2 line captured while the
+// CombatLog collector runs. It's deliberately narrower than
+// ChatMessageEntry (chat_messages.go's dedicated Chat collector) and a
+// distinct type from chat_parser.go's standalone ParseChat ChatMessage:
+// sender identity here comes from the hero entities the CombatLog
+// collector already tracks for hero-level enrichment (see
+// heroNameByPlayerID/heroTeamByPlayerID in RunParse) rather than
+// CDemoFileInfo's player list, so a message only resolves a sender
+// name/team once that player's hero entity has been seen at least once.
+type CombatLogChatEntry struct {
+	Tick       uint32  `json:"tick"`
+	GameTime   float32 `json:"game_time"`
+	SenderSlot int32   `json:"sender_slot"`
+	SenderName string  `json:"sender_name"`
+	Team       int32   `json:"team"`
+	IsAllChat  bool    `json:"is_all_chat"`
+	Message    string  `json:"message"`
+	Channel    int32   `json:"channel"`
+}
+
+// CombatLogStats is a rollup over CombatLogResult.Entries, computed once the
+// heroes_only filter has already been applied - it exists so callers that
+// only need totals (the kind of numbers external test fixtures call
+// expectCombatLogDamage/Healing/Deaths/Events) don't have to walk Entries
+// themselves. Per-hero-vs-hero attribution lives on AggregateStatsResult's
+// MatchupMatrix already (a separate, opt-in collector); this rollup sticks
+// to per-hero totals plus the per-ability and per-minute breakdowns that
+// collector doesn't offer.
+type CombatLogStats struct {
+	TotalDamage  int64 `json:"total_damage"`
+	TotalHealing int64 `json:"total_healing"`
+	TotalDeaths  int   `json:"total_deaths"`
+	TotalEvents  int   `json:"total_events"`
+
+	GoldByHero map[string]int64 `json:"gold_by_hero"` // hero name -> gold gained (DOTA_COMBATLOG_GOLD, keyed by TargetName)
+	XPByHero   map[string]int64 `json:"xp_by_hero"`    // hero name -> XP gained (DOTA_COMBATLOG_XP, keyed by TargetName)
+
+	DamageDealtByHero map[string]int64 `json:"damage_dealt_by_hero"`
+	DamageTakenByHero map[string]int64 `json:"damage_taken_by_hero"`
+	HealingByHero     map[string]int64 `json:"healing_by_hero"`
+
+	// DamageByAbility attributes DAMAGE entries to InflictorName (the
+	// ability/item tooltip name), e.g. "storm_spirit_static_remnant".
+	DamageByAbility map[string]int64 `json:"damage_by_ability"`
+
+	// DamageByMinute buckets DAMAGE entries by floor(GameTime/60), so index
+	// 0 is the first minute after the horn, negative keys are pre-game.
+	DamageByMinute map[int]int64 `json:"damage_by_minute"`
+}
+
+// computeCombatLogStats rolls up already-filtered, already-resolved combat
+// log entries into a CombatLogStats. Called from finalizeCombatLog after
+// the heroes_only filter has been applied, so every entry here already
+// passed that filter.
+func computeCombatLogStats(entries []CombatLogEntry) *CombatLogStats {
+	stats := &CombatLogStats{
+		GoldByHero:        make(map[string]int64),
+		XPByHero:          make(map[string]int64),
+		DamageDealtByHero: make(map[string]int64),
+		DamageTakenByHero: make(map[string]int64),
+		HealingByHero:     make(map[string]int64),
+		DamageByAbility:   make(map[string]int64),
+		DamageByMinute:    make(map[int]int64),
+	}
+
+	for _, entry := range entries {
+		stats.TotalEvents++
+
+		switch dota.DOTA_COMBATLOG_TYPES(entry.Type) {
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_DAMAGE:
+			dmg := int64(entry.Value)
+			stats.TotalDamage += dmg
+			if entry.AttackerName != "" {
+				stats.DamageDealtByHero[entry.AttackerName] += dmg
+			}
+			if entry.TargetName != "" {
+				stats.DamageTakenByHero[entry.TargetName] += dmg
+			}
+			if entry.InflictorName != "" {
+				stats.DamageByAbility[entry.InflictorName] += dmg
+			}
+			minute := int(entry.GameTime) / 60
+			if entry.GameTime < 0 {
+				minute--
+			}
+			stats.DamageByMinute[minute] += dmg
+
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_HEAL:
+			heal := int64(entry.Value)
+			stats.TotalHealing += heal
+			if entry.AttackerName != "" {
+				stats.HealingByHero[entry.AttackerName] += heal
+			}
+
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_DEATH:
+			stats.TotalDeaths++
+
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_GOLD:
+			if entry.TargetName != "" {
+				stats.GoldByHero[entry.TargetName] += int64(entry.Value)
+			}
+
+		case dota.DOTA_COMBATLOG_TYPES_DOTA_COMBATLOG_XP:
+			if entry.TargetName != "" {
+				stats.XPByHero[entry.TargetName] += int64(entry.Value)
+			}
+		}
+	}
+
+	return stats
+}
+
+// EconomySample is one fixed-interval bucket of a player's economy curve,
+// built from the GPM/XPM/Networth/LastHits/AttackerHeroLevel already
+// carried on CombatLogEntry - unlike the discrete per-event values those
+// fields hold, this turns them into an evenly-spaced time series a caller
+// can plot directly. It's a separate, coarser-grained series from
+// economyCollector's own EconomySnapshot (chunk10-6's build/skill order
+// plus inventory snapshots): this one is driven entirely off the combat
+// log pass and keyed by player slot rather than hero name.
+type EconomySample struct {
+	Tick      uint32  `json:"tick"`       // tick of the last entry folded into this bucket
+	GameTime  float32 `json:"game_time"`  // bucket start, i.e. bucketIndex * interval seconds
+	NetWorth  int32   `json:"net_worth"`  // max networth seen in this bucket
+	GPM       int32   `json:"gpm"`        // last gpm seen in this bucket
+	XPM       int32   `json:"xpm"`        // last xpm seen in this bucket
+	LastHits  int32   `json:"last_hits"`  // max last_hits seen in this bucket
+	HeroLevel int32   `json:"hero_level"` // last hero level seen in this bucket (backfilled from entity state when the protobuf value is 0)
 }
 
 // UniversalResult matches the existing universal parse result structure
 type UniversalResult struct {
-	Messages       []MessageEvent `json:"messages"`
-	Success        bool           `json:"success"`
-	Error          string         `json:"error,omitempty"`
-	TotalMessages  int            `json:"total_messages"`
-	FilteredCount  int            `json:"filtered_count"`
-	CallbacksUsed  []string       `json:"callbacks_used"`
+	Messages      []MessageEvent `json:"messages"`
+	Success       bool           `json:"success"`
+	Error         string         `json:"error,omitempty"`
+	TotalMessages int            `json:"total_messages"`
+	FilteredCount int            `json:"filtered_count"`
+	CallbacksUsed []string       `json:"callbacks_used"`
 }
 
 // AbilitySnapshot captures an ability's state (shared between entity_parser and index)
@@ -97,6 +276,15 @@ type AbilitySnapshot struct {
 	ManaCost    int     `json:"mana_cost"`
 	Charges     int     `json:"charges"`
 	IsUltimate  bool    `json:"is_ultimate"`
+
+	// The fields below are only populated when a AbilityRegistry data pack
+	// is loaded (see ability_registry.go) and has an entry for Name; they're
+	// left zero-valued otherwise, same as every other best-effort field in
+	// this struct.
+	IsShared   bool     `json:"is_shared,omitempty"`
+	Behavior   []string `json:"behavior,omitempty"`
+	TargetType string   `json:"target_type,omitempty"`
+	DamageType string   `json:"damage_type,omitempty"`
 }
 
 // TalentChoice captures a talent tier selection
@@ -160,28 +348,63 @@ type HeroSnapshot struct {
 	Talents       []TalentChoice    `json:"talents,omitempty"`
 	AbilityPoints int               `json:"ability_points"`
 
+	// Modifiers/buffs, populated only when EntityParseConfig.IncludeModifiers
+	// is set
+	Modifiers []ModifierSnapshot `json:"modifiers,omitempty"`
+
 	// Clone/illusion flags
 	IsClone    bool `json:"is_clone,omitempty"`
 	IsIllusion bool `json:"is_illusion,omitempty"`
 }
 
+// ModifierSnapshot is one active modifier/buff on a hero at snapshot time -
+// sourced from the same CDOTAModifierBuffTableEntry stream
+// modifiers_stream.go's ModifierEntry already decodes, filtered to the
+// parent hero and still active as of the snapshot's game time. The
+// IsStun/IsSilence/IsHex/IsRoot/IsSlow/IsShield flags come from a loaded
+// ModifierRegistry (modifier_registry.go) when one is set, or a
+// name-substring heuristic otherwise - the same registry-with-fallback
+// pattern AbilityMetadata (ability_registry.go) already uses.
+type ModifierSnapshot struct {
+	Name          string  `json:"name"`
+	CasterName    string  `json:"caster_name,omitempty"`
+	StackCount    int32   `json:"stack_count,omitempty"`
+	Duration      float32 `json:"duration,omitempty"`       // <=0 means indefinite
+	RemainingTime float32 `json:"remaining_time,omitempty"` // only set when Duration > 0
+	IsAura        bool    `json:"is_aura,omitempty"`
+	IsDebuff      bool    `json:"is_debuff,omitempty"`
+	IsStun        bool    `json:"is_stun,omitempty"`
+	IsSilence     bool    `json:"is_silence,omitempty"`
+	IsHex         bool    `json:"is_hex,omitempty"`
+	IsRoot        bool    `json:"is_root,omitempty"`
+	IsSlow        bool    `json:"is_slow,omitempty"`
+	IsShield      bool    `json:"is_shield,omitempty"`
+}
+
 // AttacksConfig controls attack event parsing from TE_Projectile
 type AttacksConfig struct {
 	MaxEvents int `json:"max_events"` // Max events (0 = unlimited)
+
+	// Fields/EnumFormat mirror CombatLogConfig's knobs so ParseAttacks'
+	// JSON encoding policy stays consistent with ParseCombatLog's - the
+	// same EncodeWithProjection powers both. AttackEvent has no enum-like
+	// fields, so EnumFormat is accepted but has nothing to act on.
+	Fields     []string `json:"fields"`
+	EnumFormat string   `json:"enum_format"`
 }
 
 // AttackEvent represents a single attack projectile
 type AttackEvent struct {
 	Tick            int     `json:"tick"`
-	SourceIndex     int     `json:"source_index"`      // Entity index of attacker
-	TargetIndex     int     `json:"target_index"`      // Entity index of target
-	SourceHandle    int64   `json:"source_handle"`     // Raw entity handle
-	TargetHandle    int64   `json:"target_handle"`     // Raw entity handle
-	ProjectileSpeed int     `json:"projectile_speed"`  // Projectile move speed
-	Dodgeable       bool    `json:"dodgeable"`         // Can be dodged/disjointed
-	LaunchTick      int     `json:"launch_tick"`       // When projectile was launched
-	GameTime        float32 `json:"game_time"`         // Game time in seconds
-	GameTimeStr     string  `json:"game_time_str"`     // Formatted game time
+	SourceIndex     int     `json:"source_index"`     // Entity index of attacker
+	TargetIndex     int     `json:"target_index"`     // Entity index of target
+	SourceHandle    int64   `json:"source_handle"`    // Raw entity handle
+	TargetHandle    int64   `json:"target_handle"`    // Raw entity handle
+	ProjectileSpeed int     `json:"projectile_speed"` // Projectile move speed
+	Dodgeable       bool    `json:"dodgeable"`        // Can be dodged/disjointed
+	LaunchTick      int     `json:"launch_tick"`      // When projectile was launched
+	GameTime        float32 `json:"game_time"`        // Game time in seconds
+	GameTimeStr     string  `json:"game_time_str"`    // Formatted game time
 }
 
 // AttacksResult contains all attack events from TE_Projectile
@@ -192,10 +415,10 @@ type AttacksResult struct {
 
 // EntityDeathsConfig controls entity death tracking
 type EntityDeathsConfig struct {
-	MaxEvents     int  `json:"max_events"`      // Max events (0 = unlimited)
-	HeroesOnly    bool `json:"heroes_only"`     // Only track hero deaths
-	CreepsOnly    bool `json:"creeps_only"`     // Only track creep deaths
-	IncludeCreeps bool `json:"include_creeps"`  // Include creeps (default false for performance)
+	MaxEvents     int  `json:"max_events"`     // Max events (0 = unlimited)
+	HeroesOnly    bool `json:"heroes_only"`    // Only track hero deaths
+	CreepsOnly    bool `json:"creeps_only"`    // Only track creep deaths
+	IncludeCreeps bool `json:"include_creeps"` // Include creeps (default false for performance)
 }
 
 // EntityDeath represents an entity being removed from the game
@@ -203,16 +426,16 @@ type EntityDeath struct {
 	Tick        int     `json:"tick"`
 	EntityID    int     `json:"entity_id"`
 	ClassName   string  `json:"class_name"`
-	Name        string  `json:"name"`          // e.g., "npc_dota_hero_juggernaut" or "npc_dota_creep_goodguys_melee"
-	Team        int     `json:"team"`          // 2=Radiant, 3=Dire
-	X           float32 `json:"x"`             // Last known position
+	Name        string  `json:"name"` // e.g., "npc_dota_hero_juggernaut" or "npc_dota_creep_goodguys_melee"
+	Team        int     `json:"team"` // 2=Radiant, 3=Dire
+	X           float32 `json:"x"`    // Last known position
 	Y           float32 `json:"y"`
-	Health      int     `json:"health"`        // Health at time of removal (usually 0)
+	Health      int     `json:"health"` // Health at time of removal (usually 0)
 	MaxHealth   int     `json:"max_health"`
 	IsHero      bool    `json:"is_hero"`
 	IsCreep     bool    `json:"is_creep"`
 	IsBuilding  bool    `json:"is_building"`
-	IsNeutral   bool    `json:"is_neutral"`    // Neutral creep
+	IsNeutral   bool    `json:"is_neutral"` // Neutral creep
 	GameTime    float32 `json:"game_time"`
 	GameTimeStr string  `json:"game_time_str"`
 }
@@ -222,3 +445,185 @@ type EntityDeathsResult struct {
 	Events      []EntityDeath `json:"events"`
 	TotalEvents int           `json:"total_events"`
 }
+
+// MapEventsConfig controls the location ping / map line / minimap event
+// collector. Like EntityDeathsConfig, MaxEvents caps the collected count;
+// unlike it, the remaining filters narrow by sender and by time rather
+// than by entity classification, since map events aren't classed by unit.
+type MapEventsConfig struct {
+	MaxEvents int `json:"max_events"` // Max events (0 = unlimited)
+
+	// PlayerSlots restricts output to events from these sender slots;
+	// empty means no filter.
+	PlayerSlots []int32 `json:"player_slots"`
+
+	// EventKinds restricts output to these Kind values (e.g.
+	// "location_ping", "map_line", "minimap_event", "minikillcam");
+	// empty means no filter, the same "absent = all" convention
+	// ChatMessagesConfig.MessageTypes uses.
+	EventKinds []string `json:"event_kinds"`
+
+	// StartTick/EndTick restrict collection to a tick range, the same
+	// convention CombatLogAggregateConfig uses. EndTick <= 0 means no
+	// upper bound.
+	StartTick int `json:"start_tick"`
+	EndTick   int `json:"end_tick"`
+}
+
+// MapEvent is one location ping / map line / minimap event / kill-cam
+// record. Fields that don't apply to a given Kind are left at their zero
+// value rather than split into per-kind structs, matching how
+// ChatMessageEntry shares one shape across say_text2/chat_wheel/chat_event.
+type MapEvent struct {
+	Tick        int     `json:"tick"`
+	GameTime    float32 `json:"game_time"`
+	GameTimeStr string  `json:"game_time_str"`
+	PlayerSlot  int32   `json:"player_slot"`
+
+	// Kind is "location_ping", "map_line", "minimap_event", or
+	// "minikillcam".
+	Kind string `json:"kind"`
+
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+
+	// TargetIndex/TargetHandle decode a pinged/highlighted entity's
+	// handle the same way AttacksResult's SourceIndex/SourceHandle do
+	// (lower 14 bits of the handle are the entity index).
+	TargetIndex  int   `json:"target_index,omitempty"`
+	TargetHandle int64 `json:"target_handle,omitempty"`
+
+	// PingType is LocationPing's EDotaEntityPingType value; only set for
+	// Kind=="location_ping".
+	PingType int32 `json:"ping_type,omitempty"`
+
+	// EventType is MinimapEvent's raw event type; only set for
+	// Kind=="minimap_event".
+	EventType int32 `json:"event_type,omitempty"`
+}
+
+// MapEventsResult contains all collected map/ping events.
+type MapEventsResult struct {
+	Events      []MapEvent `json:"events"`
+	TotalEvents int        `json:"total_events"`
+}
+
+// SummaryConfig controls the Dotabuff-style scoreboard collector: it
+// computes per-hero/per-team rollups and a fixed-interval time series as
+// a second pass over the already-resolved CombatLog entries, so callers
+// get a scoreboard from one Parse() call instead of post-processing
+// CombatLogResult themselves the way aggregate_stats.go's PlayerAggregate/
+// MatchupMatrix already lets them do for damage-by-target specifically.
+// Summary only runs when config.CombatLog is also set - it has nothing to
+// aggregate over otherwise, and RunParse reports that as an error on
+// SummaryResult rather than silently returning an empty one.
+type SummaryConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// BucketSeconds sets the TimeSeries interval; 0 defaults to 60 (one
+	// minute), matching the "like 60s" example this was requested with.
+	BucketSeconds int `json:"bucket_seconds"`
+}
+
+// HeroSummary is one hero's scoreboard row.
+type HeroSummary struct {
+	HeroName    string `json:"hero_name"`
+	Team        int32  `json:"team"`
+	DamageDealt int64  `json:"damage_dealt"`
+	DamageTaken int64  `json:"damage_taken"`
+	HealingDone int64  `json:"healing_done"`
+	Kills       int    `json:"kills"`
+	Deaths      int    `json:"deaths"`
+	Assists     int    `json:"assists"`
+	XP          int64  `json:"xp"`
+	Gold        int64  `json:"gold"`
+
+	// LastHits is the highest last-hit count CombatLogEntry.LastHits
+	// reported for this hero as attacker. Denies aren't tracked here:
+	// the combat log doesn't carry a separate deny entry type or flag
+	// distinguishing a deny from a last hit, so there's nothing to
+	// aggregate without guessing at a heuristic.
+	LastHits int `json:"last_hits"`
+}
+
+// TeamSummary is one team's (2=Radiant, 3=Dire) scoreboard row.
+type TeamSummary struct {
+	Team        int32 `json:"team"`
+	Kills       int   `json:"kills"`
+	Deaths      int   `json:"deaths"`
+	DamageDealt int64 `json:"damage_dealt"`
+	HealingDone int64 `json:"healing_done"`
+	Gold        int64 `json:"gold"`
+	XP          int64 `json:"xp"`
+}
+
+// Bucket is one fixed-interval slice of TimeSeries.
+type Bucket struct {
+	StartTime   float32 `json:"start_time"`
+	EndTime     float32 `json:"end_time"`
+	DamageDealt int64   `json:"damage_dealt"`
+	Kills       int     `json:"kills"`
+	Deaths      int     `json:"deaths"`
+	Gold        int64   `json:"gold"`
+	XP          int64   `json:"xp"`
+}
+
+// SummaryResult is the per-hero/per-team scoreboard plus time series.
+type SummaryResult struct {
+	Heroes     map[string]HeroSummary `json:"heroes"`
+	Teams      map[int32]TeamSummary  `json:"teams"`
+	TimeSeries []Bucket               `json:"time_series"`
+	Success    bool                   `json:"success"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// ProjectilesConfig controls the full projectile lifecycle collector.
+type ProjectilesConfig struct {
+	MaxEvents int `json:"max_events"` // Max events (0 = unlimited)
+}
+
+// ProjectileEvent is one lifecycle event for a tracked projectile.
+// Fields that don't apply to a given LifecycleStage are left at their
+// zero value, matching the shared-shape convention MapEvent/
+// ChatMessageEntry already use across this package's other collectors.
+type ProjectileEvent struct {
+	Tick        int     `json:"tick"`
+	GameTime    float32 `json:"game_time"`
+	GameTimeStr string  `json:"game_time_str"`
+
+	// ProjectileID correlates created/dodged/destroyed events for the
+	// same projectile. It's 0 for "attack" events, since TE_Projectile
+	// (unlike CreateLinearProjectile/DestroyLinearProjectile/
+	// DodgeTrackingProjectiles) carries no projectile ID to correlate by.
+	ProjectileID uint32 `json:"projectile_id,omitempty"`
+
+	// LifecycleStage is "created", "attack", "dodged", or "destroyed".
+	LifecycleStage string `json:"lifecycle_stage"`
+
+	SourceIndex  int   `json:"source_index"`
+	TargetIndex  int   `json:"target_index,omitempty"`
+	SourceHandle int64 `json:"source_handle,omitempty"`
+	TargetHandle int64 `json:"target_handle,omitempty"`
+
+	LaunchX float32 `json:"launch_x,omitempty"`
+	LaunchY float32 `json:"launch_y,omitempty"`
+
+	// TotalDistance is only set on "destroyed"/"dodged" events, estimated
+	// as launch speed * elapsed flight time since a linear projectile's
+	// destroy/dodge message carries no impact position of its own to
+	// measure directly - see projectiles.go's doc comment.
+	TotalDistance float32 `json:"total_distance,omitempty"`
+
+	Dodgeable bool `json:"dodgeable,omitempty"`
+
+	// AbilityIndex decodes CreateLinearProjectile's ability handle the
+	// same way TargetHandle/SourceHandle decode entity handles (lower 14
+	// bits); 0 if the creating message carried no ability handle.
+	AbilityIndex int `json:"ability_index,omitempty"`
+}
+
+// ProjectilesResult contains all projectile lifecycle events.
+type ProjectilesResult struct {
+	Events      []ProjectileEvent `json:"events"`
+	TotalEvents int               `json:"total_events"`
+}
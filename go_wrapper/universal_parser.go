@@ -23,6 +23,7 @@ type UniversalParseResult struct {
 // MessageEvent represents any Manta message with metadata
 type MessageEvent struct {
 	Type      string      `json:"type"`       // Message type name (e.g., "CDemoFileHeader")
+	ID        MessageID   `json:"id,omitempty"` // messages.go registry ID for Type, 0 if Type isn't in messageRegistry
 	Tick      uint32      `json:"tick"`       // Tick when message occurred
 	NetTick   uint32      `json:"net_tick"`   // Net tick when message occurred
 	Data      interface{} `json:"data"`       // Raw message data
@@ -378,13 +379,20 @@ func addFilteredMessage(messages *[]MessageEvent, msgType string, tick, netTick
 		return nil
 	}
 	
-	// Apply filter if specified
+	// Apply filter if specified - accept either a substring of msgType (the
+	// original behavior) or a messages.go registry Name/ProtoName, so a
+	// caller can filter by "EDotaUserMessages_CDOTAUserMsg_ChatMessage" or
+	// plain "CDOTAUserMsg_ChatMessage" interchangeably.
 	if filter != "" && !strings.Contains(msgType, filter) {
-		return nil
+		if id, ok := NameToID(filter); !ok || IDToProtoName(id) != msgType {
+			return nil
+		}
 	}
-	
+
+	id, _ := NameToID(msgType)
 	*messages = append(*messages, MessageEvent{
 		Type:      msgType,
+		ID:        id,
 		Tick:      tick,
 		NetTick:   netTick,
 		Data:      data,
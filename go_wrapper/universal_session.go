@@ -0,0 +1,211 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/dotabuff/manta"
+	"github.com/dotabuff/manta/dota"
+)
+
+// parseSession is a single OpenParseSession/NextMessage/CloseParseSession run.
+// The parser executes on its own goroutine and pushes MessageEvents into a
+// bounded channel; NextMessage pops one event per call, so maxMessages acts
+// as real backpressure on the producer instead of a post-hoc slice truncation
+// like RunUniversal's.
+type parseSession struct {
+	file    *os.File
+	events  chan MessageEvent
+	done    chan struct{}
+	err     atomic.Value // error
+	closed  atomic.Bool
+	metrics *parseMetrics
+	start   time.Time
+}
+
+// sessions holds every open parse session, keyed by the handle returned from
+// OpenParseSession. Handles are opaque uint64s so Python can treat them as a
+// context-manager token without reaching into Go internals.
+var (
+	sessions   sync.Map // map[uint64]*parseSession
+	sessionSeq uint64
+)
+
+// sessionChannelDepth bounds how many parsed-but-unconsumed MessageEvents can
+// queue up before the parse goroutine blocks on the channel send.
+const sessionChannelDepth = 256
+
+// sessionOptions mirrors the JSON object Python passes as OpenParseSession's
+// options argument.
+type sessionOptions struct {
+	MaxMessages int `json:"max_messages"`
+}
+
+//export OpenParseSession
+func OpenParseSession(filePath *C.char, filter *C.char, options *C.char) C.ulonglong {
+	goFilePath := C.GoString(filePath)
+	goFilter := C.GoString(filter)
+	goOptions := C.GoString(options)
+
+	var opts sessionOptions
+	if goOptions != "" {
+		// Best-effort: a malformed options blob just falls back to unlimited.
+		_ = json.Unmarshal([]byte(goOptions), &opts)
+	}
+
+	file, err := os.Open(goFilePath)
+	if err != nil {
+		return 0
+	}
+
+	parser, err := manta.NewStreamParser(file)
+	if err != nil {
+		file.Close()
+		return 0
+	}
+
+	sess := &parseSession{
+		file:    file,
+		events:  make(chan MessageEvent, sessionChannelDepth),
+		done:    make(chan struct{}),
+		metrics: newParseMetrics(),
+		start:   time.Now(),
+	}
+
+	handle := atomic.AddUint64(&sessionSeq, 1)
+	sessions.Store(handle, sess)
+
+	go runParseSession(sess, parser, goFilter, opts.MaxMessages)
+
+	return C.ulonglong(handle)
+}
+
+// runParseSession drives the parser on its own goroutine, pushing every
+// message that passes the filter into sess.events until maxMessages is hit,
+// the caller closes the session, or the demo runs out. It registers the same
+// commonly-consumed callback set as setupDemoCallbacks/setupUserCallbacks so
+// streaming sessions cover the message types Python callers actually ask for
+// first; see setupAllCallbacks for the full (buffering) equivalent.
+func runParseSession(sess *parseSession, parser *manta.Parser, filter string, maxMessages int) {
+	defer func() {
+		if r := recover(); r != nil {
+			sess.err.Store(fmt.Errorf("panic during parsing: %v", r))
+		}
+		close(sess.events)
+	}()
+
+	emitted := 0
+	emit := func(msgType string, data interface{}) error {
+		start := time.Now()
+		defer sess.metrics.record(msgType, time.Since(start), parser.Tick)
+
+		if maxMessages > 0 && emitted >= maxMessages {
+			return nil
+		}
+		if filter != "" && !strings.Contains(msgType, filter) {
+			return nil
+		}
+		event := MessageEvent{
+			Type:      msgType,
+			Tick:      parser.Tick,
+			NetTick:   parser.NetTick,
+			Data:      data,
+			Timestamp: time.Now().UnixMilli(),
+		}
+		select {
+		case sess.events <- event:
+			emitted++
+			if maxMessages > 0 && emitted >= maxMessages {
+				parser.Stop()
+			}
+			return nil
+		case <-sess.done:
+			parser.Stop()
+			return fmt.Errorf("session closed")
+		}
+	}
+
+	parser.Callbacks.OnCDemoFileHeader(func(m *dota.CDemoFileHeader) error {
+		return emit("CDemoFileHeader", m)
+	})
+	parser.Callbacks.OnCDemoFileInfo(func(m *dota.CDemoFileInfo) error {
+		return emit("CDemoFileInfo", m)
+	})
+	parser.Callbacks.OnCMsgDOTACombatLogEntry(func(m *dota.CMsgDOTACombatLogEntry) error {
+		return emit("CMsgDOTACombatLogEntry", m)
+	})
+	parser.Callbacks.OnCSVCMsg_PacketEntities(func(m *dota.CSVCMsg_PacketEntities) error {
+		return emit("CSVCMsg_PacketEntities", m)
+	})
+	parser.Callbacks.OnCDOTAUserMsg_ChatMessage(func(m *dota.CDOTAUserMsg_ChatMessage) error {
+		return emit("CDOTAUserMsg_ChatMessage", m)
+	})
+	parser.Callbacks.OnCDOTAUserMsg_ChatEvent(func(m *dota.CDOTAUserMsg_ChatEvent) error {
+		return emit("CDOTAUserMsg_ChatEvent", m)
+	})
+
+	if err := parser.Start(); err != nil {
+		sess.err.Store(fmt.Errorf("error parsing file: %w", err))
+	}
+}
+
+//export NextMessage
+func NextMessage(handle C.ulonglong, bufPtr *C.char, bufLen C.int) C.int {
+	v, ok := sessions.Load(uint64(handle))
+	if !ok {
+		return -1
+	}
+	sess := v.(*parseSession)
+
+	event, ok := <-sess.events
+	if !ok {
+		if err, _ := sess.err.Load().(error); err != nil {
+			return -1
+		}
+		return 0 // stream exhausted, nothing left to deliver
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return -1
+	}
+
+	needed := len(payload)
+	if needed > int(bufLen) {
+		// Buffer too small: report the required size as a negative length so
+		// the caller can grow its buffer and retry without losing the event.
+		return C.int(-needed)
+	}
+
+	if needed > 0 {
+		dst := unsafe.Slice((*byte)(unsafe.Pointer(bufPtr)), needed)
+		copy(dst, payload)
+	}
+	return C.int(needed)
+}
+
+//export CloseParseSession
+func CloseParseSession(handle C.ulonglong) {
+	v, ok := sessions.LoadAndDelete(uint64(handle))
+	if !ok {
+		return
+	}
+	sess := v.(*parseSession)
+	if sess.closed.CompareAndSwap(false, true) {
+		close(sess.done)
+	}
+	if sess.file != nil {
+		sess.file.Close()
+	}
+}
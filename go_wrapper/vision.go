@@ -0,0 +1,221 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/dotabuff/manta"
+)
+
+// VisionConfig controls the ward placement/expiration collector.
+type VisionConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// WardEvent is one ward lifecycle event: a placement, or its later
+// expiration/kill.
+type WardEvent struct {
+	Tick           int     `json:"tick"`
+	GameTime       float32 `json:"game_time"`
+	WardIndex      int     `json:"ward_index"`
+	WardType       string  `json:"ward_type"` // "observer" or "sentry"
+	LifecycleStage string  `json:"lifecycle_stage"` // "placed", "expired", "killed"
+	Team           int32   `json:"team"`
+	X              float32 `json:"x,omitempty"`
+	Y              float32 `json:"y,omitempty"`
+	PlacerIndex    int     `json:"placer_index,omitempty"`
+	PlacerHandle   int64   `json:"placer_handle,omitempty"`
+
+	// DurationRemaining is only set on a "placed" event: the nominal ward
+	// lifetime in seconds (see nominalWardDuration's doc comment - it's a
+	// hardcoded constant, not read off entity state).
+	DurationRemaining float32 `json:"duration_remaining,omitempty"`
+}
+
+// TeamVisionCoverage is one team's vision-coverage rollup: how many wards
+// it placed and how many ward-seconds of coverage they provided in total.
+// "Unique" here means each ward's own lifetime is counted once - overlapping
+// coverage from simultaneous wards isn't merged into a single deduplicated
+// time range, which would require spatial reasoning this collector doesn't
+// attempt.
+type TeamVisionCoverage struct {
+	Team             int32   `json:"team"`
+	WardsPlaced      int     `json:"wards_placed"`
+	TotalWardSeconds float32 `json:"total_ward_seconds"`
+}
+
+// VisionResult is the ParseResult payload for the Vision collector.
+type VisionResult struct {
+	Events       []WardEvent                  `json:"events"`
+	TotalEvents  int                           `json:"total_events"`
+	TeamCoverage map[int32]*TeamVisionCoverage `json:"team_coverage"`
+}
+
+// Nominal ward lifetimes in seconds. Manta's dota.Entity accessors have no
+// confirmed field carrying a ward's remaining/total duration (this sandbox
+// has no vendored dota proto source to check against, the same gap flagged
+// for CDOTAUserMsg_MiniKillCamInfo and the Projectiles collector's
+// TotalDistance estimate), so "expired" vs "killed" is inferred by comparing
+// an observed lifetime against these constants instead, and DurationRemaining
+// on a "placed" event is just this constant restated in seconds.
+const (
+	observerWardDurationSeconds = float32(360)
+	sentryWardDurationSeconds   = float32(360)
+	wardExpireToleranceSeconds  = float32(5)
+)
+
+// wardTrack is the in-flight state visionCollector keeps between a ward's
+// creation and its later deletion, keyed by entity index.
+type wardTrack struct {
+	wardType   string
+	team       int32
+	createTick uint32
+}
+
+// visionCollector accumulates VisionResult across the single OnEntity
+// callback RunParse registers for config.Vision.
+type visionCollector struct {
+	config *VisionConfig
+	result *VisionResult
+	tracks map[uint32]*wardTrack
+}
+
+func newVisionCollector(config *VisionConfig) *visionCollector {
+	return &visionCollector{
+		config: config,
+		result: &VisionResult{
+			Events:       make([]WardEvent, 0),
+			TeamCoverage: make(map[int32]*TeamVisionCoverage),
+		},
+		tracks: make(map[uint32]*wardTrack),
+	}
+}
+
+// wardKindFromClass classifies a ward entity's class name, returning "" for
+// anything that isn't an observer or sentry ward.
+func wardKindFromClass(className string) string {
+	switch {
+	case strings.Contains(className, "CDOTA_NPC_Observer_Ward"):
+		return "observer"
+	case strings.Contains(className, "CDOTA_NPC_Sentry_Ward"):
+		return "sentry"
+	default:
+		return ""
+	}
+}
+
+func nominalWardDuration(wardType string) float32 {
+	if wardType == "sentry" {
+		return sentryWardDurationSeconds
+	}
+	return observerWardDurationSeconds
+}
+
+func (c *visionCollector) teamCoverage(team int32) *TeamVisionCoverage {
+	tc, ok := c.result.TeamCoverage[team]
+	if !ok {
+		tc = &TeamVisionCoverage{Team: team}
+		c.result.TeamCoverage[team] = tc
+	}
+	return tc
+}
+
+// onEntity handles both halves of a ward's lifecycle: EntityOpCreated
+// records the placement (X/Y via the same cellX/vecX decoding
+// EntityDeaths uses), EntityOpDeleted closes out the matching track and
+// classifies it as "expired" or "killed" by comparing its observed
+// lifetime against nominalWardDuration.
+func (c *visionCollector) onEntity(parser *manta.Parser, e *manta.Entity, op manta.EntityOp) {
+	if e == nil {
+		return
+	}
+	wardType := wardKindFromClass(e.GetClassName())
+	if wardType == "" {
+		return
+	}
+	index := uint32(e.GetIndex())
+
+	if op.Flag(manta.EntityOpDeleted) {
+		track, ok := c.tracks[index]
+		if !ok {
+			return
+		}
+		delete(c.tracks, index)
+
+		lifetimeSeconds := float32(parser.Tick-track.createTick) / TicksPerSecond
+		stage := "killed"
+		if lifetimeSeconds >= nominalWardDuration(track.wardType)-wardExpireToleranceSeconds {
+			stage = "expired"
+		}
+
+		c.result.Events = append(c.result.Events, WardEvent{
+			Tick:           int(parser.Tick),
+			WardIndex:      int(index),
+			WardType:       track.wardType,
+			LifecycleStage: stage,
+			Team:           track.team,
+		})
+
+		tc := c.teamCoverage(track.team)
+		tc.TotalWardSeconds += lifetimeSeconds
+		return
+	}
+
+	if !op.Flag(manta.EntityOpCreated) {
+		return
+	}
+	if _, exists := c.tracks[index]; exists {
+		return
+	}
+
+	var team int32
+	if t, ok := e.GetInt32("m_iTeamNum"); ok {
+		team = t
+	}
+
+	var placerIndex int
+	var placerHandle int64
+	if handle, ok := e.GetUint64("m_hOwnerEntity"); ok {
+		placerHandle = int64(handle)
+		placerIndex = int(handle & 0x3FFF)
+	}
+
+	var x, y float32
+	if cellX, ok := e.GetUint64("CBodyComponent.m_cellX"); ok {
+		if cellY, ok2 := e.GetUint64("CBodyComponent.m_cellY"); ok2 {
+			if vecX, ok3 := e.GetFloat32("CBodyComponent.m_vecX"); ok3 {
+				if vecY, ok4 := e.GetFloat32("CBodyComponent.m_vecY"); ok4 {
+					x = float32(cellX)*128.0 + vecX - 8192.0
+					y = float32(cellY)*128.0 + vecY - 8192.0
+				}
+			}
+		}
+	}
+
+	c.tracks[index] = &wardTrack{wardType: wardType, team: team, createTick: parser.Tick}
+
+	c.result.Events = append(c.result.Events, WardEvent{
+		Tick:              int(parser.Tick),
+		WardIndex:         int(index),
+		WardType:          wardType,
+		LifecycleStage:    "placed",
+		Team:              team,
+		X:                 x,
+		Y:                 y,
+		PlacerIndex:       placerIndex,
+		PlacerHandle:      placerHandle,
+		DurationRemaining: nominalWardDuration(wardType),
+	})
+
+	tc := c.teamCoverage(team)
+	tc.WardsPlaced++
+}
+
+// finalize fills in GameTime using gameStartTick, the same "known only once
+// parsing finishes" dependency Projectiles/MapEvents already have.
+func (c *visionCollector) finalize(gameStartTick uint32) *VisionResult {
+	for i := range c.result.Events {
+		c.result.Events[i].GameTime = TickToGameTime(uint32(c.result.Events[i].Tick), gameStartTick)
+	}
+	c.result.TotalEvents = len(c.result.Events)
+	return c.result
+}